@@ -0,0 +1,203 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/types"
+)
+
+// cdfQuantiles are the cumulative probabilities (in percent) plotted from raw samples:
+// evenly spaced through the body of the distribution, denser in the tail
+var cdfQuantiles = func() []float64 {
+	var q []float64
+	for p := 0.0; p < 99; p += 1 {
+		q = append(q, p)
+	}
+	for p := 99.0; p < 99.9; p += 0.1 {
+		q = append(q, math.Round(p*10)/10)
+	}
+	return append(q, 99.9, 99.95, 99.99, 100)
+}()
+
+// cdfPoint is one point of a latency CDF: latency in milliseconds and cumulative percent
+type cdfPoint struct {
+	LatencyMs float64
+	Percent   float64
+}
+
+// rawLatencyFile is the subset of the runner's .raw.json.gz format used for CDFs
+type rawLatencyFile struct {
+	LatenciesNs []int64 `json:"latencies_ns"`
+}
+
+// rawLatencyPath returns where the runner writes raw samples for a storage type of a results file
+func rawLatencyPath(inputFile, storageType string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(inputFile, ".gz"), ".json")
+	return fmt.Sprintf("%s_%s.raw.json.gz", base, storageType)
+}
+
+// loadRawLatencies reads raw latency samples exported alongside a results file
+func loadRawLatencies(path string) ([]int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var raw rawLatencyFile
+	if err := json.NewDecoder(gz).Decode(&raw); err != nil {
+		return nil, err
+	}
+	if len(raw.LatenciesNs) == 0 {
+		return nil, fmt.Errorf("no samples in %s", path)
+	}
+	return raw.LatenciesNs, nil
+}
+
+// empiricalCDF computes the CDF of raw samples at cdfQuantiles
+func empiricalCDF(samples []int64) []cdfPoint {
+	sorted := append([]int64(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	points := make([]cdfPoint, 0, len(cdfQuantiles))
+	for _, q := range cdfQuantiles {
+		idx := int(math.Ceil(q/100*float64(len(sorted)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		points = append(points, cdfPoint{LatencyMs: float64(sorted[idx]) / 1e6, Percent: q})
+	}
+	return points
+}
+
+// percentileCDF approximates a CDF from the summary percentiles in a results file
+func percentileCDF(m Metrics) []cdfPoint {
+	candidates := []struct {
+		latency int64
+		percent float64
+	}{
+		{m.MinLatency, 0},
+		{m.P50Latency, 50},
+		{m.P90Latency, 90},
+		{m.P95Latency, 95},
+		{m.P99Latency, 99},
+		{m.P999Latency, 99.9},
+		{m.MaxLatency, 100},
+	}
+
+	var points []cdfPoint
+	for _, c := range candidates {
+		if c.latency > 0 {
+			points = append(points, cdfPoint{LatencyMs: float64(c.latency) / 1e6, Percent: c.percent})
+		}
+	}
+	return points
+}
+
+// storageCDF returns the CDF for one storage type, preferring raw samples and falling
+// back to the summary percentiles. approximate reports whether the fallback was used.
+func (cg *ChartGenerator) storageCDF(storageType string, m Metrics) (points []cdfPoint, approximate bool) {
+	if cg.inputFile == stdinInput {
+		// No file to find raw samples next to
+		return percentileCDF(m), true
+	}
+	path := rawLatencyPath(cg.inputFile, storageType)
+	samples, err := loadRawLatencies(path)
+	if err == nil {
+		fmt.Printf("[INFO] Using %d raw %s samples from %s\n", len(samples), storageType, path)
+		return empiricalCDF(samples), false
+	}
+	return percentileCDF(m), true
+}
+
+func cdfLineData(points []cdfPoint) []opts.LineData {
+	data := make([]opts.LineData, len(points))
+	for i, p := range points {
+		data[i] = opts.LineData{Value: []interface{}{math.Round(p.LatencyMs*1000) / 1000, p.Percent}}
+	}
+	return data
+}
+
+// GenerateLatencyCDF plots the cumulative latency distribution of every storage
+// configuration on the same axes. Raw samples exported with metrics.export_raw are used
+// when present; otherwise the curve is approximated from the reported percentiles.
+func (cg *ChartGenerator) GenerateLatencyCDF() error {
+	points := make([][]cdfPoint, len(cg.results.Storage))
+	approximated := false
+	for i, s := range cg.results.Storage {
+		var approx bool
+		points[i], approx = cg.storageCDF(s.label(), s.Metrics)
+		approximated = approximated || approx
+	}
+
+	subtitle := "Share of operations completed within a latency - further left is better"
+	if approximated {
+		subtitle += " (approximated from percentiles; enable metrics.export_raw for full curves)"
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Latency CDF: " + cg.comparedNames(),
+			Subtitle: subtitle,
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Latency (ms)",
+			Type: "value",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Cumulative %",
+			Min:  0,
+			Max:  100,
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    true,
+			Trigger: "axis",
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: true,
+		}),
+		charts.WithDataZoomOpts(opts.DataZoom{
+			Type:       "inside",
+			XAxisIndex: []int{0},
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Theme: types.ThemeWesteros,
+		}),
+	)
+
+	for i, s := range cg.results.Storage {
+		line.AddSeries(s.Name, cdfLineData(points[i]),
+			charts.WithItemStyleOpts(opts.ItemStyle{Color: seriesColor(i)}),
+		)
+	}
+
+	outputFile := filepath.Join(cg.outputDir, "latency_cdf.html")
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := line.Render(f); err != nil {
+		return err
+	}
+
+	fmt.Printf("[INFO] Latency CDF chart saved: %s\n", outputFile)
+	return nil
+}
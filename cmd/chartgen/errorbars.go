@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// runSpread returns the sample standard deviation of a metric across repeated runs, or
+// false when there are fewer than two runs to compute it from
+func runSpread(repeats []Metrics, value func(Metrics) float64) (float64, bool) {
+	if len(repeats) < 2 {
+		return 0, false
+	}
+
+	var sum float64
+	for _, m := range repeats {
+		sum += value(m)
+	}
+	mean := sum / float64(len(repeats))
+
+	var sq float64
+	for _, m := range repeats {
+		d := value(m) - mean
+		sq += d * d
+	}
+	return math.Sqrt(sq / float64(len(repeats)-1)), true
+}
+
+// errorBar is a ±1 standard deviation indicator drawn over the bar of a category
+type errorBar struct {
+	Category int // index of the category on the x axis
+	Value    float64
+	StdDev   float64
+}
+
+// errorBarRenderItem draws one error bar: a vertical line from value-stddev to
+// value+stddev with a short cap at each end. Its data items are [category, low, high,
+// slot, slots], and api.barLayout gives the offset from the category centre of the bar
+// at position slot of the slots bars sharing the category.
+const errorBarRenderItem = `function (params, api) {
+	var offset = api.barLayout({count: api.value(4)})[api.value(3)].offsetCenter;
+	var low = api.coord([api.value(0), api.value(1)]);
+	var high = api.coord([api.value(0), api.value(2)]);
+	var x = low[0] + offset;
+	var cap = 4;
+	var style = {stroke: '#333', lineWidth: 1.5};
+	return {
+		type: 'group',
+		children: [
+			{type: 'line', shape: {x1: x, y1: low[1], x2: x, y2: high[1]}, style: style},
+			{type: 'line', shape: {x1: x - cap, y1: low[1], x2: x + cap, y2: low[1]}, style: style},
+			{type: 'line', shape: {x1: x - cap, y1: high[1], x2: x + cap, y2: high[1]}, style: style}
+		]
+	};
+}`
+
+// addErrorBars overlays bars on chart as a custom series with the same name as the bar
+// series they belong to, so toggling that series in the legend hides them too. The bar
+// series is the slot-th of slots series in the chart; each error bar is drawn over that
+// series' bar rather than the category centre, which in a grouped chart lies between bars.
+func addErrorBars(chart *charts.Bar, name string, slot, slots int, bars []errorBar) {
+	if len(bars) == 0 {
+		return
+	}
+
+	data := make([]opts.CustomData, len(bars))
+	for i, b := range bars {
+		low := math.Max(0, b.Value-b.StdDev)
+		high := b.Value + b.StdDev
+		data[i] = opts.CustomData{
+			Value: []interface{}{b.Category, math.Round(low*100) / 100, math.Round(high*100) / 100, slot, slots},
+		}
+	}
+
+	custom := charts.NewCustom()
+	custom.AddSeries(name, data,
+		charts.WithCustomChartOpts(opts.CustomChart{RenderItem: opts.FuncOpts(errorBarRenderItem)}),
+		// Map both ends onto the y axis so its extent covers the top of every error bar
+		charts.WithEncodeOpts(opts.Encode{X: 0, Y: []int{1, 2}}),
+	)
+	chart.Overlap(custom)
+}
+
+// repeatCount returns how many repeated runs error bars are computed from, or 0 if any
+// storage configuration has fewer than two
+func (cg *ChartGenerator) repeatCount() int {
+	count := 0
+	for i, s := range cg.results.Storage {
+		if len(s.Repeats) < 2 {
+			return 0
+		}
+		if i == 0 || len(s.Repeats) < count {
+			count = len(s.Repeats)
+		}
+	}
+	return count
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/render"
+	tpls "github.com/go-echarts/go-echarts/v2/templates"
+)
+
+// fragmentsDir is the subdirectory of the output directory fragments are written to
+const fragmentsDir = "fragments"
+
+// fragmentChart is a go-echarts chart that can be rendered without its page
+type fragmentChart interface {
+	Validate()
+	GetAssets() opts.Assets
+}
+
+// chartFragment is one chart of the dashboard, rendered on its own
+type chartFragment struct {
+	name  string // file name without extension, also used in the chart's element ID
+	chart fragmentChart
+}
+
+// fragmentData is the data behind the fragments, for pages that render their own views
+type fragmentData struct {
+	Timestamp  string            `json:"timestamp,omitempty"`
+	Baseline   string            `json:"baseline"`
+	Parameters string            `json:"parameters,omitempty"`
+	Scripts    []string          `json:"scripts"`   // JavaScript the page must load before the chart fragments
+	Fragments  []string          `json:"fragments"` // fragment files, in dashboard order
+	Storage    []fragmentStorage `json:"storage"`
+}
+
+type fragmentStorage struct {
+	Name                string  `json:"name"`
+	Key                 string  `json:"key"`
+	StorageType         string  `json:"storage_type,omitempty"`
+	MountOption         string  `json:"mount_option,omitempty"`
+	DurationSeconds     float64 `json:"duration_seconds"`
+	ThroughputReduction float64 `json:"throughput_reduction_pct"` // against the baseline; 0 for the baseline itself
+	Metrics             Metrics `json:"metrics"`
+}
+
+// echartsFuncMarker matches the markers go-echarts wraps JavaScript functions in, which
+// its own renderers strip from the output
+var echartsFuncMarker = regexp.MustCompile(`(__f__")|("__f__)|(__f__)`)
+
+// ExportFragments writes the dashboard's charts and summary table as HTML fragments, one
+// file each with no <html> wrapper, plus data.json with the numbers behind them, so they
+// can be embedded in another page. That page must load the scripts listed in data.json.
+func (cg *ChartGenerator) ExportFragments() error {
+	dir := filepath.Join(cg.outputDir, fragmentsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create fragments directory: %w", err)
+	}
+
+	throughput := cg.createThroughputChart()
+	throughput.ChartID = "nfsbench_throughput"
+	latency := cg.createLatencyChart()
+	latency.ChartID = "nfsbench_latency"
+	impact := cg.createSummaryChart()
+	impact.ChartID = "nfsbench_impact"
+	duration := cg.createDurationChart()
+	duration.ChartID = "nfsbench_duration"
+	fragments := []chartFragment{
+		{"overhead_gauge", cg.createOverheadGauge()},
+		{"throughput", throughput},
+		{"latency", latency},
+		{"impact", impact},
+		{"duration", duration},
+	}
+
+	data := fragmentData{
+		Timestamp:  cg.results.Timestamp,
+		Baseline:   cg.baseline().Name,
+		Parameters: cg.parametersNote(),
+	}
+	seen := make(map[string]bool)
+	for _, f := range fragments {
+		var buf bytes.Buffer
+		if err := renderFragment(&buf, f.chart); err != nil {
+			return fmt.Errorf("failed to render %s fragment: %w", f.name, err)
+		}
+		file := f.name + ".html"
+		if err := os.WriteFile(filepath.Join(dir, file), buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		data.Fragments = append(data.Fragments, file)
+
+		assets := f.chart.GetAssets()
+		for _, script := range append(assets.JSAssets.Values, assets.CustomizedJSAssets.Values...) {
+			if !seen[script] {
+				seen[script] = true
+				data.Scripts = append(data.Scripts, script)
+			}
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "summary_table.html"), []byte(cg.summaryTableHTML()), 0644); err != nil {
+		return err
+	}
+	data.Fragments = append(data.Fragments, "summary_table.html")
+
+	for _, s := range cg.results.Storage {
+		data.Storage = append(data.Storage, fragmentStorage{
+			Name:                s.Name,
+			Key:                 s.Key,
+			StorageType:         s.StorageType,
+			MountOption:         s.MountOption,
+			DurationSeconds:     float64(s.Duration) / 1000000000,
+			ThroughputReduction: cg.throughputReduction(s),
+			Metrics:             s.Metrics,
+		})
+	}
+
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data.json"), encoded, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("[INFO] %d fragments and data.json saved: %s\n", len(data.Fragments), dir)
+	return nil
+}
+
+// renderFragment renders a chart's element and script without the page around them,
+// using go-echarts' own chart template
+func renderFragment(w io.Writer, chart fragmentChart) error {
+	chart.Validate()
+
+	var buf bytes.Buffer
+	tpl := render.MustTemplate("base", []string{tpls.BaseTpl})
+	if err := tpl.ExecuteTemplate(&buf, "base", chart); err != nil {
+		return err
+	}
+	_, err := w.Write(echartsFuncMarker.ReplaceAll(buf.Bytes(), nil))
+	return err
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// insertModeName names an insert mode in legends
+func insertModeName(mode string) string {
+	return strings.ToUpper(mode)
+}
+
+// insertModeRank orders row-by-row INSERT before COPY
+func insertModeRank(mode string) int {
+	switch mode {
+	case "insert":
+		return 0
+	case "copy":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// insertModeGroups returns the storage configurations and insert modes of a results file
+// from a scenario that compared insert modes, in chart order, with each configuration's
+// result per mode
+func (cg *ChartGenerator) insertModeGroups() (storage []string, modes []string, results map[string]map[string]StorageResult) {
+	results = make(map[string]map[string]StorageResult)
+	for _, s := range cg.results.Storage {
+		if s.InsertMode == "" {
+			continue
+		}
+		name := storageDisplayName(s.storageKey(), s.MountOption)
+		if results[name] == nil {
+			storage = append(storage, name)
+			results[name] = make(map[string]StorageResult)
+		}
+		results[name][s.InsertMode] = s
+		if !containsMode(modes, s.InsertMode) {
+			modes = append(modes, s.InsertMode)
+		}
+	}
+	return storage, modes, results
+}
+
+func containsMode(modes []string, mode string) bool {
+	for _, m := range modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateInsertModeChart renders throughput as bars grouped by storage configuration,
+// with a series per insert mode, and P95 latency per mode as lines, so INSERT and COPY on
+// direct storage and NFS show side by side. The subtitle gives each mode's throughput
+// reduction on NFS, since batching rows into one COPY can hide or expose NFS latency
+// differently than row-by-row inserts.
+func (cg *ChartGenerator) GenerateInsertModeChart() error {
+	storage, modes, results := cg.insertModeGroups()
+	if len(modes) == 0 {
+		return fmt.Errorf("no results with an insert mode; set insert_mode to a list such as [insert, copy] and use the scenario's combined results file")
+	}
+
+	subtitle := "Ops/sec (bars, higher is better) and P95 latency in ms (lines, lower is better)"
+	if len(storage) > 1 {
+		var parts []string
+		for _, mode := range modes {
+			base, ok := results[storage[0]][mode]
+			if !ok || base.Metrics.OperationsPerSecond == 0 {
+				continue
+			}
+			for _, name := range storage[1:] {
+				if s, ok := results[name][mode]; ok {
+					reduction := (base.Metrics.OperationsPerSecond - s.Metrics.OperationsPerSecond) / base.Metrics.OperationsPerSecond * 100
+					parts = append(parts, fmt.Sprintf("%s on %s %.1f%% slower", insertModeName(mode), name, reduction))
+				}
+			}
+		}
+		if len(parts) > 0 {
+			subtitle += "\nvs " + storage[0] + ": " + strings.Join(parts, ", ")
+		}
+	}
+	if note := cg.parametersNote(); note != "" {
+		subtitle += "\n" + note
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    "INSERT vs COPY by Storage",
+			Subtitle: subtitle,
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Ops/sec",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    true,
+			Trigger: "axis",
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: true,
+		}),
+	)
+	bar.ExtendYAxis(opts.YAxis{Name: "P95 latency (ms)"})
+	bar.SetXAxis(storage)
+
+	line := charts.NewLine()
+	line.SetXAxis(storage)
+	for i, mode := range modes {
+		var throughput []opts.BarData
+		var p95 []opts.LineData
+		for _, name := range storage {
+			s := results[name][mode]
+			throughput = append(throughput, opts.BarData{Value: math.Round(s.Metrics.OperationsPerSecond*10) / 10})
+			p95 = append(p95, opts.LineData{Value: math.Round(float64(s.Metrics.P95Latency)/1000000*100) / 100})
+		}
+		color := opts.ItemStyle{Color: seriesColor(i)}
+		bar.AddSeries(insertModeName(mode)+" ops/sec", throughput, charts.WithItemStyleOpts(color))
+		line.AddSeries(insertModeName(mode)+" P95 (ms)", p95,
+			charts.WithLineChartOpts(opts.LineChart{YAxisIndex: 1}),
+			charts.WithItemStyleOpts(color))
+	}
+	bar.Overlap(line)
+
+	outputFile := filepath.Join(cg.outputDir, "insert_modes.html")
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := bar.Render(f); err != nil {
+		return err
+	}
+
+	fmt.Printf("[INFO] Insert mode comparison chart saved: %s\n", outputFile)
+	return nil
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/json"
+	"bytes"
 	"flag"
 	"fmt"
 	"io/fs"
@@ -16,30 +16,9 @@ import (
 	"github.com/go-echarts/go-echarts/v2/components"
 	"github.com/go-echarts/go-echarts/v2/opts"
 	"github.com/go-echarts/go-echarts/v2/types"
-)
-
-type BenchmarkResults struct {
-	Metadata struct {
-		Timestamp    string `json:"timestamp"`
-		DatabaseType string `json:"database_type"`
-		Scenario     string `json:"scenario"`
-		Version      string `json:"version"`
-	} `json:"metadata"`
-	Direct DirectResults `json:"direct"`
-	NFS    NFSResults    `json:"nfs"`
-}
-
-type DirectResults struct {
-	Duration int64       `json:"Duration"`
-	Metrics  Metrics     `json:"Metrics"`
-	DBStats  DatabaseStats `json:"DBStats"`
-}
 
-type NFSResults struct {
-	Duration int64       `json:"Duration"`
-	Metrics  Metrics     `json:"Metrics"`
-	DBStats  DatabaseStats `json:"DBStats"`
-}
+	"github.com/l22io/nfsvsdirectbench/internal/benchmark"
+)
 
 type Metrics struct {
 	TotalOperations    int64   `json:"total_operations"`
@@ -51,6 +30,7 @@ type Metrics struct {
 	P90Latency         int64   `json:"p90_latency"`
 	P95Latency         int64   `json:"p95_latency"`
 	P99Latency         int64   `json:"p99_latency"`
+	P999Latency        int64   `json:"p999_latency"`
 }
 
 type DatabaseStats struct {
@@ -61,14 +41,22 @@ type DatabaseStats struct {
 
 type ChartGenerator struct {
 	results BenchmarkResults
+	inputFile string
 	outputDir string
 }
 
+// stdinInput is the -input value that reads results from standard input
+const stdinInput = "-"
+
 func main() {
 	var (
-		inputFile = flag.String("input", "", "Path to JSON results file (required)")
+		inputFile = flag.String("input", "", "Path to JSON results file, or - for stdin (required)")
+		inputs    = flag.String("inputs", "", "Directory or glob of result files for the trend chart, or the scenario chart with -chart scenarios")
 		outputDir = flag.String("output", "", "Output directory for charts (default: same as input file)")
-		chartType = flag.String("chart", "all", "Chart type: throughput, latency, combined, dashboard, all")
+		chartType = flag.String("chart", "all", "Chart type: throughput, latency, combined, dashboard, cdf, mountopts, percentiles, nfsversions, insertmodes, all")
+		format    = flag.String("format", "html", "Output format: html, png, svg, fragments")
+		width     = flag.Int("width", 1200, "Image width in pixels for png/svg output")
+		height    = flag.Int("height", 600, "Image height in pixels for png/svg output")
 		help      = flag.Bool("help", false, "Show help message")
 	)
 	flag.Parse()
@@ -78,9 +66,36 @@ func main() {
 		return
 	}
 
+	if *inputs != "" {
+		files, err := resolveInputs(*inputs)
+		if err != nil {
+			log.Fatalf("[ERROR] Failed to resolve inputs: %v", err)
+		}
+		if *outputDir == "" {
+			*outputDir = "."
+		}
+
+		if *chartType == "scenarios" {
+			fmt.Printf("[INFO] Generating scenario comparison from %d files...\n", len(files))
+			if err := GenerateScenarioComparison(files, *outputDir); err != nil {
+				log.Fatalf("[ERROR] Failed to generate scenario comparison: %v", err)
+			}
+			fmt.Println("[SUCCESS] Charts generated successfully!")
+			return
+		}
+
+		fmt.Printf("[INFO] Generating trend chart from %d files...\n", len(files))
+		if err := GenerateTrendChart(files, *outputDir); err != nil {
+			log.Fatalf("[ERROR] Failed to generate trend chart: %v", err)
+		}
+
+		fmt.Println("[SUCCESS] Charts generated successfully!")
+		return
+	}
+
 	if *inputFile == "" {
 		// Try to find latest results file
-		latest, err := findLatestResults()
+		latest, err := findLatestResults("./results")
 		if err != nil {
 		log.Fatalf("[ERROR] No input file specified and couldn't find latest results: %v", err)
 		}
@@ -89,7 +104,11 @@ func main() {
 	}
 
 	if *outputDir == "" {
-		*outputDir = filepath.Dir(*inputFile)
+		if *inputFile == stdinInput {
+			*outputDir = "."
+		} else {
+			*outputDir = filepath.Dir(*inputFile)
+		}
 	}
 
 	generator, err := NewChartGenerator(*inputFile, *outputDir)
@@ -99,6 +118,24 @@ func main() {
 
 	fmt.Println("[INFO] Generating charts...")
 
+	switch *format {
+	case "html":
+	case "png", "svg":
+		if err := generator.ExportStaticCharts(*chartType, *format, *width, *height); err != nil {
+			log.Fatalf("[ERROR] Failed to export charts: %v", err)
+		}
+		fmt.Println("[SUCCESS] Charts generated successfully!")
+		return
+	case "fragments":
+		if err := generator.ExportFragments(); err != nil {
+			log.Fatalf("[ERROR] Failed to export fragments: %v", err)
+		}
+		fmt.Println("[SUCCESS] Charts generated successfully!")
+		return
+	default:
+		log.Fatalf("[ERROR] Unknown output format: %s", *format)
+	}
+
 	switch *chartType {
 	case "throughput":
 		err = generator.GenerateThroughputChart()
@@ -108,6 +145,16 @@ func main() {
 		err = generator.GenerateCombinedChart()
 	case "dashboard":
 		err = generator.GenerateDashboard()
+	case "cdf":
+		err = generator.GenerateLatencyCDF()
+	case "mountopts":
+		err = generator.GenerateMountOptionComparison()
+	case "percentiles":
+		err = generator.GeneratePercentileOverheadChart()
+	case "nfsversions":
+		err = generator.GenerateNFSVersionChart()
+	case "insertmodes":
+		err = generator.GenerateInsertModeChart()
 	case "all":
 		err = generator.GenerateAllCharts()
 	default:
@@ -127,28 +174,66 @@ func showUsage() {
 Generate interactive HTML charts from NFS vs Direct Storage benchmark results.
 
 Options:
-    -input FILE       Path to JSON results file (if not provided, finds latest)
-    -output DIR       Output directory for charts (default: same as input file)
-    -chart TYPE       Chart type: throughput, latency, combined, dashboard, all (default: all)
+    -input FILE       Path to JSON results file, or - to read stdin (if not provided, finds latest)
+    -inputs PATTERN   Directory or glob of result files; renders the trend chart, or
+                      with -chart scenarios the scenario comparison
+    -output DIR       Output directory for charts (default: same as input file, or the
+                      current directory for stdin)
+    -chart TYPE       Chart type: throughput, latency, combined, dashboard, cdf, mountopts,
+                      percentiles, nfsversions, insertmodes, all (default: all)
+    -format FORMAT    Output format: html, png, svg, fragments (default: html)
+    -width PX         Image width for png/svg output (default: 1200)
+    -height PX        Image height for png/svg output (default: 600)
     -help            Show this help message
 
 Examples:
     %s -input results.json
     %s -input results.json -chart throughput -output charts/
     %s -chart dashboard
+    %s -input results.json -format png -width 1600 -height 900
+    %s -input results.json -format fragments -output status-page/
+    %s -inputs 'results/*/postgresql_heavy_inserts.json'
+    %s -inputs results/run_20240101_120000 -chart scenarios
+    jq 'del(.direct.DBStats, .nfs.DBStats)' results.json | %s -input -
 
 Chart Types:
     throughput - Operations per second comparison
     latency    - Latency distribution (P50, P90, P95, P99)
     combined   - Side-by-side throughput and key latency metrics
     dashboard  - Comprehensive view with all metrics
+    cdf        - Cumulative latency distribution (uses .raw.json.gz samples
+                 when present, otherwise approximated from percentiles)
+    mountopts  - Grouped throughput and P95 latency of direct and every NFS
+                 mount option variant in the results file
+    percentiles - Latency overhead vs direct at P50, P90, P95, P99, and P99.9,
+                 showing how the overhead grows toward the tail
+    nfsversions - Throughput and P50/P99 latency of direct and each NFS version
+                 (v3, v4.0, v4.1, v4.2) in the results file; 'all' includes it
+                 when the results record NFS versions
+    insertmodes - Throughput and P95 latency of INSERT and COPY grouped by
+                 storage, from a scenario with insert_mode: [insert, copy];
+                 'all' includes it when the results record insert modes
     all        - Generate all chart types (default)
+    trend      - Overhead trend across runs (selected by -inputs)
+    scenarios  - Throughput reduction and P95 latency increase of every scenario
+                 in a run directory (-inputs), side by side, hardest hit first
+
+
+Static Export:
+    png and svg formats support the throughput and latency charts (and 'all',
+    which exports both). Other chart types are HTML-only.
 
-`, os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+Fragments:
+    The fragments format writes the dashboard's charts and summary table to
+    DIR/fragments as HTML snippets without <html> wrappers, one file each, plus
+    data.json with the underlying numbers and the scripts the embedding page
+    must load. -chart is ignored.
+
+`, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
 }
 
-func findLatestResults() (string, error) {
-	resultsDir := "./results"
+// findLatestResults returns the most recently written results file under resultsDir
+func findLatestResults(resultsDir string) (string, error) {
 	if _, err := os.Stat(resultsDir); os.IsNotExist(err) {
 		return "", fmt.Errorf("results directory not found: %s", resultsDir)
 	}
@@ -158,7 +243,7 @@ func findLatestResults() (string, error) {
 		if err != nil {
 			return err
 		}
-		if !d.IsDir() && strings.HasSuffix(path, ".json") {
+		if !d.IsDir() && isResultsFile(path) {
 			jsonFiles = append(jsonFiles, path)
 		}
 		return nil
@@ -181,15 +266,32 @@ func findLatestResults() (string, error) {
 	return jsonFiles[0], nil
 }
 
+// isResultsFile reports whether a path names a results file, plain or gzipped (raw
+// latency exports and the progress file a run rewrites after every task are not
+// results files)
+func isResultsFile(path string) bool {
+	if strings.HasSuffix(path, ".raw.json.gz") || filepath.Base(path) == benchmark.ProgressFile {
+		return false
+	}
+	return strings.HasSuffix(path, ".json") || strings.HasSuffix(path, ".json.gz")
+}
+
+// NewChartGenerator loads a results file, or standard input when inputFile is "-"
 func NewChartGenerator(inputFile, outputDir string) (*ChartGenerator, error) {
-	data, err := os.ReadFile(inputFile)
+	var data []byte
+	var err error
+	if inputFile == stdinInput {
+		data, err = benchmark.ReadResults(os.Stdin)
+	} else {
+		data, err = benchmark.ReadResultsFile(inputFile)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read input file: %w", err)
 	}
 
-	var results BenchmarkResults
-	if err := json.Unmarshal(data, &results); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	results, err := parseBenchmarkResults(data)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
@@ -198,6 +300,7 @@ func NewChartGenerator(inputFile, outputDir string) (*ChartGenerator, error) {
 
 	return &ChartGenerator{
 		results:   results,
+		inputFile: inputFile,
 		outputDir: outputDir,
 	}, nil
 }
@@ -205,10 +308,6 @@ func NewChartGenerator(inputFile, outputDir string) (*ChartGenerator, error) {
 func (cg *ChartGenerator) GenerateThroughputChart() error {
 	bar := charts.NewBar()
 	bar.SetGlobalOptions(
-		charts.WithTitleOpts(opts.Title{
-			Title:    "Throughput Comparison: NFS vs Direct Storage",
-			Subtitle: "Operations per second - Higher is Better",
-		}),
 		charts.WithYAxisOpts(opts.YAxis{
 			Name: "Operations per Second",
 		}),
@@ -220,22 +319,36 @@ func (cg *ChartGenerator) GenerateThroughputChart() error {
 		}),
 	)
 
-	// Add data
-	directOps := cg.results.Direct.Metrics.OperationsPerSecond
-	nfsOps := cg.results.NFS.Metrics.OperationsPerSecond
-	
-	bar.SetXAxis([]string{"Direct Storage", "NFS Storage"}).
-		AddSeries("Throughput", []opts.BarData{
-			{Value: math.Round(directOps*10)/10, ItemStyle: &opts.ItemStyle{Color: "#007AFF"}},
-			{Value: math.Round(nfsOps*10)/10, ItemStyle: &opts.ItemStyle{Color: "#FF6B35"}},
+	// One bar per storage configuration, with error bars when all were repeated
+	var data []opts.BarData
+	var bars []errorBar
+	repeats := cg.repeatCount()
+	ops := func(m Metrics) float64 { return m.OperationsPerSecond }
+	for i, s := range cg.results.Storage {
+		data = append(data, opts.BarData{
+			Value:     math.Round(s.Metrics.OperationsPerSecond*10) / 10,
+			ItemStyle: &opts.ItemStyle{Color: seriesColor(i)},
 		})
+		if repeats > 0 {
+			dev, _ := runSpread(s.Repeats, ops)
+			bars = append(bars, errorBar{Category: i, Value: s.Metrics.OperationsPerSecond, StdDev: dev})
+		}
+	}
+	bar.SetXAxis(cg.storageNames()).
+		AddSeries("Throughput", data)
+	addErrorBars(bar, "Throughput", 0, 1, bars)
 
-	// Calculate performance difference
-	diff := ((directOps - nfsOps) / directOps) * 100
+	subtitle := cg.throughputSubtitle()
+	if repeats > 0 {
+		subtitle += fmt.Sprintf(" (error bars: ±1 stddev across %d runs)", repeats)
+	}
+	if note := cg.parametersNote(); note != "" {
+		subtitle += "\n" + note
+	}
 	bar.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{
-			Title:    "Throughput Comparison: NFS vs Direct Storage",
-			Subtitle: fmt.Sprintf("Operations per second - NFS is %.1f%% slower", diff),
+			Title:    "Throughput Comparison: " + cg.comparedNames(),
+			Subtitle: subtitle,
 		}),
 	)
 
@@ -255,12 +368,33 @@ func (cg *ChartGenerator) GenerateThroughputChart() error {
 	return nil
 }
 
+// latencyLabels and latencyValues are the latency figures compared by the latency charts,
+// in milliseconds
+var (
+	latencyLabels = []string{"Average", "P50", "P90", "P95", "P99"}
+	latencyValues = []func(Metrics) float64{
+		func(m Metrics) float64 { return float64(m.AverageLatency) / 1000000 },
+		func(m Metrics) float64 { return float64(m.P50Latency) / 1000000 },
+		func(m Metrics) float64 { return float64(m.P90Latency) / 1000000 },
+		func(m Metrics) float64 { return float64(m.P95Latency) / 1000000 },
+		func(m Metrics) float64 { return float64(m.P99Latency) / 1000000 },
+	}
+)
+
 func (cg *ChartGenerator) GenerateLatencyChart() error {
 	bar := charts.NewBar()
+	subtitle := "Response time in milliseconds - Lower is Better"
+	repeats := cg.repeatCount()
+	if repeats > 0 {
+		subtitle += fmt.Sprintf(" (error bars: ±1 stddev across %d runs)", repeats)
+	}
+	if note := cg.parametersNote(); note != "" {
+		subtitle += "\n" + note
+	}
 	bar.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{
-			Title:    "Latency Distribution: NFS vs Direct Storage",
-			Subtitle: "Response time in milliseconds - Lower is Better",
+			Title:    "Latency Distribution: " + cg.comparedNames(),
+			Subtitle: subtitle,
 		}),
 		charts.WithYAxisOpts(opts.YAxis{
 			Name: "Latency (milliseconds)",
@@ -273,48 +407,27 @@ func (cg *ChartGenerator) GenerateLatencyChart() error {
 		}),
 	)
 
-	// Convert nanoseconds to milliseconds
-	directMetrics := []float64{
-		float64(cg.results.Direct.Metrics.AverageLatency) / 1000000,
-		float64(cg.results.Direct.Metrics.P50Latency) / 1000000,
-		float64(cg.results.Direct.Metrics.P90Latency) / 1000000,
-		float64(cg.results.Direct.Metrics.P95Latency) / 1000000,
-		float64(cg.results.Direct.Metrics.P99Latency) / 1000000,
-	}
-
-	nfsMetrics := []float64{
-		float64(cg.results.NFS.Metrics.AverageLatency) / 1000000,
-		float64(cg.results.NFS.Metrics.P50Latency) / 1000000,
-		float64(cg.results.NFS.Metrics.P90Latency) / 1000000,
-		float64(cg.results.NFS.Metrics.P95Latency) / 1000000,
-		float64(cg.results.NFS.Metrics.P99Latency) / 1000000,
-	}
-
-	labels := []string{"Average", "P50", "P90", "P95", "P99"}
-
-	bar.SetXAxis(labels)
-
-	// Add Direct Storage series
-	var directData []opts.BarData
-	for _, val := range directMetrics {
-		directData = append(directData, opts.BarData{
-			Value: math.Round(val*10)/10,
-			ItemStyle: &opts.ItemStyle{Color: "#007AFF"},
-		})
-	}
-
-	// Add NFS Storage series
-	var nfsData []opts.BarData
-	for _, val := range nfsMetrics {
-		nfsData = append(nfsData, opts.BarData{
-			Value: math.Round(val*10)/10,
-			ItemStyle: &opts.ItemStyle{Color: "#FF6B35"},
-		})
+	bar.SetXAxis(latencyLabels)
+
+	// One series per storage configuration, with error bars when all were repeated
+	for i, s := range cg.results.Storage {
+		var data []opts.BarData
+		var bars []errorBar
+		for j := range latencyLabels {
+			val := latencyValues[j](s.Metrics)
+			data = append(data, opts.BarData{
+				Value:     math.Round(val*10) / 10,
+				ItemStyle: &opts.ItemStyle{Color: seriesColor(i)},
+			})
+			if repeats > 0 {
+				dev, _ := runSpread(s.Repeats, latencyValues[j])
+				bars = append(bars, errorBar{Category: j, Value: val, StdDev: dev})
+			}
+		}
+		bar.AddSeries(s.Name, data)
+		addErrorBars(bar, s.Name, i, len(cg.results.Storage), bars)
 	}
 
-	bar.AddSeries("Direct Storage", directData).
-		AddSeries("NFS Storage", nfsData)
-
 	outputFile := filepath.Join(cg.outputDir, "latency_chart.html")
 	f, err := os.Create(outputFile)
 	if err != nil {
@@ -346,14 +459,15 @@ func (cg *ChartGenerator) GenerateCombinedChart() error {
 		}),
 	)
 
-	directOps := cg.results.Direct.Metrics.OperationsPerSecond
-	nfsOps := cg.results.NFS.Metrics.OperationsPerSecond
-
-	throughputBar.SetXAxis([]string{"Direct", "NFS"}).
-		AddSeries("Throughput", []opts.BarData{
-			{Value: math.Round(directOps*10)/10, ItemStyle: &opts.ItemStyle{Color: "#007AFF"}},
-			{Value: math.Round(nfsOps*10)/10, ItemStyle: &opts.ItemStyle{Color: "#FF6B35"}},
+	var throughput []opts.BarData
+	for i, s := range cg.results.Storage {
+		throughput = append(throughput, opts.BarData{
+			Value:     math.Round(s.Metrics.OperationsPerSecond*10) / 10,
+			ItemStyle: &opts.ItemStyle{Color: seriesColor(i)},
 		})
+	}
+	throughputBar.SetXAxis(cg.storageNames()).
+		AddSeries("Throughput", throughput)
 
 	// Create key latency chart
 	latencyBar := charts.NewBar()
@@ -369,20 +483,15 @@ func (cg *ChartGenerator) GenerateCombinedChart() error {
 		}),
 	)
 
-	avgDirect := float64(cg.results.Direct.Metrics.AverageLatency) / 1000000
-	avgNFS := float64(cg.results.NFS.Metrics.AverageLatency) / 1000000
-	p95Direct := float64(cg.results.Direct.Metrics.P95Latency) / 1000000
-	p95NFS := float64(cg.results.NFS.Metrics.P95Latency) / 1000000
-
-	latencyBar.SetXAxis([]string{"Average", "P95"}).
-		AddSeries("Direct", []opts.BarData{
-			{Value: math.Round(avgDirect*10)/10, ItemStyle: &opts.ItemStyle{Color: "#007AFF"}},
-			{Value: math.Round(p95Direct*10)/10, ItemStyle: &opts.ItemStyle{Color: "#007AFF"}},
-		}).
-		AddSeries("NFS", []opts.BarData{
-			{Value: math.Round(avgNFS*10)/10, ItemStyle: &opts.ItemStyle{Color: "#FF6B35"}},
-			{Value: math.Round(p95NFS*10)/10, ItemStyle: &opts.ItemStyle{Color: "#FF6B35"}},
+	latencyBar.SetXAxis([]string{"Average", "P95"})
+	for i, s := range cg.results.Storage {
+		avg := float64(s.Metrics.AverageLatency) / 1000000
+		p95 := float64(s.Metrics.P95Latency) / 1000000
+		latencyBar.AddSeries(s.Name, []opts.BarData{
+			{Value: math.Round(avg*10) / 10, ItemStyle: &opts.ItemStyle{Color: seriesColor(i)}},
+			{Value: math.Round(p95*10) / 10, ItemStyle: &opts.ItemStyle{Color: seriesColor(i)}},
 		})
+	}
 
 	page.AddCharts(throughputBar, latencyBar)
 
@@ -418,22 +527,26 @@ func (cg *ChartGenerator) GenerateDashboard() error {
 	// 4. Duration comparison
 	durationChart := cg.createDurationChart()
 
+	// 5. Overhead at a glance, shown first
+	overheadGauge := cg.createOverheadGauge()
+
 	page.AddCharts(
+		overheadGauge,
 		throughputChart,
 		latencyChart,
 		summaryChart,
 		durationChart,
 	)
 
-	outputFile := filepath.Join(cg.outputDir, "dashboard.html")
-	f, err := os.Create(outputFile)
-	if err != nil {
+	// The numeric table is plain HTML rather than a chart, so it is added to the
+	// rendered page
+	var buf bytes.Buffer
+	if err := page.Render(&buf); err != nil {
 		return err
 	}
-	defer f.Close()
 
-	err = page.Render(f)
-	if err != nil {
+	outputFile := filepath.Join(cg.outputDir, "dashboard.html")
+	if err := os.WriteFile(outputFile, insertBeforeBodyEnd(buf.Bytes(), cg.summaryTableHTML()), 0644); err != nil {
 		return err
 	}
 
@@ -449,14 +562,15 @@ func (cg *ChartGenerator) createThroughputChart() *charts.Bar {
 		}),
 	)
 
-	directOps := cg.results.Direct.Metrics.OperationsPerSecond
-	nfsOps := cg.results.NFS.Metrics.OperationsPerSecond
-
-	bar.SetXAxis([]string{"Direct Storage", "NFS Storage"}).
-		AddSeries("Ops/sec", []opts.BarData{
-			{Value: math.Round(directOps*10)/10, ItemStyle: &opts.ItemStyle{Color: "#007AFF"}},
-			{Value: math.Round(nfsOps*10)/10, ItemStyle: &opts.ItemStyle{Color: "#FF6B35"}},
+	var data []opts.BarData
+	for i, s := range cg.results.Storage {
+		data = append(data, opts.BarData{
+			Value:     math.Round(s.Metrics.OperationsPerSecond*10) / 10,
+			ItemStyle: &opts.ItemStyle{Color: seriesColor(i)},
 		})
+	}
+	bar.SetXAxis(cg.storageNames()).
+		AddSeries("Ops/sec", data)
 
 	return bar
 }
@@ -471,62 +585,94 @@ func (cg *ChartGenerator) createLatencyChart() *charts.Bar {
 	)
 
 	labels := []string{"Average", "P90", "P95", "P99"}
-	
-	directLatencies := []float64{
-		float64(cg.results.Direct.Metrics.AverageLatency) / 1000000,
-		float64(cg.results.Direct.Metrics.P90Latency) / 1000000,
-		float64(cg.results.Direct.Metrics.P95Latency) / 1000000,
-		float64(cg.results.Direct.Metrics.P99Latency) / 1000000,
-	}
-
-	nfsLatencies := []float64{
-		float64(cg.results.NFS.Metrics.AverageLatency) / 1000000,
-		float64(cg.results.NFS.Metrics.P90Latency) / 1000000,
-		float64(cg.results.NFS.Metrics.P95Latency) / 1000000,
-		float64(cg.results.NFS.Metrics.P99Latency) / 1000000,
-	}
+	values := []func(Metrics) float64{latencyValues[0], latencyValues[2], latencyValues[3], latencyValues[4]}
 
-	var directData, nfsData []opts.BarData
-	for _, val := range directLatencies {
-		directData = append(directData, opts.BarData{Value: math.Round(val*10)/10})
-	}
-	for _, val := range nfsLatencies {
-		nfsData = append(nfsData, opts.BarData{Value: math.Round(val*10)/10})
+	bar.SetXAxis(labels)
+	for _, s := range cg.results.Storage {
+		var data []opts.BarData
+		for _, value := range values {
+			data = append(data, opts.BarData{Value: math.Round(value(s.Metrics)*10) / 10})
+		}
+		bar.AddSeries(s.Name, data)
 	}
 
-	bar.SetXAxis(labels).
-		AddSeries("Direct", directData).
-		AddSeries("NFS", nfsData)
-
 	return bar
 }
 
 func (cg *ChartGenerator) createSummaryChart() *charts.Bar {
-	// Performance impact summary
+	// Performance impact of each configuration against the baseline
 	bar := charts.NewBar()
 	bar.SetGlobalOptions(
 		charts.WithTitleOpts(opts.Title{
-			Title: "Performance Impact Summary",
+			Title:    "Performance Impact Summary",
+			Subtitle: "Overhead vs " + cg.baseline().Name,
 		}),
+		charts.WithLegendOpts(opts.Legend{Show: true}),
 	)
 
-	directOps := cg.results.Direct.Metrics.OperationsPerSecond
-	nfsOps := cg.results.NFS.Metrics.OperationsPerSecond
-	throughputOverhead := ((directOps - nfsOps) / directOps) * 100
+	baseLatency := float64(cg.baseline().Metrics.AverageLatency) / 1000000
 
-	directLatency := float64(cg.results.Direct.Metrics.AverageLatency) / 1000000
-	nfsLatency := float64(cg.results.NFS.Metrics.AverageLatency) / 1000000
-	latencyOverhead := ((nfsLatency - directLatency) / directLatency) * 100
-
-	bar.SetXAxis([]string{"Throughput Reduction", "Latency Increase"}).
-		AddSeries("NFS Overhead (%)", []opts.BarData{
-			{Value: math.Round(throughputOverhead*10)/10, ItemStyle: &opts.ItemStyle{Color: "#FF6B35"}},
-			{Value: math.Round(latencyOverhead*10)/10, ItemStyle: &opts.ItemStyle{Color: "#FF6B35"}},
+	bar.SetXAxis([]string{"Throughput Reduction", "Latency Increase"})
+	for i, s := range cg.comparisons() {
+		latency := float64(s.Metrics.AverageLatency) / 1000000
+		latencyOverhead := ((latency - baseLatency) / baseLatency) * 100
+		color := &opts.ItemStyle{Color: seriesColor(i + 1)}
+		bar.AddSeries(s.Name+" Overhead (%)", []opts.BarData{
+			{Value: math.Round(cg.throughputReduction(s)*10) / 10, ItemStyle: color},
+			{Value: math.Round(latencyOverhead*10) / 10, ItemStyle: color},
 		})
+	}
 
 	return bar
 }
 
+// worstThroughputReduction returns the configuration with the largest throughput
+// reduction against the baseline, or false if there is nothing to compare
+func (cg *ChartGenerator) worstThroughputReduction() (StorageResult, float64, bool) {
+	var worst StorageResult
+	reduction, found := 0.0, false
+	for _, s := range cg.comparisons() {
+		if r := cg.throughputReduction(s); !found || r > reduction {
+			worst, reduction, found = s, r, true
+		}
+	}
+	return worst, reduction, found
+}
+
+// overheadGaugeID is the chart ID of the overhead gauge, fixed so its color bands can be
+// applied by script: go-echarts has no option for gauge axis line colors
+const overheadGaugeID = "nfs_overhead_gauge"
+
+func (cg *ChartGenerator) createOverheadGauge() *charts.Gauge {
+	worst, overhead, _ := cg.worstThroughputReduction()
+
+	// The dial runs from 0 to 100; NFS outperforming direct pins it at 0
+	value := math.Max(0, math.Min(100, math.Round(overhead*10)/10))
+
+	subtitle := fmt.Sprintf("%.1f%% (green <10%%, yellow <30%%, red otherwise)", overhead)
+	if len(cg.comparisons()) > 1 {
+		subtitle = fmt.Sprintf("Worst: %s, %s", worst.Name, subtitle)
+	}
+
+	gauge := charts.NewGauge()
+	gauge.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			ChartID: overheadGaugeID,
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "NFS Throughput Reduction",
+			Subtitle: subtitle,
+		}),
+	)
+	gauge.AddSeries("NFS Overhead (%)", []opts.GaugeData{{Name: "Throughput Reduction", Value: value}})
+	gauge.AddJSFuncs(fmt.Sprintf(`goecharts_%s.setOption({series: [{
+		axisLine: {lineStyle: {width: 20, color: [[0.1, '#28a745'], [0.3, '#ffc107'], [1, '#dc3545']]}},
+		detail: {formatter: '{value}%%'}
+	}]});`, overheadGaugeID))
+
+	return gauge
+}
+
 func (cg *ChartGenerator) createDurationChart() *charts.Bar {
 	bar := charts.NewBar()
 	bar.SetGlobalOptions(
@@ -535,14 +681,16 @@ func (cg *ChartGenerator) createDurationChart() *charts.Bar {
 		}),
 	)
 
-	directDuration := float64(cg.results.Direct.Duration) / 1000000000 // Convert to seconds
-	nfsDuration := float64(cg.results.NFS.Duration) / 1000000000
-
-	bar.SetXAxis([]string{"Direct Storage", "NFS Storage"}).
-		AddSeries("Duration (seconds)", []opts.BarData{
-			{Value: math.Round(directDuration*10)/10, ItemStyle: &opts.ItemStyle{Color: "#28a745"}},
-			{Value: math.Round(nfsDuration*10)/10, ItemStyle: &opts.ItemStyle{Color: "#dc3545"}},
+	var data []opts.BarData
+	for i, s := range cg.results.Storage {
+		duration := float64(s.Duration) / 1000000000 // Convert to seconds
+		data = append(data, opts.BarData{
+			Value:     math.Round(duration*10) / 10,
+			ItemStyle: &opts.ItemStyle{Color: seriesColor(i)},
 		})
+	}
+	bar.SetXAxis(cg.storageNames()).
+		AddSeries("Duration (seconds)", data)
 
 	return bar
 }
@@ -564,5 +712,29 @@ func (cg *ChartGenerator) GenerateAllCharts() error {
 		return fmt.Errorf("failed to generate dashboard: %w", err)
 	}
 
+	if err := cg.GenerateLatencyCDF(); err != nil {
+		return fmt.Errorf("failed to generate latency CDF: %w", err)
+	}
+
+	if err := cg.GenerateMountOptionComparison(); err != nil {
+		return fmt.Errorf("failed to generate mount option comparison: %w", err)
+	}
+
+	if err := cg.GeneratePercentileOverheadChart(); err != nil {
+		return fmt.Errorf("failed to generate percentile overhead chart: %w", err)
+	}
+
+	if len(cg.versionedStorage()) > 0 {
+		if err := cg.GenerateNFSVersionChart(); err != nil {
+			return fmt.Errorf("failed to generate NFS version chart: %w", err)
+		}
+	}
+
+	if _, modes, _ := cg.insertModeGroups(); len(modes) > 0 {
+		if err := cg.GenerateInsertModeChart(); err != nil {
+			return fmt.Errorf("failed to generate insert mode chart: %w", err)
+		}
+	}
+
 	return nil
 }
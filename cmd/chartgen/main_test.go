@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/benchmark"
+)
+
+func TestFindLatestResultsSkipsProgress(t *testing.T) {
+	resultsDir := t.TempDir()
+	runDir := filepath.Join(resultsDir, "run_1")
+	if err := os.Mkdir(runDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	results := filepath.Join(runDir, "postgresql_heavy_inserts.json")
+	if err := os.WriteFile(results, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The run rewrites its progress file after every task, so it is the newest .json
+	progress := filepath.Join(runDir, benchmark.ProgressFile)
+	if err := os.WriteFile(progress, []byte(`{"completed":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(progress, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := findLatestResults(resultsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != results {
+		t.Errorf("Expected %s, got %s", results, latest)
+	}
+}
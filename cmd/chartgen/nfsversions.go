@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// versionedStorage returns the NFS configurations whose version is known, ordered by
+// version (v3 before v4.0 before v4.1)
+func (cg *ChartGenerator) versionedStorage() []StorageResult {
+	var versioned []StorageResult
+	for _, s := range cg.comparisons() {
+		if s.NFSVersion != "" {
+			versioned = append(versioned, s)
+		}
+	}
+	sort.SliceStable(versioned, func(i, j int) bool {
+		return compareNFSVersions(versioned[i].NFSVersion, versioned[j].NFSVersion) < 0
+	})
+	return versioned
+}
+
+// compareNFSVersions orders versions such as "v3" and "v4.1" numerically, treating a
+// missing minor version as 0
+func compareNFSVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := range pa {
+		if pa[i] != pb[i] {
+			return pa[i] - pb[i]
+		}
+	}
+	return 0
+}
+
+func versionParts(version string) [2]int {
+	var parts [2]int
+	major, minor, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	parts[0], _ = strconv.Atoi(major)
+	parts[1], _ = strconv.Atoi(minor)
+	return parts
+}
+
+// versionLabel names a configuration on the NFS version chart's axis by its version,
+// keeping the configuration's name when it does not already say the version
+func versionLabel(s StorageResult) string {
+	if strings.Contains(s.Name, s.NFSVersion) {
+		return s.Name
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, s.NFSVersion)
+}
+
+// GenerateNFSVersionChart renders throughput as bars and P50 and P99 latency as lines for
+// the baseline and every NFS configuration whose version is known, ordered by version,
+// so v3, v4.0, v4.1, and v4.2 can be compared side by side. Versions are recorded for
+// versioned mount option variants and for NFS mounts the runner checked.
+func (cg *ChartGenerator) GenerateNFSVersionChart() error {
+	versioned := cg.versionedStorage()
+	if len(versioned) == 0 {
+		return fmt.Errorf("no results with a known NFS version; map versioned nfs.mount_options or leave the NFS mount check enabled")
+	}
+
+	subtitle := "Ops/sec (bars, higher is better) and latency in ms (lines, lower is better)"
+	if note := cg.parametersNote(); note != "" {
+		subtitle += "\n" + note
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    "NFS Versions vs " + cg.baseline().Name,
+			Subtitle: subtitle,
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Ops/sec",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    true,
+			Trigger: "axis",
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: true,
+		}),
+	)
+	bar.ExtendYAxis(opts.YAxis{Name: "Latency (ms)"})
+
+	entries := append([]StorageResult{cg.baseline()}, versioned...)
+	labels := []string{cg.baseline().Name}
+	for _, s := range versioned {
+		labels = append(labels, versionLabel(s))
+	}
+
+	var throughput []opts.BarData
+	var p50, p99 []opts.LineData
+	for i, s := range entries {
+		throughput = append(throughput, opts.BarData{
+			Value:     math.Round(s.Metrics.OperationsPerSecond*10) / 10,
+			ItemStyle: &opts.ItemStyle{Color: seriesColor(i)},
+		})
+		p50 = append(p50, opts.LineData{Value: math.Round(float64(s.Metrics.P50Latency)/1000000*100) / 100})
+		p99 = append(p99, opts.LineData{Value: math.Round(float64(s.Metrics.P99Latency)/1000000*100) / 100})
+	}
+	bar.SetXAxis(labels).AddSeries("Ops/sec", throughput)
+
+	line := charts.NewLine()
+	line.SetXAxis(labels)
+	line.AddSeries("P50 latency (ms)", p50, charts.WithLineChartOpts(opts.LineChart{YAxisIndex: 1}))
+	line.AddSeries("P99 latency (ms)", p99, charts.WithLineChartOpts(opts.LineChart{YAxisIndex: 1}))
+	bar.Overlap(line)
+
+	outputFile := filepath.Join(cg.outputDir, "nfs_versions.html")
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := bar.Render(f); err != nil {
+		return err
+	}
+
+	fmt.Printf("[INFO] NFS version comparison chart saved: %s\n", outputFile)
+	return nil
+}
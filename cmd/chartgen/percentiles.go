@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"github.com/l22io/nfsvsdirectbench/internal/benchmark"
+)
+
+// percentileLabels and percentileValues are the latency percentiles compared by the
+// percentile overhead chart, from the median to the far tail
+var (
+	percentileLabels = []string{"P50", "P90", "P95", "P99", "P99.9"}
+	percentileValues = []func(Metrics) float64{
+		func(m Metrics) float64 { return float64(m.P50Latency) },
+		func(m Metrics) float64 { return float64(m.P90Latency) },
+		func(m Metrics) float64 { return float64(m.P95Latency) },
+		func(m Metrics) float64 { return float64(m.P99Latency) },
+		func(m Metrics) float64 { return float64(m.P999Latency) },
+	}
+)
+
+// percentileOverheads returns how much higher a configuration's latency is than the
+// baseline's at each percentile, in percent
+func (cg *ChartGenerator) percentileOverheads(s StorageResult) []float64 {
+	overheads := make([]float64, len(percentileValues))
+	for i, value := range percentileValues {
+		overheads[i] = benchmark.GetOverheadPercent(value(cg.baseline().Metrics), value(s.Metrics))
+	}
+	return overheads
+}
+
+// GeneratePercentileOverheadChart renders, per latency percentile, how much slower each
+// configuration is than the baseline. Overhead that grows from P50 to P99.9 shows that
+// NFS hurts tail latency more than typical latency.
+func (cg *ChartGenerator) GeneratePercentileOverheadChart() error {
+	subtitle := "Latency increase vs " + cg.baseline().Name + " at each percentile (%) - Lower is Better"
+	if len(cg.comparisons()) == 1 {
+		s := cg.comparisons()[0]
+		overheads := cg.percentileOverheads(s)
+		subtitle += fmt.Sprintf("\n%s overhead goes from %+.1f%% at P50 to %+.1f%% at P99.9", s.Name, overheads[0], overheads[len(overheads)-1])
+	}
+	if note := cg.parametersNote(); note != "" {
+		subtitle += "\n" + note
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Latency Overhead by Percentile",
+			Subtitle: subtitle,
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Overhead (%)",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    true,
+			Trigger: "axis",
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: true,
+		}),
+	)
+	bar.SetXAxis(percentileLabels)
+
+	// Bars per configuration, with a line over them so the trend toward the tail reads
+	// at a glance
+	line := charts.NewLine()
+	line.SetXAxis(percentileLabels)
+	for i, s := range cg.comparisons() {
+		color := seriesColor(i + 1)
+		var bars []opts.BarData
+		var points []opts.LineData
+		for _, overhead := range cg.percentileOverheads(s) {
+			rounded := math.Round(overhead*10) / 10
+			bars = append(bars, opts.BarData{Value: rounded, ItemStyle: &opts.ItemStyle{Color: color}})
+			points = append(points, opts.LineData{Value: rounded})
+		}
+		bar.AddSeries(s.Name, bars)
+		line.AddSeries(s.Name+" trend", points, charts.WithLineStyleOpts(opts.LineStyle{Color: color}), charts.WithItemStyleOpts(opts.ItemStyle{Color: color}))
+	}
+	bar.Overlap(line)
+
+	outputFile := filepath.Join(cg.outputDir, "percentile_overhead.html")
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := bar.Render(f); err != nil {
+		return err
+	}
+
+	fmt.Printf("[INFO] Percentile overhead chart saved: %s\n", outputFile)
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+
+	"github.com/l22io/nfsvsdirectbench/internal/benchmark"
+)
+
+// scenarioOverhead is the NFS overhead of one scenario run, from one results file
+type scenarioOverhead struct {
+	Database    string
+	Scenario    string
+	MountOption string
+	InsertMode  string
+	Throughput  float64 // throughput reduction on NFS, percent
+	P95         float64 // P95 latency increase on NFS, percent
+	p95OK       bool
+}
+
+// label names the scenario run on the chart's axis, with the database when the inputs
+// span several
+func (s scenarioOverhead) label(withDatabase bool) string {
+	name := s.Scenario
+	var qualifiers []string
+	if s.MountOption != "" {
+		qualifiers = append(qualifiers, s.MountOption)
+	}
+	if s.InsertMode != "" {
+		qualifiers = append(qualifiers, insertModeName(s.InsertMode))
+	}
+	if len(qualifiers) > 0 {
+		name += " (" + strings.Join(qualifiers, ", ") + ")"
+	}
+	if withDatabase && s.Database != "" {
+		name = s.Database + "/" + name
+	}
+	return name
+}
+
+// loadScenarioOverhead reads a results file and computes the NFS overhead of its direct
+// and NFS entries. Files without both, such as an insert mode scenario's combined file,
+// return false; the runner writes each of their pairs to a file of its own too.
+func loadScenarioOverhead(path string) (scenarioOverhead, bool, error) {
+	data, err := benchmark.ReadResultsFile(path)
+	if err != nil {
+		return scenarioOverhead{}, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	results, err := parseBenchmarkResults(data)
+	if err != nil {
+		return scenarioOverhead{}, false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	direct, nfs := results.find("direct"), results.find("nfs")
+	throughput, ok := overheadPercent(direct.Metrics.OperationsPerSecond, nfs.Metrics.OperationsPerSecond, true)
+	if !ok {
+		return scenarioOverhead{}, false, nil
+	}
+	p95, p95OK := overheadPercent(float64(direct.Metrics.P95Latency), float64(nfs.Metrics.P95Latency), false)
+	return scenarioOverhead{
+		Database:    direct.Database,
+		Scenario:    direct.Scenario,
+		MountOption: nfs.MountOption,
+		InsertMode:  direct.InsertMode,
+		Throughput:  throughput,
+		P95:         p95,
+		p95OK:       p95OK,
+	}, true, nil
+}
+
+// GenerateScenarioComparison renders the NFS overhead of every scenario in a run's
+// results files side by side: throughput reduction and P95 latency increase per
+// scenario, ordered from the scenario NFS hurts most to the one it hurts least. Mount
+// option variants and insert modes get a bar group of their own.
+func GenerateScenarioComparison(files []string, outputDir string) error {
+	var overheads []scenarioOverhead
+	databases := make(map[string]bool)
+	for _, file := range files {
+		overhead, ok, err := loadScenarioOverhead(file)
+		if err != nil {
+			fmt.Printf("[WARN] Skipping %v\n", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		overheads = append(overheads, overhead)
+		databases[overhead.Database] = true
+	}
+	if len(overheads) == 0 {
+		return fmt.Errorf("no results with both direct and NFS metrics among %d inputs", len(files))
+	}
+
+	sort.SliceStable(overheads, func(i, j int) bool {
+		return overheads[i].Throughput > overheads[j].Throughput
+	})
+
+	var labels []string
+	var throughput, p95 []opts.BarData
+	for _, o := range overheads {
+		labels = append(labels, o.label(len(databases) > 1))
+		throughput = append(throughput, barValue(o.Throughput, true))
+		p95 = append(p95, barValue(o.P95, o.p95OK))
+	}
+
+	worst := overheads[0]
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: "NFS Overhead by Scenario",
+			Subtitle: fmt.Sprintf("Percent worse than direct storage - Lower is Better\nHardest hit: %s, %.1f%% lower throughput",
+				worst.label(len(databases) > 1), worst.Throughput),
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Overhead (%)",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    true,
+			Trigger: "axis",
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: true,
+		}),
+	)
+	bar.SetXAxis(labels).
+		AddSeries("Throughput Reduction (%)", throughput, charts.WithItemStyleOpts(opts.ItemStyle{Color: seriesColor(1)})).
+		AddSeries("P95 Latency Increase (%)", p95, charts.WithItemStyleOpts(opts.ItemStyle{Color: seriesColor(3)}))
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	outputFile := filepath.Join(outputDir, "scenario_comparison.html")
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := bar.Render(f); err != nil {
+		return err
+	}
+
+	fmt.Printf("[INFO] Scenario comparison chart saved: %s (%d scenario runs)\n", outputFile, len(overheads))
+	return nil
+}
+
+// barValue rounds a value for display, using "-" so ECharts leaves out missing data
+func barValue(value float64, ok bool) opts.BarData {
+	if !ok {
+		return opts.BarData{Value: "-"}
+	}
+	return opts.BarData{Value: math.Round(value*10) / 10}
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// staticSeries is one named, colored set of bar values
+type staticSeries struct {
+	Name   string
+	Color  string
+	Values []float64
+}
+
+// staticBarChart describes a grouped bar chart that can be rendered without a browser.
+// It backs the PNG and SVG export formats, since go-echarts only renders client-side.
+type staticBarChart struct {
+	Title      string
+	Subtitle   string
+	YAxisName  string
+	Categories []string
+	Series     []staticSeries
+}
+
+// chart layout margins in pixels
+const (
+	marginLeft   = 80
+	marginRight  = 20
+	marginTop    = 72
+	marginBottom = 60
+	gridLines    = 5
+)
+
+// ExportStatic writes the chart as a PNG or SVG image of the given resolution
+func (c *staticBarChart) ExportStatic(path, format string, width, height int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	switch format {
+	case "png":
+		err = c.renderPNG(w, width, height)
+	case "svg":
+		err = c.renderSVG(w, width, height)
+	default:
+		return fmt.Errorf("unsupported static format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// bar is a positioned rectangle in chart pixel space
+type bar struct {
+	X, Y, W, H int
+	Color      string
+	Label      string
+}
+
+// chartLayout holds the computed geometry shared by both renderers
+type chartLayout struct {
+	PlotLeft, PlotTop, PlotRight, PlotBottom int
+	MaxValue                                 float64
+	Bars                                     []bar
+	CategoryX                                []int
+}
+
+func (c *staticBarChart) layout(width, height int) chartLayout {
+	l := chartLayout{
+		PlotLeft:   marginLeft,
+		PlotTop:    marginTop,
+		PlotRight:  width - marginRight,
+		PlotBottom: height - marginBottom,
+	}
+
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			l.MaxValue = math.Max(l.MaxValue, v)
+		}
+	}
+	l.MaxValue = niceCeil(l.MaxValue)
+
+	plotWidth := l.PlotRight - l.PlotLeft
+	plotHeight := l.PlotBottom - l.PlotTop
+	if len(c.Categories) == 0 || len(c.Series) == 0 || plotWidth <= 0 || plotHeight <= 0 {
+		return l
+	}
+
+	groupWidth := plotWidth / len(c.Categories)
+	barWidth := groupWidth * 7 / 10 / len(c.Series)
+	groupPad := (groupWidth - barWidth*len(c.Series)) / 2
+
+	for i := range c.Categories {
+		groupLeft := l.PlotLeft + i*groupWidth
+		l.CategoryX = append(l.CategoryX, groupLeft+groupWidth/2)
+		for j, s := range c.Series {
+			if i >= len(s.Values) {
+				continue
+			}
+			h := int(float64(plotHeight) * s.Values[i] / l.MaxValue)
+			l.Bars = append(l.Bars, bar{
+				X:     groupLeft + groupPad + j*barWidth,
+				Y:     l.PlotBottom - h,
+				W:     barWidth - 2,
+				H:     h,
+				Color: s.Color,
+				Label: formatValue(s.Values[i]),
+			})
+		}
+	}
+
+	return l
+}
+
+func (c *staticBarChart) renderSVG(w io.Writer, width, height int) error {
+	l := c.layout(width, height)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+	fmt.Fprintf(&b, `<text x="%d" y="24" font-size="18" font-weight="bold">%s</text>`+"\n", marginLeft, escapeXML(c.Title))
+	fmt.Fprintf(&b, `<text x="%d" y="44" font-size="12" fill="#666666">%s</text>`+"\n", marginLeft, escapeXML(c.Subtitle))
+
+	for i := 0; i <= gridLines; i++ {
+		value := l.MaxValue * float64(i) / gridLines
+		y := l.PlotBottom - (l.PlotBottom-l.PlotTop)*i/gridLines
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#dddddd"/>`+"\n", l.PlotLeft, y, l.PlotRight, y)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="11" text-anchor="end">%s</text>`+"\n", l.PlotLeft-6, y+4, formatValue(value))
+	}
+	fmt.Fprintf(&b, `<text x="16" y="%d" font-size="12" transform="rotate(-90 16 %d)" text-anchor="middle">%s</text>`+"\n",
+		(l.PlotTop+l.PlotBottom)/2, (l.PlotTop+l.PlotBottom)/2, escapeXML(c.YAxisName))
+
+	for _, bar := range l.Bars {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", bar.X, bar.Y, bar.W, bar.H, bar.Color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="10" text-anchor="middle">%s</text>`+"\n", bar.X+bar.W/2, bar.Y-4, bar.Label)
+	}
+
+	for i, x := range l.CategoryX {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="12" text-anchor="middle">%s</text>`+"\n", x, l.PlotBottom+18, escapeXML(c.Categories[i]))
+	}
+
+	legendX := l.PlotLeft
+	for _, s := range c.Series {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`+"\n", legendX, height-24, s.Color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="12">%s</text>`+"\n", legendX+16, height-14, escapeXML(s.Name))
+		legendX += 24 + 8*len(s.Name)
+	}
+
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c *staticBarChart) renderPNG(w io.Writer, width, height int) error {
+	l := c.layout(width, height)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	text := func(x, y int, s string, col color.Color) {
+		d := &font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(col),
+			Face: basicfont.Face7x13,
+			Dot:  fixed.P(x, y),
+		}
+		d.DrawString(s)
+	}
+	centered := func(x, y int, s string, col color.Color) {
+		text(x-len(s)*basicfont.Face7x13.Advance/2, y, s, col)
+	}
+	fill := func(x, y, w, h int, col color.Color) {
+		draw.Draw(img, image.Rect(x, y, x+w, y+h), image.NewUniform(col), image.Point{}, draw.Src)
+	}
+
+	black := color.RGBA{0x33, 0x33, 0x33, 0xff}
+	text(marginLeft, 24, c.Title, black)
+	text(marginLeft, 44, c.Subtitle, color.RGBA{0x66, 0x66, 0x66, 0xff})
+
+	for i := 0; i <= gridLines; i++ {
+		value := formatValue(l.MaxValue * float64(i) / gridLines)
+		y := l.PlotBottom - (l.PlotBottom-l.PlotTop)*i/gridLines
+		fill(l.PlotLeft, y, l.PlotRight-l.PlotLeft, 1, color.RGBA{0xdd, 0xdd, 0xdd, 0xff})
+		text(l.PlotLeft-6-len(value)*basicfont.Face7x13.Advance, y+4, value, black)
+	}
+	text(4, l.PlotTop-14, c.YAxisName, black)
+
+	for _, bar := range l.Bars {
+		fill(bar.X, bar.Y, bar.W, bar.H, parseHexColor(bar.Color))
+		centered(bar.X+bar.W/2, bar.Y-4, bar.Label, black)
+	}
+
+	for i, x := range l.CategoryX {
+		centered(x, l.PlotBottom+18, c.Categories[i], black)
+	}
+
+	legendX := l.PlotLeft
+	for _, s := range c.Series {
+		fill(legendX, height-24, 12, 12, parseHexColor(s.Color))
+		text(legendX+16, height-14, s.Name, black)
+		legendX += 24 + basicfont.Face7x13.Advance*len(s.Name)
+	}
+
+	return png.Encode(w, img)
+}
+
+// niceCeil rounds a maximum up to a 1/2/5 x 10^n boundary so axis ticks are readable
+func niceCeil(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if v <= m*exp {
+			return m * exp
+		}
+	}
+	return 10 * exp
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// parseHexColor converts "#RRGGBB" to a color, falling back to grey
+func parseHexColor(s string) color.Color {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil || len(s) != 7 {
+		return color.RGBA{0x99, 0x99, 0x99, 0xff}
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+}
+
+// throughputStaticChart mirrors GenerateThroughputChart for static export
+func (cg *ChartGenerator) throughputStaticChart() *staticBarChart {
+	var series []staticSeries
+	for i, s := range cg.results.Storage {
+		series = append(series, staticSeries{Name: s.Name, Color: seriesColor(i), Values: []float64{s.Metrics.OperationsPerSecond}})
+	}
+
+	return &staticBarChart{
+		Title:      "Throughput Comparison: " + cg.comparedNames(),
+		Subtitle:   cg.throughputSubtitle(),
+		YAxisName:  "Operations per Second",
+		Categories: []string{"Throughput"},
+		Series:     series,
+	}
+}
+
+// latencyStaticChart mirrors GenerateLatencyChart for static export
+func (cg *ChartGenerator) latencyStaticChart() *staticBarChart {
+	var series []staticSeries
+	for i, s := range cg.results.Storage {
+		values := make([]float64, len(latencyValues))
+		for j, value := range latencyValues {
+			values[j] = value(s.Metrics)
+		}
+		series = append(series, staticSeries{Name: s.Name, Color: seriesColor(i), Values: values})
+	}
+
+	return &staticBarChart{
+		Title:      "Latency Distribution: " + cg.comparedNames(),
+		Subtitle:   "Response time in milliseconds - Lower is Better",
+		YAxisName:  "Latency (ms)",
+		Categories: latencyLabels,
+		Series:     series,
+	}
+}
+
+// ExportStaticCharts writes the throughput and/or latency charts as images
+func (cg *ChartGenerator) ExportStaticCharts(chartType, format string, width, height int) error {
+	type export struct {
+		name  string
+		chart *staticBarChart
+	}
+
+	var exports []export
+	switch chartType {
+	case "throughput":
+		exports = append(exports, export{"throughput_chart", cg.throughputStaticChart()})
+	case "latency":
+		exports = append(exports, export{"latency_chart", cg.latencyStaticChart()})
+	case "all":
+		exports = append(exports,
+			export{"throughput_chart", cg.throughputStaticChart()},
+			export{"latency_chart", cg.latencyStaticChart()},
+		)
+	default:
+		return fmt.Errorf("chart type %q cannot be exported as %s (supported: throughput, latency, all)", chartType, format)
+	}
+
+	for _, e := range exports {
+		outputFile := filepath.Join(cg.outputDir, e.name+"."+format)
+		if err := e.chart.ExportStatic(outputFile, format, width, height); err != nil {
+			return fmt.Errorf("failed to export %s: %w", e.name, err)
+		}
+		fmt.Printf("[INFO] %s chart saved: %s\n", strings.ToUpper(format), outputFile)
+	}
+
+	return nil
+}
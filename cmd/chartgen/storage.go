@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BenchmarkResults is a results file in general form: every storage configuration it
+// holds, in chart order. The baseline the others are compared against comes first.
+type BenchmarkResults struct {
+	Timestamp string          // metadata.timestamp, if the file has one
+	Storage   []StorageResult // baseline first
+	Config    *runConfig      // the effective config of the run, if the file has one
+}
+
+// runConfig is the part of a results file's effective config that charts are annotated with
+type runConfig struct {
+	Scenarios []struct {
+		Name       string                 `json:"name"`
+		Duration   int                    `json:"duration"`
+		Parameters map[string]interface{} `json:"parameters"`
+		Session    map[string]interface{} `json:"session_settings"`
+	} `json:"scenarios"`
+}
+
+// resultsConfigKey is the results file entry holding the effective config
+const resultsConfigKey = "config"
+
+// StorageResult is one storage configuration's entry in a results file, such as direct
+// storage, NFS, or an NFS mount option variant
+type StorageResult struct {
+	Name        string        `json:"-"` // display name, e.g. "Direct", "NFS", "NFS v4.1"
+	Key         string        `json:"-"` // the entry's key in the results file
+	Scenario    string        `json:"Name"`
+	Database    string        `json:"Database"`
+	StorageType string        `json:"StorageType"`
+	MountOption string        `json:"MountOption"`
+	NFSVersion  string        `json:"NFSVersion"` // e.g. "v4.1", when the run recorded it
+	InsertMode  string        `json:"InsertMode"` // "insert" or "copy", when the scenario compared insert modes
+	Duration    int64         `json:"Duration"`
+	Metrics     Metrics       `json:"Metrics"`
+	Repeats     []Metrics     `json:"Repeats"` // per-run metrics when the scenario was repeated
+	DBStats     DatabaseStats `json:"DBStats"`
+}
+
+// label is the storage label the runner names the entry's raw latency export after
+func (s StorageResult) label() string {
+	if s.StorageType == "" {
+		return s.Key
+	}
+	label := s.StorageType
+	if s.MountOption != "" {
+		label += "_" + s.MountOption
+	}
+	if s.InsertMode != "" {
+		label += "_" + s.InsertMode
+	}
+	return label
+}
+
+// storageKey is the entry's key without its insert mode, naming the storage
+// configuration alone
+func (s StorageResult) storageKey() string {
+	if s.InsertMode == "" {
+		return s.Key
+	}
+	return strings.TrimSuffix(s.Key, "_"+s.InsertMode)
+}
+
+// find returns the entry stored under a results file key, or an empty result if the
+// file has none
+func (r BenchmarkResults) find(key string) StorageResult {
+	for _, s := range r.Storage {
+		if s.Key == key {
+			return s
+		}
+	}
+	return StorageResult{}
+}
+
+// seriesColors are assigned to storage configurations in chart order, so direct storage
+// keeps its blue and NFS its orange in a plain direct vs NFS file
+var seriesColors = []string{"#007AFF", "#FF6B35", "#28a745", "#9a60b4", "#ffc107", "#17a2b8", "#dc3545", "#6c757d"}
+
+func seriesColor(i int) string {
+	return seriesColors[i%len(seriesColors)]
+}
+
+// parseBenchmarkResults parses a results file, which maps storage labels such as
+// "direct", "nfs", or "nfs_<variant>" to their results. Every entry with metrics becomes
+// a storage configuration: direct first as the baseline, then plain NFS, then the rest
+// by name, with the insert modes of a configuration next to each other, INSERT first.
+// The two-entry direct/nfs file of a run without variants is the common case.
+func parseBenchmarkResults(data []byte) (BenchmarkResults, error) {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return BenchmarkResults{}, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	var results BenchmarkResults
+	if raw, ok := entries["metadata"]; ok {
+		var metadata struct {
+			Timestamp string `json:"timestamp"`
+		}
+		if err := json.Unmarshal(raw, &metadata); err == nil {
+			results.Timestamp = metadata.Timestamp
+		}
+	}
+
+	if raw, ok := entries[resultsConfigKey]; ok {
+		var config runConfig
+		if err := json.Unmarshal(raw, &config); err == nil {
+			results.Config = &config
+		}
+	}
+
+	for key, raw := range entries {
+		if key == resultsConfigKey {
+			continue
+		}
+		var probe map[string]json.RawMessage
+		if json.Unmarshal(raw, &probe) != nil || probe["Metrics"] == nil {
+			continue // metadata or another non-result entry
+		}
+
+		var result StorageResult
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return BenchmarkResults{}, fmt.Errorf("failed to parse %s results: %w", key, err)
+		}
+		result.Key = key
+		result.Name = storageDisplayName(result.storageKey(), result.MountOption)
+		if result.InsertMode != "" {
+			result.Name += " " + insertModeName(result.InsertMode)
+		}
+		results.Storage = append(results.Storage, result)
+	}
+	if len(results.Storage) == 0 {
+		return BenchmarkResults{}, fmt.Errorf("no storage results in input")
+	}
+
+	sort.Slice(results.Storage, func(i, j int) bool {
+		a, b := results.Storage[i], results.Storage[j]
+		if ra, rb := storageRank(a.storageKey()), storageRank(b.storageKey()); ra != rb {
+			return ra < rb
+		}
+		if a.storageKey() != b.storageKey() {
+			return a.Name < b.Name
+		}
+		return insertModeRank(a.InsertMode) < insertModeRank(b.InsertMode)
+	})
+	return results, nil
+}
+
+// storageDisplayName names a results entry for legends and axes
+func storageDisplayName(key, mountOption string) string {
+	switch {
+	case key == "direct":
+		return "Direct"
+	case key == "nfs":
+		return "NFS"
+	case mountOption != "":
+		return "NFS " + mountOption
+	case strings.HasPrefix(key, "nfs_"):
+		return "NFS " + strings.TrimPrefix(key, "nfs_")
+	default:
+		return key
+	}
+}
+
+// storageRank orders direct before plain NFS before everything else
+func storageRank(key string) int {
+	switch key {
+	case "direct":
+		return 0
+	case "nfs":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// baseline returns the configuration the others are compared against
+func (cg *ChartGenerator) baseline() StorageResult {
+	return cg.results.Storage[0]
+}
+
+// comparisons returns every configuration but the baseline
+func (cg *ChartGenerator) comparisons() []StorageResult {
+	return cg.results.Storage[1:]
+}
+
+// storageNames returns the display names of every configuration, in chart order
+func (cg *ChartGenerator) storageNames() []string {
+	names := make([]string, len(cg.results.Storage))
+	for i, s := range cg.results.Storage {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// comparedNames names the compared configurations for chart titles: the others against
+// the baseline, such as "NFS vs Direct" or "NFS v3, NFS v4.1 vs Direct"
+func (cg *ChartGenerator) comparedNames() string {
+	names := cg.storageNames()
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[1:], ", ") + " vs " + names[0]
+}
+
+// throughputReduction returns how much lower a configuration's throughput is than the
+// baseline's, in percent
+func (cg *ChartGenerator) throughputReduction(s StorageResult) float64 {
+	base := cg.baseline().Metrics.OperationsPerSecond
+	if base == 0 {
+		return 0
+	}
+	return ((base - s.Metrics.OperationsPerSecond) / base) * 100
+}
+
+// throughputSubtitle summarizes each configuration's throughput against the baseline
+func (cg *ChartGenerator) throughputSubtitle() string {
+	if len(cg.comparisons()) == 0 || cg.baseline().Metrics.OperationsPerSecond == 0 {
+		return "Operations per second - Higher is Better"
+	}
+	if len(cg.comparisons()) == 1 {
+		s := cg.comparisons()[0]
+		return fmt.Sprintf("Operations per second - %s is %.1f%% slower than %s", s.Name, cg.throughputReduction(s), cg.baseline().Name)
+	}
+
+	var parts []string
+	for _, s := range cg.comparisons() {
+		parts = append(parts, fmt.Sprintf("%s %.1f%% slower", s.Name, cg.throughputReduction(s)))
+	}
+	return fmt.Sprintf("Operations per second vs %s - %s", cg.baseline().Name, strings.Join(parts, ", "))
+}
+
+// parametersNote describes the scenario settings the results were produced with, such as
+// "duration 60s, batch_size 1000, threads 10, SET synchronous_commit=off", or returns ""
+// if the file has no config
+func (cg *ChartGenerator) parametersNote() string {
+	if cg.results.Config == nil {
+		return ""
+	}
+	for _, scenario := range cg.results.Config.Scenarios {
+		if scenario.Name != cg.baseline().Scenario {
+			continue
+		}
+		parts := []string{fmt.Sprintf("duration %ds", scenario.Duration)}
+		keys := make([]string, 0, len(scenario.Parameters))
+		for key := range scenario.Parameters {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s %v", key, scenario.Parameters[key]))
+		}
+		settings := make([]string, 0, len(scenario.Session))
+		for key, value := range scenario.Session {
+			settings = append(settings, fmt.Sprintf("SET %s=%v", key, value))
+		}
+		sort.Strings(settings)
+		return strings.Join(append(parts, settings...), ", ")
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+// The two-field direct/nfs format chartgen read before it took any number of storage
+// configurations, without StorageType or Name in the entries
+func TestParseTwoFieldResults(t *testing.T) {
+	data, err := os.ReadFile("testdata/two_field_results.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := parseBenchmarkResults(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results.Timestamp != "2024-01-01T12:00:00Z" {
+		t.Errorf("Expected the metadata timestamp, got %q", results.Timestamp)
+	}
+	if len(results.Storage) != 2 {
+		t.Fatalf("Expected direct and NFS, got %+v", results.Storage)
+	}
+	direct, nfs := results.Storage[0], results.Storage[1]
+	if direct.Name != "Direct" || direct.label() != "direct" || direct.Metrics.OperationsPerSecond != 1000 || direct.DBStats.TableSizeBytes != 8192000 {
+		t.Errorf("Unexpected baseline %+v", direct)
+	}
+	if nfs.Name != "NFS" || nfs.label() != "nfs" || nfs.Metrics.P99Latency != 9000000 || len(nfs.Repeats) != 2 {
+		t.Errorf("Unexpected NFS entry %+v", nfs)
+	}
+
+	cg := &ChartGenerator{results: results}
+	if got := cg.comparedNames(); got != "NFS vs Direct" {
+		t.Errorf("Expected chart titles to compare NFS vs Direct, got %q", got)
+	}
+}
+
+func TestComparedNames(t *testing.T) {
+	data := []byte(`{
+		"nfs_v4.2": {"StorageType": "nfs", "MountOption": "v4.2", "Metrics": {}},
+		"direct": {"StorageType": "direct", "Metrics": {}},
+		"nfs_v3": {"StorageType": "nfs", "MountOption": "v3", "Metrics": {}}
+	}`)
+	results, err := parseBenchmarkResults(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cg := &ChartGenerator{results: results}
+	if got := cg.comparedNames(); got != "NFS v3, NFS v4.2 vs Direct" {
+		t.Errorf("Unexpected title %q", got)
+	}
+}
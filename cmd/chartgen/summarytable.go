@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// summaryRow is one metric of the dashboard's summary table, with a value per storage
+// configuration in chart order
+type summaryRow struct {
+	label          string
+	values         []float64
+	format         func(float64) string
+	higherIsBetter bool
+}
+
+func formatOps(v float64) string   { return fmt.Sprintf("%.2f", v) }
+func formatCount(v float64) string { return fmt.Sprintf("%.0f", v) }
+func formatMs(v float64) string    { return fmt.Sprintf("%.3f ms", v) }
+func formatSec(v float64) string   { return fmt.Sprintf("%.1f s", v) }
+func formatMB(v float64) string    { return fmt.Sprintf("%.1f MB", v/(1024*1024)) }
+
+// summaryRows lists the raw figures behind the dashboard charts
+func (cg *ChartGenerator) summaryRows() []summaryRow {
+	row := func(label string, format func(float64) string, higherIsBetter bool, value func(StorageResult) float64) summaryRow {
+		values := make([]float64, len(cg.results.Storage))
+		for i, s := range cg.results.Storage {
+			values[i] = value(s)
+		}
+		return summaryRow{label, values, format, higherIsBetter}
+	}
+	latency := func(label string, value func(Metrics) int64) summaryRow {
+		return row(label, formatMs, false, func(s StorageResult) float64 { return float64(value(s.Metrics)) / 1e6 })
+	}
+
+	return []summaryRow{
+		row("Throughput (ops/sec)", formatOps, true, func(s StorageResult) float64 { return s.Metrics.OperationsPerSecond }),
+		row("Total operations", formatCount, true, func(s StorageResult) float64 { return float64(s.Metrics.TotalOperations) }),
+		latency("Average latency", func(m Metrics) int64 { return m.AverageLatency }),
+		latency("Min latency", func(m Metrics) int64 { return m.MinLatency }),
+		latency("P50 latency", func(m Metrics) int64 { return m.P50Latency }),
+		latency("P90 latency", func(m Metrics) int64 { return m.P90Latency }),
+		latency("P95 latency", func(m Metrics) int64 { return m.P95Latency }),
+		latency("P99 latency", func(m Metrics) int64 { return m.P99Latency }),
+		latency("P99.9 latency", func(m Metrics) int64 { return m.P999Latency }),
+		latency("Max latency", func(m Metrics) int64 { return m.MaxLatency }),
+		row("Duration", formatSec, false, func(s StorageResult) float64 { return float64(s.Duration) / 1e9 }),
+		row("Final records", formatCount, true, func(s StorageResult) float64 { return float64(s.DBStats.FinalRecordCount) }),
+		row("Table size", formatMB, false, func(s StorageResult) float64 { return float64(s.DBStats.TableSizeBytes) }),
+		row("Index size", formatMB, false, func(s StorageResult) float64 { return float64(s.DBStats.IndexSizeBytes) }),
+	}
+}
+
+// summaryTableHTML renders the summary rows as a plain HTML table: a column per storage
+// configuration, then each configuration's delta against the baseline in red where it
+// is worse and green where it is better
+func (cg *ChartGenerator) summaryTableHTML() string {
+	base := cg.baseline()
+	headings := append([]string{"Metric"}, cg.storageNames()...)
+	for _, s := range cg.comparisons() {
+		headings = append(headings, s.Name+" vs "+base.Name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<div class="container" style="width:900px;margin:20px auto;font-family:sans-serif">
+<h3>Summary: %s</h3>
+`, html.EscapeString(strings.Join(cg.storageNames(), " vs ")))
+	if note := cg.parametersNote(); note != "" {
+		fmt.Fprintf(&b, "<p>%s: %s</p>\n", html.EscapeString(cg.baseline().Scenario), html.EscapeString(note))
+	}
+	b.WriteString(`<table style="border-collapse:collapse;width:100%">
+<thead><tr>`)
+	for i, heading := range headings {
+		align := "right"
+		if i == 0 {
+			align = "left"
+		}
+		fmt.Fprintf(&b, `<th style="text-align:%s;border-bottom:2px solid #333;padding:4px 8px">%s</th>`, align, html.EscapeString(heading))
+	}
+	b.WriteString("</tr></thead>\n<tbody>\n")
+
+	for _, row := range cg.summaryRows() {
+		fmt.Fprintf(&b, `<tr><td style="padding:4px 8px;border-bottom:1px solid #ddd">%s</td>`, html.EscapeString(row.label))
+		for _, v := range row.values {
+			fmt.Fprintf(&b, `<td style="text-align:right;padding:4px 8px;border-bottom:1px solid #ddd">%s</td>`, row.format(v))
+		}
+		for _, v := range row.values[1:] {
+			delta, color := "-", "inherit"
+			if row.values[0] != 0 {
+				change := (v - row.values[0]) / row.values[0] * 100
+				delta = fmt.Sprintf("%+.1f%%", change)
+				if change != 0 {
+					if (change > 0) == row.higherIsBetter {
+						color = "#28a745"
+					} else {
+						color = "#dc3545"
+					}
+				}
+			}
+			fmt.Fprintf(&b, `<td style="text-align:right;padding:4px 8px;border-bottom:1px solid #ddd;color:%s">%s</td>`, color, delta)
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n</div>\n")
+	return b.String()
+}
+
+// insertBeforeBodyEnd adds an HTML fragment at the end of a rendered page's body
+func insertBeforeBodyEnd(page []byte, fragment string) []byte {
+	i := bytes.LastIndex(page, []byte("</body>"))
+	if i < 0 {
+		return append(page, fragment...)
+	}
+	out := make([]byte, 0, len(page)+len(fragment))
+	out = append(out, page[:i]...)
+	out = append(out, fragment...)
+	return append(out, page[i:]...)
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/go-echarts/go-echarts/v2/types"
+
+	"github.com/l22io/nfsvsdirectbench/internal/benchmark"
+)
+
+// trendPoint holds the headline figures of a single run on the trend chart
+type trendPoint struct {
+	Timestamp time.Time
+	Source    string
+	DirectOps float64
+	NFSOps    float64
+	DirectP95 int64
+	NFSP95    int64
+}
+
+// timestampLayouts are the formats accepted for metadata.timestamp and run directory names
+var timestampLayouts = []string{
+	time.RFC3339,
+	"20060102_150405",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// resolveInputs expands a directory or glob pattern into a list of JSON result files
+func resolveInputs(pattern string) ([]string, error) {
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		var files []string
+		err := filepath.WalkDir(pattern, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isResultsFile(path) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return files, nil
+	}
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+	}
+	return files, nil
+}
+
+// loadTrendPoint reads a results file and extracts the figures plotted on the trend chart
+func loadTrendPoint(path string) (trendPoint, error) {
+	data, err := benchmark.ReadResultsFile(path)
+	if err != nil {
+		return trendPoint{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	results, err := parseBenchmarkResults(data)
+	if err != nil {
+		return trendPoint{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// The trend follows plain direct and NFS; mount option variants are left out
+	direct, nfs := results.find("direct"), results.find("nfs")
+	return trendPoint{
+		Timestamp: resultTimestamp(path, results.Timestamp),
+		Source:    path,
+		DirectOps: direct.Metrics.OperationsPerSecond,
+		NFSOps:    nfs.Metrics.OperationsPerSecond,
+		DirectP95: direct.Metrics.P95Latency,
+		NFSP95:    nfs.Metrics.P95Latency,
+	}, nil
+}
+
+// resultTimestamp determines when a run happened, preferring the metadata timestamp,
+// then the run_<timestamp> directory name, and finally the file modification time
+func resultTimestamp(path, metadataTimestamp string) time.Time {
+	if ts, ok := parseTimestamp(metadataTimestamp); ok {
+		return ts
+	}
+
+	dir := filepath.Base(filepath.Dir(path))
+	if ts, ok := parseTimestamp(strings.TrimPrefix(dir, "run_")); ok {
+		return ts
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+func parseTimestamp(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range timestampLayouts {
+		if ts, err := time.Parse(layout, value); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// overheadPercent returns how much worse NFS is than direct, or false if either side is missing
+func overheadPercent(direct, nfs float64, higherIsBetter bool) (float64, bool) {
+	if direct == 0 || nfs == 0 {
+		return 0, false
+	}
+	if higherIsBetter {
+		return ((direct - nfs) / direct) * 100, true
+	}
+	return ((nfs - direct) / direct) * 100, true
+}
+
+// lineValue rounds a value for display, using "-" so ECharts leaves a gap for missing data
+func lineValue(value float64, ok bool) opts.LineData {
+	if !ok {
+		return opts.LineData{Value: "-"}
+	}
+	return opts.LineData{Value: math.Round(value*10) / 10}
+}
+
+// GenerateTrendChart renders the NFS vs direct trend across multiple result files
+func GenerateTrendChart(files []string, outputDir string) error {
+	var points []trendPoint
+	for _, file := range files {
+		point, err := loadTrendPoint(file)
+		if err != nil {
+			fmt.Printf("[WARN] Skipping %v\n", err)
+			continue
+		}
+		points = append(points, point)
+	}
+
+	if len(points) == 0 {
+		return fmt.Errorf("no usable result files among %d inputs", len(files))
+	}
+
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+
+	var labels []string
+	var directOps, nfsOps, throughputOverhead, p95Overhead []opts.LineData
+	for _, p := range points {
+		labels = append(labels, p.Timestamp.Format("2006-01-02 15:04"))
+		directOps = append(directOps, lineValue(p.DirectOps, p.DirectOps != 0))
+		nfsOps = append(nfsOps, lineValue(p.NFSOps, p.NFSOps != 0))
+		throughputOverhead = append(throughputOverhead, lineValue(overheadPercent(p.DirectOps, p.NFSOps, true)))
+		p95Overhead = append(p95Overhead, lineValue(overheadPercent(float64(p.DirectP95), float64(p.NFSP95), false)))
+	}
+
+	throughputLine := charts.NewLine()
+	throughputLine.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Throughput Trend: NFS vs Direct Storage",
+			Subtitle: fmt.Sprintf("Operations per second across %d runs", len(points)),
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Operations per Second",
+		}),
+		charts.WithLegendOpts(opts.Legend{Show: true}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true, Trigger: "axis"}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Theme: types.ThemeWesteros,
+		}),
+	)
+	throughputLine.SetXAxis(labels).
+		AddSeries("Direct Storage", directOps, charts.WithItemStyleOpts(opts.ItemStyle{Color: "#007AFF"})).
+		AddSeries("NFS Storage", nfsOps, charts.WithItemStyleOpts(opts.ItemStyle{Color: "#FF6B35"}))
+
+	overheadLine := charts.NewLine()
+	overheadLine.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    "NFS Overhead Trend",
+			Subtitle: "Percent worse than direct storage - Lower is Better",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Overhead (%)",
+		}),
+		charts.WithLegendOpts(opts.Legend{Show: true}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: true, Trigger: "axis"}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Theme: types.ThemeWesteros,
+		}),
+	)
+	overheadLine.SetXAxis(labels).
+		AddSeries("Throughput Reduction (%)", throughputOverhead).
+		AddSeries("P95 Latency Increase (%)", p95Overhead)
+
+	page := components.NewPage()
+	page.SetLayout(components.PageFlexLayout)
+	page.AddCharts(throughputLine, overheadLine)
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	outputFile := filepath.Join(outputDir, "trend_chart.html")
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := page.Render(f); err != nil {
+		return err
+	}
+
+	fmt.Printf("[INFO] Trend chart saved: %s (%d runs)\n", outputFile, len(points))
+	return nil
+}
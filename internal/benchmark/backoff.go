@@ -0,0 +1,64 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// errorBackoff paces a worker's retries after failed operations. The pause doubles with
+// each failure in a row up to a cap, and after too many failures in a row the worker
+// gives up, so a dead mount fails the scenario instead of reporting zero throughput.
+type errorBackoff struct {
+	initial     time.Duration
+	max         time.Duration
+	limit       int // failures in a row before giving up; 0 never gives up
+	consecutive int
+}
+
+// newErrorBackoff returns a backoff for one worker using the execution settings
+func (r *Runner) newErrorBackoff() *errorBackoff {
+	return &errorBackoff{
+		initial: r.config.GetErrorBackoff(),
+		max:     r.config.GetMaxErrorBackoff(),
+		limit:   r.config.Execution.MaxConsecutiveErrors,
+	}
+}
+
+// failed records a failed operation and waits before the retry, returning early if ctx
+// ends. It returns an error wrapping err once the limit of failures in a row is reached,
+// or at once for a constraint violation, which points at the workload rather than the
+// storage and would fail the same way on every retry.
+func (b *errorBackoff) failed(ctx context.Context, err error) error {
+	b.consecutive++
+	if errors.Is(err, database.ErrConstraint) {
+		return fmt.Errorf("giving up on a constraint violation: %w", err)
+	}
+	if b.limit > 0 && b.consecutive >= b.limit {
+		return fmt.Errorf("giving up after %d consecutive errors: %w", b.consecutive, err)
+	}
+
+	pause := b.initial
+	for i := 1; i < b.consecutive && pause < b.max; i++ {
+		pause *= 2
+	}
+	if pause > b.max {
+		pause = b.max
+	}
+
+	timer := time.NewTimer(pause)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	return nil
+}
+
+// succeeded resets the run of failures after a successful operation
+func (b *errorBackoff) succeeded() {
+	b.consecutive = 0
+}
@@ -0,0 +1,98 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// bulkLoadScenario loads a fixed number of rows instead of inserting for a fixed duration
+const bulkLoadScenario = "bulk_load"
+
+func init() {
+	RegisterWorkload(bulkLoadScenario, true, func(scenario config.ScenarioConfig, opts WorkloadOptions) (Workload, error) {
+		return newBulkLoadWorkload(scenario, opts)
+	})
+}
+
+// bulkLoadWorkload inserts target_rows rows and then stops. It takes the heavy_inserts
+// parameters, but the result's Duration is the load's wall time and its throughput the
+// effective rows per second.
+//
+// Threads claim batches from a shared row counter, so the last batch is cut short to
+// land exactly on the target. With execution.deterministic, each thread instead loads a
+// fixed equal share, so every run splits the rows across threads the same way. The rows
+// of a failed batch that didn't commit are retried so the load still ends on exactly
+// target_rows.
+type bulkLoadWorkload struct {
+	*insertWorkload
+	targetRows int64
+	claimed    []int64 // rows claimed against each share; a single shared counter unless deterministic
+	shares     []int64
+	pending    [][]database.BenchmarkRecord // per-thread batch awaiting a retry
+}
+
+func newBulkLoadWorkload(scenario config.ScenarioConfig, opts WorkloadOptions) (*bulkLoadWorkload, error) {
+	targetRows, err := intParam(scenario.Parameters, "target_rows", 0)
+	if err != nil {
+		return nil, err
+	}
+	if targetRows <= 0 {
+		return nil, fmt.Errorf("bulk_load requires a positive target_rows parameter, got %d", targetRows)
+	}
+
+	inserts, err := newInsertWorkload(scenario, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &bulkLoadWorkload{
+		insertWorkload: inserts,
+		targetRows:     int64(targetRows),
+		claimed:        make([]int64, 1),
+		shares:         []int64{int64(targetRows)},
+		pending:        make([][]database.BenchmarkRecord, opts.Threads),
+	}
+	if opts.Deterministic {
+		w.claimed = make([]int64, opts.Threads)
+		w.shares = threadShares(w.targetRows, opts.Threads)
+	}
+	return w, nil
+}
+
+// RunOp inserts the thread's next batch, or retries the uncommitted rows of its last one
+// if that failed
+func (w *bulkLoadWorkload) RunOp(ctx context.Context, db database.Database, thread int) (OpResult, error) {
+	if w.pending[thread] == nil {
+		share := 0
+		if len(w.shares) > 1 {
+			share = thread
+		}
+		end := atomic.AddInt64(&w.claimed[share], int64(w.batchSize))
+		start := end - int64(w.batchSize)
+		if start >= w.shares[share] {
+			return OpResult{}, ErrWorkloadDone
+		}
+		if end > w.shares[share] {
+			end = w.shares[share]
+		}
+		w.pending[thread] = w.nextBatch(thread, int(end-start))
+	}
+
+	result, err := w.insert(ctx, db, thread, w.pending[thread])
+	if err != nil {
+		// Only the rows the failed attempt didn't commit are retried
+		w.pending[thread] = w.pending[thread][result.Items:]
+	} else {
+		w.pending[thread] = nil
+	}
+	return result, err
+}
+
+// Stats records the target alongside the final row count
+func (w *bulkLoadWorkload) Stats(ctx context.Context, db database.Database) map[string]interface{} {
+	return map[string]interface{}{"target_rows": w.targetRows}
+}
@@ -0,0 +1,68 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// partialCommitDB commits the first transaction of a batch and then fails, once, like a
+// connection lost partway through a batch with rows_per_commit
+type partialCommitDB struct {
+	database.Database
+	failed   bool
+	inserted []int
+}
+
+func (db *partialCommitDB) InsertBatch(ctx context.Context, batch []database.BenchmarkRecord, rowsPerCommit int) (database.BatchTiming, error) {
+	if !db.failed {
+		db.failed = true
+		db.inserted = append(db.inserted, rowsPerCommit)
+		return database.BatchTiming{Committed: rowsPerCommit}, errors.New("connection lost")
+	}
+	db.inserted = append(db.inserted, len(batch))
+	return database.BatchTiming{Committed: len(batch)}, nil
+}
+
+func TestBulkLoadRetriesOnlyUncommittedRows(t *testing.T) {
+	w, err := newBulkLoadWorkload(config.ScenarioConfig{
+		Name: bulkLoadScenario,
+		Parameters: map[string]interface{}{
+			"target_rows":     10,
+			"batch_size":      10,
+			"rows_per_commit": 4,
+			"record_size":     "small",
+		},
+	}, WorkloadOptions{Threads: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := &partialCommitDB{}
+	ctx := context.Background()
+
+	result, err := w.RunOp(ctx, db, 0)
+	if err == nil {
+		t.Fatal("Expected the first batch to fail")
+	}
+	if result.Items != 4 {
+		t.Errorf("Expected the failed batch to count its 4 committed rows, got %d", result.Items)
+	}
+
+	result, err = w.RunOp(ctx, db, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Items != 6 {
+		t.Errorf("Expected the retry to insert the 6 uncommitted rows, got %d", result.Items)
+	}
+
+	if _, err := w.RunOp(ctx, db, 0); !errors.Is(err, ErrWorkloadDone) {
+		t.Errorf("Expected the load to be done, got %v", err)
+	}
+	if len(db.inserted) != 2 || db.inserted[0]+db.inserted[1] != 10 {
+		t.Errorf("Expected exactly target_rows inserted, got batches of %v", db.inserted)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build linux
+
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+const dropCachesPath = "/proc/sys/vm/drop_caches"
+
+// dropCachesHint describes how caches are dropped, for diagnostics
+const dropCachesHint = "write to " + dropCachesPath
+
+// checkDropCaches reports whether dropPageCache would be permitted, without dropping anything
+func checkDropCaches() error {
+	f, err := os.OpenFile(dropCachesPath, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("cannot open %s for writing (run as root in a privileged container): %w", dropCachesPath, err)
+	}
+	return f.Close()
+}
+
+// dropPageCache flushes dirty pages and drops the page cache, dentries, and inodes
+func dropPageCache() error {
+	syscall.Sync()
+
+	if err := os.WriteFile(dropCachesPath, []byte("3\n"), 0); err != nil {
+		if errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EROFS) {
+			return fmt.Errorf("clearing caches requires root with a writable %s (disable execution.cleanup.clear_caches or run as root): %w", dropCachesPath, err)
+		}
+		return fmt.Errorf("failed to drop caches: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package benchmark
+
+import "fmt"
+
+// dropCachesHint describes how caches are dropped, for diagnostics
+const dropCachesHint = "drop the OS page cache"
+
+// checkDropCaches reports that cache clearing is unavailable on this platform
+func checkDropCaches() error {
+	return dropPageCache()
+}
+
+// dropPageCache is only implemented on Linux
+func dropPageCache() error {
+	return fmt.Errorf("execution.cleanup.clear_caches is only supported on Linux")
+}
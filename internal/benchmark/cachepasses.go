@@ -0,0 +1,83 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+)
+
+// cacheModeColdWarm is the cache_mode that runs a workload's operations twice: once
+// right after dropping the OS page cache and once again with the cache warm
+const cacheModeColdWarm = "cold_warm"
+
+// Passes of cache_mode: cold_warm, in the order they run. Each pass's result is named
+// <scenario>_<pass>, so both are saved, reported, and charted as scenarios of their own.
+const (
+	cachePassCold = "cold"
+	cachePassWarm = "warm"
+)
+
+// parseCacheMode reports whether a scenario sets cache_mode: cold_warm, which needs a
+// count-bound workload that can replay its operations
+func parseCacheMode(scenario config.ScenarioConfig, spec workloadSpec) (bool, error) {
+	v, ok := scenario.Parameters["cache_mode"]
+	if !ok || v == nil || v == "" {
+		return false, nil
+	}
+	if fmt.Sprintf("%v", v) != cacheModeColdWarm {
+		return false, fmt.Errorf("invalid cache_mode parameter %v: must be %s", v, cacheModeColdWarm)
+	}
+	if !spec.countBound {
+		return false, fmt.Errorf("cache_mode %s requires a workload with a fixed set of operations, such as %s", cacheModeColdWarm, readQueriesScenario)
+	}
+	return true, nil
+}
+
+// cachePassName names the result of a cache_mode pass
+func cachePassName(scenario, pass string) string {
+	return scenario + "_" + pass
+}
+
+// runCachePasses measures each storage type's workload cold and then warm. The page cache
+// is dropped right before the cold pass, and the warm pass replays the same operations
+// immediately after it, before the next storage type's drop evicts what it cached. A
+// cold pass is only meaningful if the cache was dropped, so failing to drop it fails the
+// run.
+func (r *Runner) runCachePasses(ctx context.Context, runs []*workloadRun, scenario config.ScenarioConfig) ([]*ScenarioResult, error) {
+	var scenarioResults []*ScenarioResult
+	for _, run := range runs {
+		replayer, ok := run.workload.(WorkloadReplayer)
+		if !ok {
+			return nil, fmt.Errorf("workload %q cannot replay its operations for cache_mode %s", scenario.WorkloadName(), cacheModeColdWarm)
+		}
+
+		for _, pass := range []string{cachePassCold, cachePassWarm} {
+			if pass == cachePassCold {
+				if err := dropPageCache(); err != nil {
+					return nil, fmt.Errorf("%s: cold pass: %w", run.storageType, err)
+				}
+			} else {
+				replayer.Replay()
+				run.collector = r.newCollector()
+				run.threadCollectors = r.newThreadCollectors(run.threads)
+				if run.nfsBefore != nil {
+					if stats, err := snapshotNFSStats(run.db); err == nil {
+						run.nfsBefore = stats
+					}
+				}
+				run.db.MarkPoolStats(ctx)
+			}
+
+			slog.Info("Starting cache pass", "scenario", scenario.Name, "storage_type", run.storageType, "pass", pass)
+			if err := r.measureWorkload(ctx, run, 0); err != nil {
+				return nil, fmt.Errorf("%s: %s pass: %w", run.storageType, pass, err)
+			}
+			result := r.finishWorkload(ctx, run, scenario)
+			result.Name = cachePassName(scenario.Name, pass)
+			scenarioResults = append(scenarioResults, result)
+		}
+	}
+	return scenarioResults, nil
+}
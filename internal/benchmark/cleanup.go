@@ -0,0 +1,97 @@
+package benchmark
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// cleanupBeforeRun performs the configured cleanup before every measured run (each repeat
+// of each storage type), so no run benefits from caches warmed by the previous one.
+// Table resets (execution.cleanup.reset_databases) happen during scenario setup, where
+// the connection is already open. Failing to drop caches (typically when not running as
+// root) is logged once rather than failing every run.
+func (r *Runner) cleanupBeforeRun(ctx context.Context, t task) error {
+	if !r.config.Execution.Cleanup.ClearCaches {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := dropPageCache(); err != nil {
+		r.cacheWarning.Do(func() {
+			slog.Warn("Runs will not start with a cold page cache", "error", err)
+		})
+		return nil
+	}
+	slog.Debug("Dropped OS page cache", "task", t.String())
+	return nil
+}
+
+// cleanupAfterRun handles the benchmark table once a run's results are gathered. With
+// execution.cleanup.drop_table it drops the table, so no benchmark data is left behind;
+// with execution.cleanup.keep_data it logs the table and its row count, so the rows can
+// be inspected (e.g. with EXPLAIN ANALYZE) after the suite ends. Without either, the
+// table is left for the next run to clear. Failures are logged rather than failing a
+// run whose results are already in.
+func (r *Runner) cleanupAfterRun(ctx context.Context, run *workloadRun) {
+	cleanup := r.config.Execution.Cleanup
+	switch {
+	case cleanup.DropTable:
+		if err := run.db.DropBenchmarkTable(ctx); err != nil {
+			slog.Warn("Failed to drop benchmark table", "database", run.db.GetName(), "storage_type", run.storageType, "table", run.db.Table(), "error", err)
+			return
+		}
+		slog.Debug("Dropped benchmark table", "database", run.db.GetName(), "storage_type", run.storageType, "table", run.db.Table())
+	case cleanup.KeepData:
+		rows, err := run.db.CountRecords(ctx)
+		if err != nil {
+			slog.Warn("Benchmark table kept, but its rows could not be counted", "database", run.db.GetName(), "storage_type", run.storageType, "table", run.db.Table(), "error", err)
+			return
+		}
+		slog.Info("Benchmark table kept for inspection", "database", run.db.GetName(), "storage_type", run.storageType, "table", run.db.Table(), "rows", rows)
+	}
+}
+
+// pauseBetweenStorageTypes runs when the runner moves on from one storage type to another:
+// it sleeps for execution.inter_run_pause so writeback and checkpoints started by the
+// previous storage type settle, then drops the OS page cache if
+// execution.inter_run_drop_caches is set, so each storage type starts from a comparably
+// clean state. It returns early with the context's error when the run is cancelled.
+func (r *Runner) pauseBetweenStorageTypes(ctx context.Context, from, to string) error {
+	pause := r.config.GetInterRunPause()
+	dropCaches := r.config.Execution.InterRunDropCaches
+	if pause <= 0 && !dropCaches {
+		return nil
+	}
+
+	slog.Info("Pausing between storage types", "from", from, "to", to, "pause", pause, "drop_caches", dropCaches)
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if dropCaches {
+		if err := dropPageCache(); err != nil {
+			r.cacheWarning.Do(func() {
+				slog.Warn("Runs will not start with a cold page cache", "error", err)
+			})
+			return nil
+		}
+		slog.Debug("Dropped OS page cache", "from", from, "to", to)
+	}
+	return nil
+}
+
+// warnUnsupportedCleanup logs cleanup settings that are accepted but have no effect
+func (r *Runner) warnUnsupportedCleanup() {
+	if r.config.Execution.Cleanup.RestartServices {
+		slog.Warn("execution.cleanup.restart_services is not supported by the runner; restart services from the orchestration scripts instead")
+	}
+}
@@ -0,0 +1,47 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// connectChurnScenario measures how long fresh connections take to establish
+const connectChurnScenario = "connect_churn"
+
+func init() {
+	RegisterWorkload(connectChurnScenario, false, func(scenario config.ScenarioConfig, opts WorkloadOptions) (Workload, error) {
+		return &connectChurnWorkload{}, nil
+	})
+}
+
+// connectChurnWorkload opens and closes a new connection per operation, bypassing the
+// pool like a serverless function or a short-lived script would, and records the time
+// each took to establish as its latency. A backend has to start up and read its catalog
+// files for every connection, so storage that is slow to serve them shows up here even
+// when queries on pooled connections are fast.
+type connectChurnWorkload struct{}
+
+// Setup creates the benchmark table so the result's table stats can be read; the
+// workload itself never touches it
+func (w *connectChurnWorkload) Setup(ctx context.Context, db database.Database) error {
+	if err := db.CreateBenchmarkTable(ctx); err != nil {
+		return fmt.Errorf("failed to create benchmark table: %w", err)
+	}
+	return nil
+}
+
+// RunOp opens and closes one fresh connection
+func (w *connectChurnWorkload) RunOp(ctx context.Context, db database.Database, thread int) (OpResult, error) {
+	latency, err := db.ConnectFresh(ctx)
+	if err != nil {
+		return OpResult{Latency: latency}, err
+	}
+	return OpResult{Latency: latency, Items: 1}, nil
+}
+
+func (w *connectChurnWorkload) Teardown(ctx context.Context, db database.Database) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package benchmark
+
+import "sync"
+
+// roundBarrier keeps insert threads in lockstep for execution.deterministic: every thread
+// finishes its batch of a round before any starts the next, so all threads complete the
+// same number of batches. Once broken, by a thread leaving or the run ending, it
+// releases every waiter and stops the remaining threads.
+type roundBarrier struct {
+	mu         sync.Mutex
+	cond       *sync.Cond
+	parties    int
+	waiting    int
+	generation int
+	broken     bool
+}
+
+func newRoundBarrier(parties int) *roundBarrier {
+	b := &roundBarrier{parties: parties}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// Wait blocks until every thread has reached the end of the round. It returns false if
+// the barrier is broken, in which case the thread must stop.
+func (b *roundBarrier) Wait() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.broken {
+		return false
+	}
+	generation := b.generation
+	b.waiting++
+	if b.waiting == b.parties {
+		b.waiting = 0
+		b.generation++
+		b.cond.Broadcast()
+		return true
+	}
+	for generation == b.generation && !b.broken {
+		b.cond.Wait()
+	}
+	return !b.broken || generation != b.generation
+}
+
+// Break releases all waiting threads and makes every later Wait return false
+func (b *roundBarrier) Break() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.broken = true
+	b.cond.Broadcast()
+}
+
+// threadShares splits a count-bound workload of total rows into equal per-thread
+// shares, the first total%threads threads taking one extra row
+func threadShares(total int64, threads int) []int64 {
+	shares := make([]int64, threads)
+	for i := range shares {
+		shares[i] = total / int64(threads)
+		if int64(i) < total%int64(threads) {
+			shares[i]++
+		}
+	}
+	return shares
+}
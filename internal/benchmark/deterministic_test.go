@@ -0,0 +1,97 @@
+package benchmark
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestThreadShares(t *testing.T) {
+	for _, tc := range []struct {
+		total   int64
+		threads int
+		want    []int64
+	}{
+		{100, 4, []int64{25, 25, 25, 25}},
+		{10, 3, []int64{4, 3, 3}},
+		{11, 4, []int64{3, 3, 3, 2}},
+		{2, 5, []int64{1, 1, 0, 0, 0}},
+		{0, 2, []int64{0, 0}},
+		{7, 1, []int64{7}},
+	} {
+		shares := threadShares(tc.total, tc.threads)
+		var sum int64
+		for _, share := range shares {
+			sum += share
+		}
+		if sum != tc.total {
+			t.Errorf("threadShares(%d, %d) sums to %d, expected %d", tc.total, tc.threads, sum, tc.total)
+		}
+		if len(shares) != len(tc.want) {
+			t.Errorf("threadShares(%d, %d) = %v, expected %v", tc.total, tc.threads, shares, tc.want)
+			continue
+		}
+		for i := range shares {
+			if shares[i] != tc.want[i] {
+				t.Errorf("threadShares(%d, %d) = %v, expected %v", tc.total, tc.threads, shares, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRoundBarrierKeepsThreadsInLockstep(t *testing.T) {
+	const threads, rounds = 4, 50
+	b := newRoundBarrier(threads)
+	var finished [rounds]int32
+
+	var wg sync.WaitGroup
+	var lagging int32
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := 0; round < rounds; round++ {
+				atomic.AddInt32(&finished[round], 1)
+				if !b.Wait() {
+					t.Error("Barrier broke unexpectedly")
+					return
+				}
+				// No thread gets past the barrier before every thread finished the round
+				if atomic.LoadInt32(&finished[round]) != threads {
+					atomic.AddInt32(&lagging, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if lagging != 0 {
+		t.Errorf("Threads passed the barrier %d times before the round was finished", lagging)
+	}
+}
+
+func TestRoundBarrierBreakReleasesWaiters(t *testing.T) {
+	b := newRoundBarrier(3)
+	released := make(chan bool, 2)
+	for i := 0; i < 2; i++ {
+		go func() { released <- b.Wait() }()
+	}
+
+	// The third thread leaves instead of waiting
+	time.Sleep(10 * time.Millisecond)
+	b.Break()
+	for i := 0; i < 2; i++ {
+		select {
+		case ok := <-released:
+			if ok {
+				t.Error("Expected Wait to report the broken barrier")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Break did not release a waiting thread")
+		}
+	}
+	if b.Wait() {
+		t.Error("Expected Wait after Break to return false")
+	}
+}
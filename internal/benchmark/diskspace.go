@@ -0,0 +1,216 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// diskRowOverhead approximates what PostgreSQL stores per row beyond the record's text
+// and JSON: the tuple header, line pointer, and the fixed-width columns
+const diskRowOverhead = 64
+
+// diskWriteAmplification covers indexes and WAL, which take space alongside the table
+// until a checkpoint recycles the WAL
+const diskWriteAmplification = 2
+
+// diskNeed is the space the planned runs need on one data path
+type diskNeed struct {
+	path          string
+	labels        []string // storage labels writing to the path, for messages
+	bytes         int64    // largest estimate of any single run, since tables are cleared between runs
+	durationBound bool     // a run writes for a duration, so its volume is unknown
+}
+
+// checkDiskSpace estimates the space each data path needs before any benchmark runs and
+// compares it with the free space there, so a full disk shows up now rather than as
+// ENOSPC errors mid-run. Count-bound runs need their rows' estimated size; duration-bound
+// runs need execution.disk_check.min_free_percent of the filesystem free. Depending on
+// execution.disk_check.action, shortfalls are logged or abort the run.
+func (r *Runner) checkDiskSpace(ctx context.Context, tasks []task) error {
+	check := r.config.Execution.DiskCheck
+	if check.Action == config.DiskCheckOff {
+		return nil
+	}
+
+	needs, err := r.diskNeeds(ctx, tasks)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, need := range needs {
+		free, total, err := diskSpace(need.path)
+		if err != nil {
+			slog.Warn("Cannot check free disk space", "path", need.path, "error", err)
+			continue
+		}
+		labels := strings.Join(need.labels, ", ")
+
+		if need.bytes > 0 && free < uint64(need.bytes) {
+			problems = append(problems, fmt.Sprintf("%s (%s): about %s needed, %s free",
+				need.path, labels, database.FormatBytes(need.bytes), database.FormatBytes(int64(free))))
+			continue
+		}
+		if need.durationBound && total > 0 {
+			if percent := float64(free) / float64(total) * 100; percent < check.MinFreePercent {
+				problems = append(problems, fmt.Sprintf("%s (%s): %.1f%% free (%s), below min_free_percent %g for duration-bound runs",
+					need.path, labels, percent, database.FormatBytes(int64(free)), check.MinFreePercent))
+				continue
+			}
+		}
+		slog.Info("Checked free disk space", "path", need.path, "storage", labels, "free", database.FormatBytes(int64(free)), "estimated", database.FormatBytes(need.bytes))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if check.Action == config.DiskCheckAbort {
+		return fmt.Errorf("insufficient disk space (set execution.disk_check.action to warn or off to run anyway):\n  %s", strings.Join(problems, "\n  "))
+	}
+	for _, problem := range problems {
+		slog.Warn("Disk space may be insufficient", "check", problem)
+	}
+	return nil
+}
+
+// diskNeeds works out the data path and space requirement of every storage the tasks
+// write to. PostgreSQL data directories that are not visible on this host are skipped.
+func (r *Runner) diskNeeds(ctx context.Context, tasks []task) ([]*diskNeed, error) {
+	byPath := make(map[string]*diskNeed)
+	dataDirs := make(map[string]string) // PostgreSQL data directory by database and storage label
+	var order []string
+
+	for _, t := range tasks {
+		for _, storageType := range t.StorageTypes {
+			label := storageLabel(storageType, t.MountOption)
+
+			var path string
+			var bytes int64
+			durationBound := false
+			if t.Database == filesystemTarget {
+				dir, err := r.filesystemPath(storageType, t.MountOption)
+				if err != nil {
+					return nil, err
+				}
+				path = dir
+				if bytes, err = fsyncScenarioBytes(t.Scenario); err != nil {
+					return nil, err
+				}
+			} else {
+				key := t.Database + "/" + label
+				dir, ok := dataDirs[key]
+				if !ok {
+					dir = r.localDataDirectory(ctx, t.Database, storageType, t.MountOption)
+					dataDirs[key] = dir
+				}
+				if dir == "" {
+					continue
+				}
+				path = dir
+				var err error
+				if bytes, durationBound, err = workloadScenarioBytes(t.Scenario); err != nil {
+					return nil, err
+				}
+			}
+
+			need, ok := byPath[path]
+			if !ok {
+				need = &diskNeed{path: path}
+				byPath[path] = need
+				order = append(order, path)
+			}
+			if !containsString(need.labels, label) {
+				need.labels = append(need.labels, label)
+			}
+			if bytes > need.bytes {
+				need.bytes = bytes
+			}
+			need.durationBound = need.durationBound || durationBound
+		}
+	}
+
+	needs := make([]*diskNeed, len(order))
+	for i, path := range order {
+		needs[i] = byPath[path]
+		sort.Strings(needs[i].labels)
+	}
+	return needs, nil
+}
+
+// localDataDirectory returns a PostgreSQL server's data directory if it is visible on
+// this host, e.g. a bind-mounted volume, or "" if it cannot be checked from here
+func (r *Runner) localDataDirectory(ctx context.Context, db, storageType, mountOption string) string {
+	label := storageLabel(storageType, mountOption)
+	dbConfig, err := r.connectionConfig(db, storageType, mountOption)
+	if err != nil {
+		slog.Warn("Skipping disk space check", "storage", label, "error", err)
+		return ""
+	}
+	conn, err := database.ConnectPostgresDB(ctx, dbConfig, "postgresql-"+label)
+	if err != nil {
+		slog.Warn("Skipping disk space check", "storage", label, "error", err)
+		return ""
+	}
+	defer conn.Close()
+
+	dir, err := conn.DataDirectory()
+	if err != nil {
+		slog.Warn("Skipping disk space check", "storage", label, "error", err)
+		return ""
+	}
+	if _, err := os.Stat(dir); err != nil {
+		slog.Info("Skipping disk space check, data directory is not visible on this host", "storage", label, "data_directory", dir)
+		return ""
+	}
+	return dir
+}
+
+// workloadScenarioBytes estimates the space one run of a database scenario needs: its
+// prepopulated rows plus, for count-bound workloads, its target rows. Duration-bound
+// workloads write an unknown number of rows, which the returned flag reports.
+func workloadScenarioBytes(scenario config.ScenarioConfig) (int64, bool, error) {
+	if scenario.WorkloadName() == largeValueScenario {
+		params, _, err := largeValueParameters(scenario.Parameters)
+		if err != nil {
+			return 0, false, err
+		}
+		scenario.Parameters = params
+	}
+
+	rows, err := intParam(scenario.Parameters, "prepopulate_rows", 0)
+	if err != nil {
+		return 0, false, err
+	}
+	spec := workloads[scenario.WorkloadName()]
+	if spec.countBound {
+		target, err := intParam(scenario.Parameters, "target_rows", 0)
+		if err != nil {
+			return 0, false, err
+		}
+		rows += target
+	}
+
+	size := database.RecordSize(fmt.Sprintf("%v", scenario.Parameters["record_size"]))
+	bytes := int64(rows) * int64(size.ApproxBytes()+diskRowOverhead) * diskWriteAmplification
+	return bytes, !spec.countBound, nil
+}
+
+// fsyncScenarioBytes returns the space fsync_latency's per-thread files take
+func fsyncScenarioBytes(scenario config.ScenarioConfig) (int64, error) {
+	threads, err := intParam(scenario.Parameters, "threads", 1)
+	if err != nil {
+		return 0, err
+	}
+	fileSize, err := intParam(scenario.Parameters, "file_size", defaultFsyncFileSize)
+	if err != nil {
+		return 0, err
+	}
+	return int64(threads) * int64(fileSize), nil
+}
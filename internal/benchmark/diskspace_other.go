@@ -0,0 +1,10 @@
+//go:build !linux
+
+package benchmark
+
+import "fmt"
+
+// diskSpace is only implemented on Linux
+func diskSpace(path string) (free, total uint64, err error) {
+	return 0, 0, fmt.Errorf("disk space check is only supported on Linux")
+}
@@ -0,0 +1,27 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+)
+
+func TestWorkloadScenarioBytesUsesValueSize(t *testing.T) {
+	inserts, _, err := workloadScenarioBytes(config.ScenarioConfig{
+		Name:       "heavy_inserts",
+		Parameters: map[string]interface{}{"prepopulate_rows": 1000, "record_size": 65536},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, _, err := workloadScenarioBytes(config.ScenarioConfig{
+		Name:       largeValueScenario,
+		Parameters: map[string]interface{}{"prepopulate_rows": 1000, "value_size": 65536},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if large != inserts {
+		t.Errorf("Expected large_value rows of value_size to be estimated like record_size, got %d and %d", large, inserts)
+	}
+}
@@ -0,0 +1,187 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// Check is the outcome of one environment check performed by Diagnose
+type Check struct {
+	Name    string
+	Err     error  // nil when the check passed
+	Skipped string // why the check does not apply; empty when it ran
+}
+
+// Diagnose checks that the environment can run the configured benchmark: every enabled
+// database is reachable and can create tables, NFS data directories are really on NFS,
+// and cache clearing works if configured. It runs every check rather than stopping at
+// the first failure.
+func Diagnose(ctx context.Context, cfg *config.Config) []Check {
+	r := NewRunner(cfg)
+	var checks []Check
+
+	databases := cfg.GetEnabledDatabases()
+	sort.Strings(databases)
+	for _, db := range databases {
+		if db != "postgresql" {
+			checks = append(checks, Check{Name: db, Skipped: "not implemented by the runner"})
+			continue
+		}
+		for _, storageType := range cfg.Execution.StorageTypes {
+			for _, mountOption := range r.mountOptionsFor(db, storageType) {
+				checks = append(checks, r.diagnosePostgreSQL(ctx, storageType, mountOption)...)
+			}
+		}
+	}
+
+	if r.filesystemScenarioEnabled() {
+		for _, storageType := range cfg.Execution.StorageTypes {
+			for _, mountOption := range r.mountOptionsFor(filesystemTarget, storageType) {
+				checks = append(checks, r.diagnoseFilesystem(ctx, storageType, mountOption)...)
+			}
+		}
+	}
+
+	if cfg.Execution.Cleanup.ClearCaches || r.coldCacheScenarioEnabled() {
+		checks = append(checks, Check{Name: "clear caches: " + dropCachesHint, Err: checkDropCaches()})
+	}
+
+	return checks
+}
+
+// CheckConnections connects to every enabled database's endpoint for each storage type
+// and mount option variant, as a dry run with --check-connections does, without
+// creating tables or running any workload
+func CheckConnections(ctx context.Context, cfg *config.Config) []Check {
+	r := NewRunner(cfg)
+	var checks []Check
+
+	databases := cfg.GetEnabledDatabases()
+	sort.Strings(databases)
+	for _, db := range databases {
+		if db != "postgresql" {
+			checks = append(checks, Check{Name: db, Skipped: "not implemented by the runner"})
+			continue
+		}
+		for _, storageType := range cfg.Execution.StorageTypes {
+			for _, mountOption := range r.mountOptionsFor(db, storageType) {
+				checks = append(checks, r.checkPostgreSQLConnection(ctx, storageType, mountOption))
+			}
+		}
+	}
+	return checks
+}
+
+func (r *Runner) checkPostgreSQLConnection(ctx context.Context, storageType, mountOption string) Check {
+	label := storageLabel(storageType, mountOption)
+	dbConfig, err := r.connectionConfig("postgresql", storageType, mountOption)
+	if err != nil {
+		return Check{Name: fmt.Sprintf("postgresql (%s): connection configured", label), Err: err}
+	}
+	db, check := connectPostgreSQL(ctx, dbConfig, label)
+	if db != nil {
+		db.Close()
+	}
+	return check
+}
+
+// connectPostgreSQL opens a connection to one PostgreSQL endpoint, returning it along
+// with the check describing the attempt; the connection is nil if the check failed
+func connectPostgreSQL(ctx context.Context, dbConfig config.DatabaseConnectionConfig, label string) (*database.PostgresDB, Check) {
+	db, err := database.NewPostgresDB(ctx, dbConfig, "postgresql-"+label)
+	target := fmt.Sprintf("%s:%d", dbConfig.Host, dbConfig.Port)
+	if dbConfig.DSN != "" {
+		target = "dsn " + config.RedactDSN(dbConfig.DSN)
+	}
+	return db, Check{Name: fmt.Sprintf("postgresql (%s): reachable at %s", label, target), Err: err}
+}
+
+func (r *Runner) diagnosePostgreSQL(ctx context.Context, storageType, mountOption string) []Check {
+	label := storageLabel(storageType, mountOption)
+	prefix := fmt.Sprintf("postgresql (%s): ", label)
+	dbConfig, err := r.connectionConfig("postgresql", storageType, mountOption)
+	if err != nil {
+		return []Check{{Name: prefix + "connection configured", Err: err}}
+	}
+
+	db, check := connectPostgreSQL(ctx, dbConfig, label)
+	checks := []Check{check}
+	if db == nil {
+		return append(checks, Check{Name: prefix + "create and drop a table", Skipped: "database unreachable"})
+	}
+	defer db.Close()
+
+	checks = append(checks, Check{Name: prefix + "create and drop a table", Err: db.CheckCreateTable(ctx)})
+
+	if storageType == "nfs" {
+		check := Check{Name: prefix + "data directory is on NFS"}
+		if r.config.NFS.SkipMountCheck {
+			check.Skipped = "nfs.skip_mount_check is set"
+		} else {
+			check.Err = r.verifyPostgreSQLNFSMount(ctx, mountOption)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func (r *Runner) diagnoseFilesystem(ctx context.Context, storageType, mountOption string) []Check {
+	prefix := fmt.Sprintf("filesystem (%s): ", storageLabel(storageType, mountOption))
+
+	dir, err := r.filesystemPath(storageType, mountOption)
+	if err != nil {
+		return []Check{{Name: prefix + "path configured", Err: err}}
+	}
+
+	checks := []Check{{Name: prefix + dir + " is writable", Err: checkWritableDir(dir)}}
+	if storageType == "nfs" {
+		check := Check{Name: prefix + dir + " is on NFS"}
+		if r.config.NFS.SkipMountCheck {
+			check.Skipped = "nfs.skip_mount_check is set"
+		} else {
+			check.Err = r.verifyFilesystemNFSMount(ctx, mountOption)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// filesystemScenarioEnabled reports whether any enabled scenario bypasses the databases
+func (r *Runner) filesystemScenarioEnabled() bool {
+	for _, scenario := range r.config.GetEnabledScenarios() {
+		if scenario.WorkloadName() == fsyncLatencyScenario {
+			return true
+		}
+	}
+	return false
+}
+
+// coldCacheScenarioEnabled reports whether an enabled scenario runs a cold cache pass,
+// which drops caches whatever execution.cleanup.clear_caches says
+func (r *Runner) coldCacheScenarioEnabled() bool {
+	for _, scenario := range r.config.GetEnabledScenarios() {
+		if v, ok := scenario.Parameters["cache_mode"]; ok && fmt.Sprintf("%v", v) == cacheModeColdWarm {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWritableDir creates and removes a scratch file in dir
+func checkWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "nfsbench_doctor_*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(filepath.Clean(f.Name()))
+}
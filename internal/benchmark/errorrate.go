@@ -0,0 +1,56 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// errorRateCheckInterval is how often a running scenario's error rate is evaluated
+const errorRateCheckInterval = time.Second
+
+// errorRateMinAttempts is how many attempts a run needs before its error rate is
+// evaluated, so a few early failures cannot abort it
+const errorRateMinAttempts = 100
+
+// ErrErrorRateExceeded fails a scenario whose error rate rose above
+// execution.abort_on_error_rate while it ran
+var ErrErrorRateExceeded = errors.New("error rate exceeded abort_on_error_rate")
+
+// watchErrorRate evaluates the collector's error rate every errorRateCheckInterval until
+// ctx is done, and calls abort once it exceeds execution.abort_on_error_rate. Throughput
+// from a run that mostly fails, such as on a flapping NFS mount, is meaningless, so the
+// run stops rather than finishing its duration.
+func (r *Runner) watchErrorRate(ctx context.Context, label string, collector *metrics.Collector, abort func(error)) {
+	limit := r.config.Execution.AbortOnErrorRate
+	if limit <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(errorRateCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			operations, errs := collector.Attempts()
+			attempts := operations + int64(errs)
+			if attempts < errorRateMinAttempts {
+				continue
+			}
+			rate := float64(errs) / float64(attempts)
+			if rate <= limit {
+				continue
+			}
+
+			slog.Error("Aborting scenario run, error rate too high", "label", label, "errors", errs, "attempts", attempts, "error_rate", rate, "abort_on_error_rate", limit)
+			abort(fmt.Errorf("%w: %d of %d attempts failed (%.1f%%, limit %.1f%%)", ErrErrorRateExceeded, errs, attempts, rate*100, limit*100))
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package benchmark
+
+import (
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+)
+
+// RuntimeEstimate is the expected wall-clock time of a suite run, derived from the
+// configured durations rather than measured
+type RuntimeEstimate struct {
+	Tasks   int           // planned tasks
+	Runs    int           // measured runs, counting every repeat
+	Workers int           // task groups running at a time
+	Serial  time.Duration // time the runs would take one after another
+	Total   time.Duration // expected wall-clock time with task groups run in parallel
+}
+
+// EstimateRuntime estimates how long RunAll will take with cfg. Every run costs its
+// duration, which an interleaved run measures once per storage type; task groups are
+// then scheduled onto max_workers the way runTaskGroups runs them.
+// Every switch to another storage type adds inter_run_pause. Setup, cleanup, and
+// connection time are not included.
+func EstimateRuntime(cfg *config.Config) RuntimeEstimate {
+	r := NewRunner(cfg)
+	tasks := r.planTasks()
+	groups := groupTasks(tasks)
+
+	repeats := cfg.Execution.RepeatCount
+	if repeats < 1 {
+		repeats = 1
+	}
+	pause := cfg.GetInterRunPause()
+
+	estimate := RuntimeEstimate{
+		Tasks:   len(tasks),
+		Runs:    len(tasks) * repeats,
+		Workers: r.workerCount(len(groups)),
+	}
+
+	// Each group starts on whichever worker frees up first, in plan order
+	workers := make([]time.Duration, estimate.Workers)
+	for _, group := range groups {
+		var groupTime time.Duration
+		var previous string
+		for _, t := range group {
+			measured := time.Duration(t.Scenario.Duration) * time.Second * time.Duration(len(t.StorageTypes))
+			groupTime += time.Duration(repeats) * (measured + pause*time.Duration(storageSwitches(cfg, t)))
+			first, last := r.storageEnds(t)
+			if previous != "" && first != previous {
+				groupTime += pause
+			}
+			previous = last
+		}
+		estimate.Serial += groupTime
+
+		next := 0
+		for i := range workers {
+			if workers[i] < workers[next] {
+				next = i
+			}
+		}
+		workers[next] += groupTime
+	}
+	for _, busy := range workers {
+		if busy > estimate.Total {
+			estimate.Total = busy
+		}
+	}
+
+	return estimate
+}
+
+// storageSwitches returns how often one run of an interleaved task switches storage
+// type: once between each pair of adjacent storage types in every slice round
+func storageSwitches(cfg *config.Config, t task) int {
+	if len(t.StorageTypes) < 2 {
+		return 0
+	}
+	return sliceRounds(cfg, t) * (len(t.StorageTypes) - 1)
+}
+
+// sliceRounds returns how many slice rounds an interleaved task's duration is split into
+func sliceRounds(cfg *config.Config, t task) int {
+	duration := time.Duration(t.Scenario.Duration) * time.Second
+	slice := cfg.GetSliceDuration()
+	rounds := int((duration + slice - 1) / slice)
+	if rounds < 1 {
+		rounds = 1
+	}
+	return rounds
+}
@@ -0,0 +1,241 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// Filesystem scenarios bypass the databases and run against the configured paths
+const (
+	filesystemTarget     = "filesystem"
+	fsyncLatencyScenario = "fsync_latency"
+)
+
+// Default fsync_latency parameters
+const (
+	defaultFsyncWriteSize = 8192     // one PostgreSQL page
+	defaultFsyncFileSize  = 64 << 20 // writes wrap around within this size
+)
+
+// filesystemPath returns the directory used by filesystem scenarios for a storage type
+// and, for NFS, a mount option variant (empty for nfs.path)
+func (r *Runner) filesystemPath(storageType, mountOption string) (string, error) {
+	if storageType == "nfs" && mountOption != "" {
+		option, err := r.mountOption(mountOption)
+		if err != nil {
+			return "", err
+		}
+		if option.Path == "" {
+			return "", fmt.Errorf("NFS mount option variant %q has no path", mountOption)
+		}
+		return option.Path, nil
+	}
+	if storageType == "nfs" {
+		if r.config.NFS.Path == "" {
+			return "", fmt.Errorf("nfs.path must be set for filesystem scenarios")
+		}
+		return r.config.NFS.Path, nil
+	}
+	if r.config.NFS.DirectPath != "" {
+		return r.config.NFS.DirectPath, nil
+	}
+	return os.TempDir(), nil
+}
+
+// runFsyncLatency measures os.File.Sync latency on a storage type's path. Each thread
+// repeatedly writes write_size bytes to its own file and times only the fsync, giving a
+// lower bound on the durability cost any database on that storage has to pay.
+func (r *Runner) runFsyncLatency(ctx context.Context, storageType, mountOption string, scenario config.ScenarioConfig) (*ScenarioResult, error) {
+	dir, err := r.filesystemPath(storageType, mountOption)
+	if err != nil {
+		return nil, err
+	}
+	threads, err := intParam(scenario.Parameters, "threads", 1)
+	if err != nil {
+		return nil, err
+	}
+	writeSize, err := intParam(scenario.Parameters, "write_size", defaultFsyncWriteSize)
+	if err != nil {
+		return nil, err
+	}
+	fileSize, err := intParam(scenario.Parameters, "file_size", defaultFsyncFileSize)
+	if err != nil {
+		return nil, err
+	}
+	if threads < 1 || writeSize < 1 || fileSize < writeSize {
+		return nil, fmt.Errorf("invalid fsync_latency parameters: threads %d, write_size %d, file_size %d", threads, writeSize, fileSize)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	files := make([]*os.File, threads)
+	defer func() {
+		for _, f := range files {
+			if f != nil {
+				f.Close()
+				os.Remove(f.Name())
+			}
+		}
+	}()
+	for i := range files {
+		name := filepath.Join(dir, fmt.Sprintf("nfsbench_fsync_%d.dat", i))
+		if files[i], err = os.OpenFile(name, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644); err != nil {
+			return nil, fmt.Errorf("failed to create test file: %w", err)
+		}
+	}
+
+	slog.Info("Starting fsync benchmark",
+		"storage_type", storageType,
+		"mount_option", mountOption,
+		"path", dir,
+		"threads", threads,
+		"write_size", writeSize,
+		"duration_seconds", scenario.Duration)
+
+	duration := time.Duration(scenario.Duration) * time.Second
+	collector := r.newCollector(r.streamTimeline(filesystemTarget, scenario.Name, storageType, mountOption)...)
+	collector.Start()
+	defer r.live.track(&liveRun{
+		database:    filesystemTarget,
+		scenario:    scenario.Name,
+		storageType: storageType,
+		mountOption: mountOption,
+		collector:   collector,
+	})()
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var totalSyncs int64
+	var threadErr error
+	for _, f := range files {
+		wg.Add(1)
+		go func(f *os.File) {
+			defer wg.Done()
+			syncs, err := runFsyncThread(ctx, f, writeSize, fileSize, collector, r.newErrorBackoff())
+			mu.Lock()
+			totalSyncs += syncs
+			if err != nil && threadErr == nil {
+				threadErr = err
+				cancel()
+			}
+			mu.Unlock()
+		}(f)
+	}
+
+	label := "fsync-" + storageLabel(storageType, mountOption)
+	progressDone := make(chan struct{})
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		r.watchErrorRate(progressCtx, label, collector, func(err error) {
+			mu.Lock()
+			if threadErr == nil {
+				threadErr = err
+				cancel()
+			}
+			mu.Unlock()
+		})
+	}()
+	if reporter := r.newProgressReporter(label, duration, collector); reporter != nil {
+		go func() {
+			defer close(progressDone)
+			reporter.Run(progressCtx)
+		}()
+	} else {
+		close(progressDone)
+	}
+
+	wg.Wait()
+	stopProgress()
+	<-progressDone
+	<-watchDone
+	collector.End()
+	collector.SetThroughput(totalSyncs)
+	if threadErr != nil {
+		return nil, threadErr
+	}
+
+	results := collector.Results()
+	slog.Info("Fsync results",
+		"storage_type", storageType,
+		"syncs", results.TotalOperations,
+		"syncs_per_sec", results.OperationsPerSecond,
+		"mb_per_sec", results.MegabytesPerSecond(),
+		"avg_latency", results.AverageLatency,
+		"p99_latency", results.P99Latency,
+		"errors", results.ErrorCount)
+
+	return &ScenarioResult{
+		Name:        scenario.Name,
+		Database:    filesystemTarget,
+		StorageType: storageType,
+		MountOption: mountOption,
+		Duration:    results.TotalDuration,
+		Success:     true,
+		Metrics:     results,
+		DBStats: map[string]interface{}{
+			"path":          dir,
+			"write_size":    writeSize,
+			"bytes_written": totalSyncs * int64(writeSize),
+		},
+		collector: collector,
+	}, nil
+}
+
+// runFsyncThread writes and syncs until ctx is done, returning the number of successful
+// syncs. It returns an error if backoff gives up after too many failures in a row.
+func runFsyncThread(ctx context.Context, f *os.File, writeSize, fileSize int, collector *metrics.Collector, backoff *errorBackoff) (int64, error) {
+	buf := make([]byte, writeSize)
+	for i := range buf {
+		buf[i] = byte(i)
+	}
+
+	var syncs int64
+	var offset int64
+	for ctx.Err() == nil {
+		if _, err := f.WriteAt(buf, offset); err != nil {
+			collector.AddError(err)
+			if err := backoff.failed(ctx, err); err != nil {
+				return syncs, err
+			}
+			continue
+		}
+
+		start := time.Now()
+		err := f.Sync()
+		latency := time.Since(start)
+		if err != nil {
+			collector.AddError(err)
+			collector.AddFailedLatency(latency)
+			if err := backoff.failed(ctx, err); err != nil {
+				return syncs, err
+			}
+			continue
+		}
+
+		backoff.succeeded()
+		collector.AddLatency(latency)
+		collector.AddBytes(int64(writeSize))
+		syncs++
+
+		offset += int64(writeSize)
+		if offset+int64(writeSize) > int64(fileSize) {
+			offset = 0
+		}
+	}
+	return syncs, nil
+}
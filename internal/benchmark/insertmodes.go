@@ -0,0 +1,70 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+)
+
+// Values of the insert_mode parameter of the insert scenarios
+const (
+	insertModeInsert = "insert" // a prepared INSERT per row (the default)
+	insertModeCopy   = "copy"   // COPY FROM STDIN per transaction
+)
+
+// parseInsertModes returns the modes listed by the insert_mode parameter, or nil if it is
+// unset. Listing several modes runs the scenario once per mode.
+func parseInsertModes(params map[string]interface{}) ([]string, error) {
+	modes := stringListParam(params["insert_mode"])
+	seen := make(map[string]bool)
+	for _, mode := range modes {
+		if mode != insertModeInsert && mode != insertModeCopy {
+			return nil, fmt.Errorf("invalid insert_mode %q (valid: %s, %s)", mode, insertModeInsert, insertModeCopy)
+		}
+		if seen[mode] {
+			return nil, fmt.Errorf("insert_mode %q listed twice", mode)
+		}
+		seen[mode] = true
+	}
+	return modes, nil
+}
+
+// modeLabel appends a result's insert mode, if any, to its storage label, so the modes of
+// one storage type are told apart in results files
+func modeLabel(label, mode string) string {
+	if mode == "" {
+		return label
+	}
+	return label + "_" + mode
+}
+
+// resultLabel returns the label a result is keyed by in results files
+func resultLabel(result *ScenarioResult) string {
+	return modeLabel(storageLabel(result.StorageType, result.MountOption), result.InsertMode)
+}
+
+// runInsertModes runs a scenario whose insert_mode lists several modes once per mode, in
+// the listed order, each with a freshly prepared table. Results record their mode in
+// InsertMode, making it a dimension next to the storage type.
+func (r *Runner) runInsertModes(ctx context.Context, storageTypes []string, mountOption string, scenario config.ScenarioConfig, spec workloadSpec, modes []string) ([]*ScenarioResult, error) {
+	var scenarioResults []*ScenarioResult
+	for _, mode := range modes {
+		modeScenario := scenario
+		modeScenario.Parameters = make(map[string]interface{}, len(scenario.Parameters))
+		for key, value := range scenario.Parameters {
+			modeScenario.Parameters[key] = value
+		}
+		modeScenario.Parameters["insert_mode"] = mode
+
+		modeResults, err := r.runWorkload(ctx, storageTypes, mountOption, modeScenario, spec)
+		if err != nil {
+			return nil, fmt.Errorf("insert_mode %s: %w", mode, err)
+		}
+		for _, result := range modeResults {
+			result.InsertMode = mode
+		}
+		scenarioResults = append(scenarioResults, modeResults...)
+	}
+	return scenarioResults, nil
+}
@@ -0,0 +1,303 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// heavyInsertsScenario inserts batches of generated records for the scenario duration
+const heavyInsertsScenario = "heavy_inserts"
+
+func init() {
+	RegisterWorkload(heavyInsertsScenario, false, func(scenario config.ScenarioConfig, opts WorkloadOptions) (Workload, error) {
+		return newInsertWorkload(scenario, opts)
+	})
+}
+
+// insertWorkload inserts batches of generated records into the benchmark table. Its
+// parameters are batch_size, record_size, rows_per_commit, index_columns,
+// prepopulate_rows, reuse_existing_data, pregenerate_batches, verify_durability, and
+// insert_mode.
+type insertWorkload struct {
+	batchSize        int
+	rowsPerCommit    int // rows per transaction within a batch; 0 commits each batch once
+	recordSize       database.RecordSize
+	indexColumns     []string
+	resetTable       bool
+	copy             bool // insert_mode: copy loads batches with COPY instead of an INSERT per row
+	prepopulate      prepopulation
+	rngs             []*rand.Rand              // per-thread record generators
+	pregenerate      int                       // pregenerate_batches: batches generated during setup; 0 generates each batch fresh
+	seed             int64                     // seeds the pregenerated batches
+	pool             *database.BatchPool       // pregenerated batches, once set up
+	poolNext         []int                     // per-thread index of the next pool batch
+	checksums        []database.RecordChecksum // per-thread checksums of inserted batches, with verify_data
+	initial          database.RecordChecksum   // checksum of the rows present before measurement, with verify_data
+	verifyDurability int                       // verify_durability: committed rows per batch read back; 0 disables
+	durability       *DurabilityCheck          // rows read back so far, with verify_durability
+	durabilityMu     sync.Mutex
+}
+
+func newInsertWorkload(scenario config.ScenarioConfig, opts WorkloadOptions) (*insertWorkload, error) {
+	batchSize, err := intParam(scenario.Parameters, "batch_size", 0)
+	if err != nil {
+		return nil, err
+	}
+	if batchSize < 1 {
+		return nil, fmt.Errorf("batch_size must be at least 1, got %d", batchSize)
+	}
+	recordSize := database.RecordSize(fmt.Sprintf("%v", scenario.Parameters["record_size"]))
+	if err := recordSize.Validate(); err != nil {
+		return nil, err
+	}
+	rowsPerCommit, err := intParam(scenario.Parameters, "rows_per_commit", 0)
+	if err != nil {
+		return nil, err
+	}
+	if rowsPerCommit < 0 {
+		return nil, fmt.Errorf("rows_per_commit must not be negative, got %d", rowsPerCommit)
+	}
+	prepopulate, err := parsePrepopulation(scenario.Parameters, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+	pregenerate, err := intParam(scenario.Parameters, "pregenerate_batches", 0)
+	if err != nil {
+		return nil, err
+	}
+	if pregenerate < 0 {
+		return nil, fmt.Errorf("pregenerate_batches must not be negative, got %d", pregenerate)
+	}
+	verifyDurability, err := intParam(scenario.Parameters, "verify_durability", 0)
+	if err != nil {
+		return nil, err
+	}
+	if verifyDurability < 0 {
+		return nil, fmt.Errorf("verify_durability must not be negative, got %d", verifyDurability)
+	}
+	// The runner splits a list of modes into one run per mode
+	modes, err := parseInsertModes(scenario.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	if len(modes) > 1 {
+		return nil, fmt.Errorf("insert_mode must be a single mode here, got %v", modes)
+	}
+	copyMode := len(modes) == 1 && modes[0] == insertModeCopy
+	if copyMode && verifyDurability > 0 {
+		return nil, fmt.Errorf("verify_durability requires insert_mode %s, since COPY does not return the ids of its rows", insertModeInsert)
+	}
+
+	// One generator per thread, seeded identically for every storage type so each
+	// storage type receives the same record content
+	rngs := make([]*rand.Rand, opts.Threads)
+	for i := range rngs {
+		rngs[i] = rand.New(rand.NewSource(opts.Seed + int64(i)))
+	}
+
+	w := &insertWorkload{
+		batchSize:     batchSize,
+		rowsPerCommit: rowsPerCommit,
+		recordSize:    recordSize,
+		indexColumns:  stringListParam(scenario.Parameters["index_columns"]),
+		resetTable:    opts.ResetTable,
+		copy:          copyMode,
+		prepopulate:   prepopulate,
+		rngs:          rngs,
+		pregenerate:   pregenerate,
+		seed:          opts.Seed,
+	}
+	if opts.Verify {
+		w.checksums = make([]database.RecordChecksum, opts.Threads)
+	}
+	if verifyDurability > 0 {
+		w.verifyDurability = verifyDurability
+		w.durability = &DurabilityCheck{}
+	}
+	return w, nil
+}
+
+// Setup creates the benchmark table and prepares it for the run
+func (w *insertWorkload) Setup(ctx context.Context, db database.Database) error {
+	if err := db.CreateBenchmarkTable(ctx); err != nil {
+		return fmt.Errorf("failed to create benchmark table: %w", err)
+	}
+	return w.prepareTable(ctx, db)
+}
+
+// prepareTable empties the benchmark table, prepopulates it if configured, and builds
+// the configured indexes. With reuse_existing_data, a table that already holds enough
+// rows is kept as is.
+func (w *insertWorkload) prepareTable(ctx context.Context, db database.Database) error {
+	reused, err := w.prepopulate.reusable(ctx, db)
+	if err != nil {
+		return err
+	}
+	if !reused {
+		if w.resetTable {
+			err = db.ResetBenchmarkTable(ctx)
+		} else {
+			err = db.ClearBenchmarkTable(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to clear benchmark table: %w", err)
+		}
+		// Loading before building indexes is faster than maintaining them row by row
+		if err := w.prepopulate.load(ctx, db, w.batchSize, w.recordSize); err != nil {
+			return err
+		}
+	}
+
+	if err := db.EnsureIndexes(ctx, w.indexColumns); err != nil {
+		return fmt.Errorf("failed to set up indexes: %w", err)
+	}
+
+	if w.pregenerate > 0 && w.pool == nil {
+		// The pool has a generator of its own, like prepopulation, seeded the same for
+		// every storage type
+		w.pool = database.NewBatchPool(rand.New(rand.NewSource(w.seed-2)), w.pregenerate, w.batchSize, w.recordSize)
+		w.poolNext = make([]int, len(w.rngs))
+		slog.Info("Pregenerated insert batches", "batches", w.pregenerate, "batch_size", w.batchSize)
+	}
+	for thread := range w.poolNext {
+		w.poolNext[thread] = thread
+	}
+
+	w.initial = database.RecordChecksum{}
+	if w.checksums != nil && w.prepopulate.rows > 0 {
+		if w.initial, err = db.ChecksumRecords(ctx); err != nil {
+			return fmt.Errorf("failed to checksum prepopulated table: %w", err)
+		}
+	}
+
+	slog.Info("Prepared benchmark table",
+		"database", db.GetName(),
+		"batch_size", w.batchSize,
+		"rows_per_commit", w.rowsPerCommit,
+		"record_size", w.recordSize,
+		"copy", w.copy,
+		"indexes", w.indexColumns,
+		"prepopulate_rows", w.prepopulate.rows)
+	return nil
+}
+
+// RunOp generates and inserts one batch
+func (w *insertWorkload) RunOp(ctx context.Context, db database.Database, thread int) (OpResult, error) {
+	return w.insert(ctx, db, thread, w.nextBatch(thread, w.batchSize))
+}
+
+// nextBatch returns a thread's next batch of n records, at most batch_size. Without
+// pregenerate_batches it is generated fresh; otherwise it is the thread's next batch from
+// the pool, with threads starting at different batches so they insert different content
+// at any one time.
+func (w *insertWorkload) nextBatch(thread, n int) []database.BenchmarkRecord {
+	if w.pool == nil {
+		return database.GenerateBenchmarkRecords(w.rngs[thread], n, w.recordSize)
+	}
+	batch := w.pool.Batch(w.poolNext[thread])
+	w.poolNext[thread] += len(w.poolNext)
+	return batch[:n]
+}
+
+// insert inserts a thread's batch, timing it apart from record generation, checksums,
+// and durability checks. When it fails, the result still counts the rows committed
+// before the failure, which are the first Items rows of the batch.
+func (w *insertWorkload) insert(ctx context.Context, db database.Database, thread int, batch []database.BenchmarkRecord) (OpResult, error) {
+	var ids []int64
+	var timing database.BatchTiming
+	var err error
+	start := time.Now()
+	switch {
+	case w.copy:
+		timing, err = db.CopyBatch(ctx, batch, w.rowsPerCommit)
+	case w.durability != nil:
+		ids, timing, err = db.InsertBatchIDs(ctx, batch, w.rowsPerCommit)
+	default:
+		timing, err = db.InsertBatch(ctx, batch, w.rowsPerCommit)
+	}
+	latency := time.Since(start)
+	// Rows of transactions that committed before a failure must be durable too
+	if len(ids) > 0 {
+		w.checkDurability(ctx, db, ids)
+	}
+	// With rows_per_commit, the transactions before a failed one stay committed
+	committed := batch[:timing.Committed]
+	if w.checksums != nil {
+		w.checksums[thread].Add(committed)
+	}
+	if err != nil {
+		return OpResult{Latency: latency, Items: int64(len(committed)), Bytes: database.PayloadBytes(committed)}, err
+	}
+
+	return OpResult{
+		Latency: latency,
+		Items:   int64(len(batch)),
+		Bytes:   database.PayloadBytes(batch),
+		Phases: map[string]time.Duration{
+			PhaseConnAcquire: timing.Acquire,
+			PhaseExecute:     timing.Execute,
+			PhaseWrite:       timing.Write,
+			PhaseCommit:      timing.Commit,
+		},
+	}, nil
+}
+
+// checkDurability reads back up to verify_durability of the ids a batch just committed,
+// chosen at random, and counts those the database can't find
+func (w *insertWorkload) checkDurability(ctx context.Context, db database.Database, ids []int64) {
+	sample := ids
+	if len(ids) > w.verifyDurability {
+		sample = make([]int64, w.verifyDurability)
+		for i, j := range rand.Perm(len(ids))[:w.verifyDurability] {
+			sample[i] = ids[j]
+		}
+	}
+
+	missing, err := db.MissingRecordIDs(ctx, sample)
+	w.durabilityMu.Lock()
+	defer w.durabilityMu.Unlock()
+	if err != nil {
+		w.durability.CheckErrors++
+		slog.Debug("Durability check failed", "database", db.GetName(), "error", err)
+		return
+	}
+	w.durability.add(len(sample), missing)
+	if len(missing) > 0 {
+		slog.Error("Committed rows are missing", "database", db.GetName(), "sampled", len(sample), "missing", len(missing), "ids", missing)
+	}
+}
+
+// Durability returns the rows read back with verify_durability, or nil without it
+func (w *insertWorkload) Durability() *DurabilityCheck {
+	return w.durability
+}
+
+// Verify compares the checksum of the committed rows with the table's. That includes
+// the transactions of a batch that committed before a later one failed (rows_per_commit).
+func (w *insertWorkload) Verify(ctx context.Context, db database.Database) (*Verification, error) {
+	if w.checksums == nil {
+		return nil, fmt.Errorf("workload was built without verification")
+	}
+
+	expected := w.initial
+	for _, checksum := range w.checksums {
+		expected.Merge(checksum)
+	}
+	actual, err := db.ChecksumRecords(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checksum benchmark table: %w", err)
+	}
+	return &Verification{Expected: expected, Actual: actual, Match: expected == actual}, nil
+}
+
+// Teardown leaves the table in place for inspection; cleanup handles it
+func (w *insertWorkload) Teardown(ctx context.Context, db database.Database) error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// largeValueScenario inserts rows whose values PostgreSQL stores out of line in TOAST
+const largeValueScenario = "large_value"
+
+const (
+	// defaultLargeValueSize is the data_text size large_value inserts without value_size
+	defaultLargeValueSize = 2 << 20
+	// minLargeValueSize is the smallest value_size that is sure to be moved to TOAST,
+	// whose threshold is about 2kB per row with the default 8kB page
+	minLargeValueSize = 8192
+	// defaultLargeValueBatchSize is the batch size large_value uses without batch_size
+	defaultLargeValueBatchSize = 10
+)
+
+func init() {
+	RegisterWorkload(largeValueScenario, false, func(scenario config.ScenarioConfig, opts WorkloadOptions) (Workload, error) {
+		return newLargeValueWorkload(scenario, opts)
+	})
+}
+
+// largeValueWorkload inserts like heavy_inserts, but with a data_text of value_size
+// bytes per row, so every value is written out of line into the table's TOAST relation
+// instead of the heap. The text is random, so TOAST compression does not shrink it. It
+// takes the heavy_inserts parameters except record_size, which value_size replaces.
+type largeValueWorkload struct {
+	*insertWorkload
+	valueSize int
+}
+
+func newLargeValueWorkload(scenario config.ScenarioConfig, opts WorkloadOptions) (*largeValueWorkload, error) {
+	params, valueSize, err := largeValueParameters(scenario.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	scenario.Parameters = params
+
+	inserts, err := newInsertWorkload(scenario, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &largeValueWorkload{insertWorkload: inserts, valueSize: valueSize}, nil
+}
+
+// largeValueParameters returns the heavy_inserts parameters a large_value scenario runs
+// with, value_size passed through as record_size, and the value size
+func largeValueParameters(parameters map[string]interface{}) (map[string]interface{}, int, error) {
+	valueSize, err := intParam(parameters, "value_size", defaultLargeValueSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if valueSize < minLargeValueSize {
+		return nil, 0, fmt.Errorf("value_size must be at least %d bytes to be stored in TOAST, got %d", minLargeValueSize, valueSize)
+	}
+	if _, ok := parameters["record_size"]; ok {
+		return nil, 0, fmt.Errorf("large_value takes value_size instead of record_size")
+	}
+
+	params := make(map[string]interface{}, len(parameters)+2)
+	for key, value := range parameters {
+		params[key] = value
+	}
+	params["record_size"] = valueSize
+	if _, ok := params["batch_size"]; !ok {
+		params["batch_size"] = defaultLargeValueBatchSize
+	}
+	return params, valueSize, nil
+}
+
+// toastDatabase is a database that can report the size of the benchmark table's TOAST
+type toastDatabase interface {
+	ToastSize(ctx context.Context) (int64, error)
+}
+
+// Stats records the value size next to the TOAST size the database reports
+func (w *largeValueWorkload) Stats(ctx context.Context, db database.Database) map[string]interface{} {
+	stats := map[string]interface{}{"value_size_bytes": w.valueSize}
+	if tdb, ok := db.(toastDatabase); ok {
+		size, err := tdb.ToastSize(ctx)
+		if err != nil {
+			slog.Warn("Failed to get TOAST size", "error", err)
+		} else {
+			stats["toast_size_bytes"] = size
+		}
+	}
+	return stats
+}
@@ -0,0 +1,165 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// metricsShutdownTimeout bounds how long the metrics server waits for in-flight scrapes
+// when the run finishes
+const metricsShutdownTimeout = 5 * time.Second
+
+// liveMetrics tracks the collectors of the runs being measured, for the metrics endpoint
+// (reporting.metrics_addr). Runs register their collector for each measurement window,
+// so with interleaving or repeats the counters of a run restart with every slice, which
+// Prometheus' rate() treats as counter resets.
+type liveMetrics struct {
+	mu      sync.Mutex
+	runs    map[string]*liveRun
+	started time.Time
+}
+
+// liveRun is one run being measured: a scenario on one storage label
+type liveRun struct {
+	database    string
+	scenario    string
+	storageType string
+	mountOption string
+	collector   *metrics.Collector
+}
+
+// key identifies the run's series
+func (l *liveRun) key() string {
+	return strings.Join([]string{l.database, l.scenario, l.storageType, l.mountOption}, "\x00")
+}
+
+func newLiveMetrics() *liveMetrics {
+	return &liveMetrics{runs: make(map[string]*liveRun), started: time.Now()}
+}
+
+// track exposes a run's collector until the returned function is called. It does nothing
+// without a metrics endpoint.
+func (m *liveMetrics) track(run *liveRun) func() {
+	if m == nil {
+		return func() {}
+	}
+	m.mu.Lock()
+	m.runs[run.key()] = run
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		if m.runs[run.key()] == run {
+			delete(m.runs, run.key())
+		}
+		m.mu.Unlock()
+	}
+}
+
+// ServeHTTP writes the tracked runs in the Prometheus text exposition format
+func (m *liveMetrics) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	m.mu.Lock()
+	runs := make([]*liveRun, 0, len(m.runs))
+	for _, run := range m.runs {
+		runs = append(runs, run)
+	}
+	m.mu.Unlock()
+	sort.Slice(runs, func(i, j int) bool { return runs[i].key() < runs[j].key() })
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w, runs)
+}
+
+// write renders the metrics of the given runs
+func (m *liveMetrics) write(w io.Writer, runs []*liveRun) {
+	fmt.Fprintf(w, "# HELP nfsbench_up Whether a benchmark run is in progress.\n# TYPE nfsbench_up gauge\nnfsbench_up 1\n")
+	fmt.Fprintf(w, "# HELP nfsbench_uptime_seconds Time since the benchmark run started.\n# TYPE nfsbench_uptime_seconds gauge\nnfsbench_uptime_seconds %g\n", time.Since(m.started).Seconds())
+	fmt.Fprintf(w, "# HELP nfsbench_active_runs Scenario runs currently being measured.\n# TYPE nfsbench_active_runs gauge\nnfsbench_active_runs %d\n", len(runs))
+	if len(runs) == 0 {
+		return
+	}
+
+	snapshots := make([]metrics.Snapshot, len(runs))
+	for i, run := range runs {
+		snapshots[i] = run.collector.Snapshot()
+	}
+
+	series := func(name, kind, help string, value func(metrics.Snapshot) float64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for i, run := range runs {
+			fmt.Fprintf(w, "%s{%s} %g\n", name, run.labels(), value(snapshots[i]))
+		}
+	}
+	series("nfsbench_operations_total", "counter", "Successful operations in the current measurement window.",
+		func(s metrics.Snapshot) float64 { return float64(s.Operations) })
+	series("nfsbench_errors_total", "counter", "Failed operations in the current measurement window.",
+		func(s metrics.Snapshot) float64 { return float64(s.Errors) })
+	series("nfsbench_ops_per_second", "gauge", "Average operations per second over the current measurement window.",
+		func(s metrics.Snapshot) float64 {
+			if s.Elapsed <= 0 {
+				return 0
+			}
+			return float64(s.Operations) / s.Elapsed.Seconds()
+		})
+	series("nfsbench_elapsed_seconds", "gauge", "Length of the current measurement window so far.",
+		func(s metrics.Snapshot) float64 { return s.Elapsed.Seconds() })
+
+	fmt.Fprintf(w, "# HELP nfsbench_latency_seconds Operation latency percentiles over the current measurement window.\n# TYPE nfsbench_latency_seconds gauge\n")
+	for i, run := range runs {
+		for _, q := range []struct {
+			quantile string
+			latency  time.Duration
+		}{
+			{"0.5", snapshots[i].P50Latency},
+			{"0.95", snapshots[i].P95Latency},
+			{"0.99", snapshots[i].P99Latency},
+			{"0.999", snapshots[i].P999Latency},
+		} {
+			fmt.Fprintf(w, "nfsbench_latency_seconds{%s,quantile=%q} %g\n", run.labels(), q.quantile, q.latency.Seconds())
+		}
+	}
+}
+
+// labels renders the run's Prometheus labels
+func (l *liveRun) labels() string {
+	return fmt.Sprintf("database=%q,scenario=%q,storage_type=%q,mount_option=%q", l.database, l.scenario, l.storageType, l.mountOption)
+}
+
+// serveMetrics starts the metrics endpoint on addr and returns a function that shuts it
+// down. Failing to listen fails the run up front rather than leaving it unobservable.
+func (r *Runner) serveMetrics(addr string) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on metrics address %s: %w", addr, err)
+	}
+
+	r.live = newLiveMetrics()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.live)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Metrics endpoint failed", "error", err)
+		}
+	}()
+	slog.Info("Serving live metrics", "url", "http://"+listener.Addr().String()+"/metrics")
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			slog.Warn("Metrics endpoint did not shut down cleanly", "error", err)
+		}
+		r.live = nil
+	}, nil
+}
@@ -0,0 +1,74 @@
+package benchmark
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+	"github.com/l22io/nfsvsdirectbench/internal/nfs"
+)
+
+// procMountStats lists per-mount NFS client statistics
+const procMountStats = "/proc/self/mountstats"
+
+// snapshotNFSStats reads the NFS client statistics of the mount holding the database's
+// data directory. The backend process reading the file shares the server's mounts.
+func snapshotNFSStats(db *database.PostgresDB) (*nfs.MountStats, error) {
+	dataDir, err := db.DataDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data_directory: %w", err)
+	}
+
+	contents, err := readServerFile(db, dataDir, procMountStats)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := nfs.ParseMountStats(strings.NewReader(contents))
+	if err != nil {
+		return nil, err
+	}
+
+	stats, ok := nfs.FindMountStats(all, dataDir)
+	if !ok {
+		return nil, fmt.Errorf("no NFS mount statistics found for %s", dataDir)
+	}
+	return &stats, nil
+}
+
+// addNFSStats records the NFS operation deltas since before into dbStats: per-operation
+// counters for the tracked operations under "nfs_ops", and the retransmit and major
+// timeout totals across all operations
+func addNFSStats(dbStats map[string]interface{}, db *database.PostgresDB, before *nfs.MountStats) {
+	after, err := snapshotNFSStats(db)
+	if err != nil {
+		slog.Warn("Failed to read NFS statistics", "error", err)
+		return
+	}
+
+	deltas := after.Sub(*before)
+	ops := make(map[string]interface{}, len(nfs.TrackedOps))
+	for _, op := range nfs.TrackedOps {
+		d := deltas[op]
+		ops[op] = map[string]int64{
+			"ops":            d.Ops,
+			"retransmits":    d.Retransmits(),
+			"major_timeouts": d.MajorTimeouts,
+			"bytes_sent":     d.BytesSent,
+			"bytes_recv":     d.BytesRecv,
+			"rtt_ms":         d.RTTMs,
+			"execute_ms":     d.ExecuteMs,
+		}
+	}
+
+	var retransmits, majorTimeouts int64
+	for _, d := range deltas {
+		retransmits += d.Retransmits()
+		majorTimeouts += d.MajorTimeouts
+	}
+
+	dbStats["nfs_ops"] = ops
+	dbStats["nfs_retransmits"] = retransmits
+	dbStats["nfs_major_timeouts"] = majorTimeouts
+}
@@ -0,0 +1,78 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// partitionedInsertsScenario runs heavy_inserts against a partitioned benchmark table
+const partitionedInsertsScenario = "partitioned_inserts"
+
+// defaultPartitions is the partition count used when the partitions parameter is unset
+const defaultPartitions = 8
+
+func init() {
+	RegisterWorkload(partitionedInsertsScenario, false, func(scenario config.ScenarioConfig, opts WorkloadOptions) (Workload, error) {
+		return newPartitionedInsertWorkload(scenario, opts)
+	})
+}
+
+// partitionedDatabase is a database that can hold a partitioned benchmark table
+type partitionedDatabase interface {
+	CreatePartitionedBenchmarkTable(ctx context.Context, partitions int) error
+	PartitionSizes(ctx context.Context) (map[string]int64, error)
+}
+
+// partitionedInsertWorkload inserts like heavy_inserts, but into a benchmark table
+// hash-partitioned on id, so each batch pays for routing rows across the partitions.
+// It takes the heavy_inserts parameters plus partitions.
+type partitionedInsertWorkload struct {
+	*insertWorkload
+	partitions int
+}
+
+func newPartitionedInsertWorkload(scenario config.ScenarioConfig, opts WorkloadOptions) (*partitionedInsertWorkload, error) {
+	partitions, err := intParam(scenario.Parameters, "partitions", defaultPartitions)
+	if err != nil {
+		return nil, err
+	}
+	if partitions < 1 {
+		return nil, fmt.Errorf("partitions must be at least 1, got %d", partitions)
+	}
+
+	inserts, err := newInsertWorkload(scenario, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &partitionedInsertWorkload{insertWorkload: inserts, partitions: partitions}, nil
+}
+
+// Setup creates the partitioned benchmark table and prepares it for the run
+func (w *partitionedInsertWorkload) Setup(ctx context.Context, db database.Database) error {
+	pdb, ok := db.(partitionedDatabase)
+	if !ok {
+		return fmt.Errorf("%s does not support partitioned tables", db.GetName())
+	}
+	if err := pdb.CreatePartitionedBenchmarkTable(ctx, w.partitions); err != nil {
+		return err
+	}
+	return w.prepareTable(ctx, db)
+}
+
+// Stats reports the size of each partition, so skew across partitions shows up
+func (w *partitionedInsertWorkload) Stats(ctx context.Context, db database.Database) map[string]interface{} {
+	stats := map[string]interface{}{"partitions": w.partitions}
+	if pdb, ok := db.(partitionedDatabase); ok {
+		sizes, err := pdb.PartitionSizes(ctx)
+		if err != nil {
+			slog.Warn("Failed to get partition sizes", "error", err)
+		} else {
+			stats["partition_sizes_bytes"] = sizes
+		}
+	}
+	return stats
+}
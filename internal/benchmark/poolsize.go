@@ -0,0 +1,38 @@
+package benchmark
+
+import (
+	"log/slog"
+)
+
+// checkPoolSizes warns, before any benchmark runs, about scenarios whose threads
+// outnumber the connections an explicit pool.max_open allows. Surplus threads queue for
+// a pooled connection, so the run measures pool contention rather than storage, and the
+// time queued is counted as latency. Without max_open the pool is sized to the threads
+// of each scenario, so only explicit settings can fall short. connect_churn opens its
+// connections outside the pool and is not checked. Invalid settings are left for the
+// scenario itself to report.
+func (r *Runner) checkPoolSizes(tasks []task) {
+	for _, t := range tasks {
+		if t.Database == filesystemTarget || t.Scenario.WorkloadName() == connectChurnScenario {
+			continue
+		}
+		threads, err := intParam(t.Scenario.Parameters, "threads", 1)
+		if err != nil {
+			continue
+		}
+		for _, storageType := range t.StorageTypes {
+			dbConfig, err := r.connectionConfig(t.Database, storageType, t.MountOption)
+			if err != nil {
+				continue
+			}
+			if maxOpen := dbConfig.Pool.MaxOpen; maxOpen > 0 && threads > maxOpen {
+				slog.Warn("Scenario threads exceed the connection pool; results will measure pool contention, not storage",
+					"task", t.String(),
+					"storage_type", storageLabel(storageType, t.MountOption),
+					"threads", threads,
+					"max_open", maxOpen,
+					"hint", "raise pool.max_open to at least the thread count, or remove it to size the pool automatically")
+			}
+		}
+	}
+}
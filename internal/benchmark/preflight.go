@@ -0,0 +1,144 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+	"github.com/l22io/nfsvsdirectbench/internal/nfs"
+)
+
+// procMounts is where the kernel lists mounted filesystems
+const procMounts = "/proc/mounts"
+
+// verifyNFSMounts confirms, before any benchmark runs, that each database's NFS data
+// directory is really on an NFS mount with one of the configured versions. Without it
+// a misconfigured server silently benchmarks local disk.
+func (r *Runner) verifyNFSMounts(ctx context.Context, tasks []task) error {
+	if r.config.NFS.SkipMountCheck {
+		slog.Warn("Skipping NFS mount check")
+		return nil
+	}
+
+	r.mountVersions = make(map[string]string)
+	checked := make(map[string]bool)
+	for _, t := range tasks {
+		key := t.Database + "/" + t.MountOption
+		if checked[key] || !containsString(t.StorageTypes, "nfs") {
+			continue
+		}
+		checked[key] = true
+
+		verify := r.verifyPostgreSQLNFSMount
+		if t.Database == filesystemTarget {
+			verify = r.verifyFilesystemNFSMount
+		}
+		if err := verify(ctx, t.MountOption); err != nil {
+			name := t.Database
+			if t.MountOption != "" {
+				name += " (" + t.MountOption + ")"
+			}
+			return fmt.Errorf("%s NFS mount check failed: %w (use --no-mount-check to skip)", name, err)
+		}
+	}
+	return nil
+}
+
+// verifyPostgreSQLNFSMount checks the data directory of the NFS PostgreSQL server for a
+// mount option variant (empty for the default NFS server) against the server's mount table.
+func (r *Runner) verifyPostgreSQLNFSMount(ctx context.Context, mountOption string) error {
+	dbConfig, err := r.connectionConfig("postgresql", "nfs", mountOption)
+	if err != nil {
+		return err
+	}
+	db, err := database.ConnectPostgresDB(ctx, dbConfig, "postgresql-"+storageLabel("nfs", mountOption))
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	dataDir, err := db.DataDirectory()
+	if err != nil {
+		return fmt.Errorf("failed to read data_directory: %w", err)
+	}
+
+	contents, err := readServerFile(db, dataDir, procMounts)
+	if err != nil {
+		return err
+	}
+
+	mounts, err := nfs.ParseMounts(strings.NewReader(contents))
+	if err != nil {
+		return err
+	}
+
+	mount, err := nfs.VerifyMount(mounts, dataDir, r.expectedNFSVersions(mountOption))
+	if err != nil {
+		return err
+	}
+
+	r.mountVersions["postgresql/"+mountOption] = mount.Version()
+	slog.Info("Verified NFS mount", "path", dataDir, "source", mount.Source, "version", mount.Version(), "options", mount.Options)
+	return nil
+}
+
+// verifyFilesystemNFSMount checks the NFS path used by filesystem scenarios for a mount
+// option variant (empty for nfs.path) against this host's mount table
+func (r *Runner) verifyFilesystemNFSMount(ctx context.Context, mountOption string) error {
+	dir, err := r.filesystemPath("nfs", mountOption)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(procMounts)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", procMounts, err)
+	}
+	defer f.Close()
+
+	mounts, err := nfs.ParseMounts(f)
+	if err != nil {
+		return err
+	}
+
+	mount, err := nfs.VerifyMount(mounts, dir, r.expectedNFSVersions(mountOption))
+	if err != nil {
+		return err
+	}
+
+	r.mountVersions[filesystemTarget+"/"+mountOption] = mount.Version()
+	slog.Info("Verified NFS mount", "path", dir, "source", mount.Source, "version", mount.Version(), "options", mount.Options)
+	return nil
+}
+
+// readServerFile reads a kernel file (such as /proc/mounts) as seen by the database
+// server, since the NFS client lives on the server host. When the server does not allow
+// reading files, the local copy is used if the data directory is visible here too.
+func readServerFile(db *database.PostgresDB, dataDir, path string) (string, error) {
+	contents, err := db.ReadServerFile(path)
+	if err == nil {
+		return contents, nil
+	}
+
+	if _, statErr := os.Stat(dataDir); statErr != nil {
+		return "", fmt.Errorf("cannot read %s on the server (%v) and data directory %s is not visible locally", path, err, dataDir)
+	}
+	slog.Warn("Cannot read file on the database server, using local copy", "path", path, "error", err)
+	local, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, readErr)
+	}
+	return string(local), nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
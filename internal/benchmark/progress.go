@@ -0,0 +1,105 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// progressBarWidth is the number of characters in the rendered progress bar
+const progressBarWidth = 30
+
+// progressReporter periodically reports live throughput and latency while a workload runs
+type progressReporter struct {
+	label     string
+	total     time.Duration
+	interval  time.Duration
+	collector *metrics.Collector
+	showBar   bool
+	out       io.Writer
+}
+
+// newProgressReporter returns a reporter for the given collector, or nil if real-time
+// updates are disabled. Progress bars are only drawn when stderr is a terminal and a
+// single task runs at a time, since concurrent bars would overwrite each other.
+func (r *Runner) newProgressReporter(label string, total time.Duration, collector *metrics.Collector) *progressReporter {
+	if !r.config.Reporting.CLI.RealTimeUpdates {
+		return nil
+	}
+
+	return &progressReporter{
+		label:     label,
+		total:     total,
+		interval:  r.config.GetCollectionInterval(),
+		collector: collector,
+		showBar:   r.config.Reporting.CLI.ShowProgressBars && !r.parallel && r.config.Global.LogFormat != "json" && IsTerminal(os.Stderr),
+		out:       os.Stderr,
+	}
+}
+
+// Run reports progress every interval until ctx is done
+func (p *progressReporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	var lastOps int64
+	lastTime := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if p.showBar {
+				fmt.Fprintln(p.out)
+			}
+			return
+		case now := <-ticker.C:
+			snapshot := p.collector.Snapshot()
+			opsPerSec := float64(snapshot.Operations-lastOps) / now.Sub(lastTime).Seconds()
+			lastOps, lastTime = snapshot.Operations, now
+
+			if p.showBar {
+				fmt.Fprintf(p.out, "\r%s %s %s/%s %.1f ops/s p95 %v errors %d",
+					p.label, renderProgressBar(snapshot.Elapsed, p.total),
+					formatElapsed(snapshot.Elapsed), formatElapsed(p.total),
+					opsPerSec, snapshot.P95Latency.Round(time.Microsecond), snapshot.Errors)
+			} else {
+				slog.Info("Progress",
+					"label", p.label,
+					"elapsed", formatElapsed(snapshot.Elapsed),
+					"ops_per_sec", opsPerSec,
+					"p95_latency", snapshot.P95Latency.Round(time.Microsecond),
+					"operations", snapshot.Operations,
+					"errors", snapshot.Errors)
+			}
+		}
+	}
+}
+
+func renderProgressBar(elapsed, total time.Duration) string {
+	fraction := 1.0
+	if total > 0 && elapsed < total {
+		fraction = float64(elapsed) / float64(total)
+	}
+	filled := int(fraction * progressBarWidth)
+	return fmt.Sprintf("[%s%s] %3.0f%%", strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled), fraction*100)
+}
+
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// IsTerminal reports whether f is attached to a character device such as a TTY
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
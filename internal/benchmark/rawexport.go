@@ -0,0 +1,64 @@
+package benchmark
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// rawExportVersion is the version of the raw latency file format
+const rawExportVersion = 1
+
+// rawLatencyExport is the contents of a .raw.json.gz file. Latencies are in nanoseconds,
+// in the order they were recorded; when TotalSamples exceeds len(LatenciesNs) the
+// export is a uniform random subset.
+type rawLatencyExport struct {
+	Version      int     `json:"version"`
+	Database     string  `json:"database"`
+	Scenario     string  `json:"scenario"`
+	StorageType  string  `json:"storage_type"`
+	TotalSamples int64   `json:"total_samples"`
+	Sampled      bool    `json:"sampled"`
+	LatenciesNs  []int64 `json:"latencies_ns"`
+}
+
+// rawExportPath returns where a result's raw latency samples are written
+func rawExportPath(outputDir string, result *ScenarioResult) string {
+	return filepath.Join(outputDir, fmt.Sprintf("%s_%s_%s.raw.json.gz", result.Database, result.Name, resultLabel(result)))
+}
+
+// writeRawLatencies writes a result's raw latency samples as gzipped JSON
+func writeRawLatencies(path string, result *ScenarioResult, samples []time.Duration) error {
+	export := rawLatencyExport{
+		Version:     rawExportVersion,
+		Database:    result.Database,
+		Scenario:    result.Name,
+		StorageType: result.StorageType,
+		LatenciesNs: make([]int64, len(samples)),
+	}
+	if result.Metrics != nil {
+		export.TotalSamples = result.Metrics.TotalOperations
+	}
+	for i, latency := range samples {
+		export.LatenciesNs[i] = int64(latency)
+	}
+	export.Sampled = export.TotalSamples > int64(len(samples))
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	if err := json.NewEncoder(gz).Encode(export); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,140 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// readQueriesScenario reads prepopulated rows with a fixed set of queries
+const readQueriesScenario = "read_queries"
+
+// defaultQueryCount is the number of queries read_queries runs without query_count
+const defaultQueryCount = 10000
+
+func init() {
+	RegisterWorkload(readQueriesScenario, true, func(scenario config.ScenarioConfig, opts WorkloadOptions) (Workload, error) {
+		return newReadQueriesWorkload(scenario, opts)
+	})
+}
+
+// readQueriesWorkload prepopulates the benchmark table and then runs query_count
+// queries against it, each reading rows_per_query consecutive rows from a random id. It
+// takes the heavy_inserts parameters for the load, and requires prepopulate_rows.
+//
+// The queries are drawn from the seed once the table is loaded and split evenly across
+// threads, so every storage type, and every pass of cache_mode: cold_warm, runs the same
+// query set. A failed query is retried so the set always completes.
+type readQueriesWorkload struct {
+	*insertWorkload
+	queryCount   int64
+	rowsPerQuery int
+	seed         int64
+	threads      int
+	queries      [][]int64 // per-thread first ids of its queries
+	next         []int     // per-thread index of the next query
+}
+
+func newReadQueriesWorkload(scenario config.ScenarioConfig, opts WorkloadOptions) (*readQueriesWorkload, error) {
+	queryCount, err := intParam(scenario.Parameters, "query_count", defaultQueryCount)
+	if err != nil {
+		return nil, err
+	}
+	if queryCount < 1 {
+		return nil, fmt.Errorf("query_count must be at least 1, got %d", queryCount)
+	}
+	rowsPerQuery, err := intParam(scenario.Parameters, "rows_per_query", 1)
+	if err != nil {
+		return nil, err
+	}
+	if rowsPerQuery < 1 {
+		return nil, fmt.Errorf("rows_per_query must be at least 1, got %d", rowsPerQuery)
+	}
+
+	inserts, err := newInsertWorkload(scenario, opts)
+	if err != nil {
+		return nil, err
+	}
+	if inserts.prepopulate.rows == 0 {
+		return nil, fmt.Errorf("read_queries requires a positive prepopulate_rows parameter")
+	}
+
+	return &readQueriesWorkload{
+		insertWorkload: inserts,
+		queryCount:     int64(queryCount),
+		rowsPerQuery:   rowsPerQuery,
+		seed:           opts.Seed,
+		threads:        opts.Threads,
+		next:           make([]int, opts.Threads),
+	}, nil
+}
+
+// Setup loads the table and draws the query set from the ids it holds
+func (w *readQueriesWorkload) Setup(ctx context.Context, db database.Database) error {
+	if err := w.insertWorkload.Setup(ctx, db); err != nil {
+		return err
+	}
+
+	first, last, err := db.RecordIDRange(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read id range: %w", err)
+	}
+	span := last - first - int64(w.rowsPerQuery) + 2
+	if last == 0 || span < 1 {
+		span = 1
+	}
+
+	rng := rand.New(rand.NewSource(w.seed))
+	w.queries = make([][]int64, w.threads)
+	for thread, share := range threadShares(w.queryCount, w.threads) {
+		w.queries[thread] = make([]int64, share)
+		for i := range w.queries[thread] {
+			w.queries[thread][i] = first + rng.Int63n(span)
+		}
+	}
+	w.Replay()
+
+	slog.Info("Prepared read queries",
+		"database", db.GetName(),
+		"query_count", w.queryCount,
+		"rows_per_query", w.rowsPerQuery,
+		"first_id", first,
+		"last_id", last)
+	return nil
+}
+
+// RunOp runs the thread's next query, or retries its last one if that failed
+func (w *readQueriesWorkload) RunOp(ctx context.Context, db database.Database, thread int) (OpResult, error) {
+	if w.next[thread] >= len(w.queries[thread]) {
+		return OpResult{}, ErrWorkloadDone
+	}
+
+	start := time.Now()
+	rows, err := db.ReadRecords(ctx, w.queries[thread][w.next[thread]], w.rowsPerQuery)
+	latency := time.Since(start)
+	if err != nil {
+		return OpResult{Latency: latency}, err
+	}
+	w.next[thread]++
+	return OpResult{Latency: latency, Items: int64(rows)}, nil
+}
+
+// Replay starts every thread over at the first query of its set
+func (w *readQueriesWorkload) Replay() {
+	for i := range w.next {
+		w.next[i] = 0
+	}
+}
+
+// Stats records the query set's size
+func (w *readQueriesWorkload) Stats(ctx context.Context, db database.Database) map[string]interface{} {
+	return map[string]interface{}{
+		"query_count":    w.queryCount,
+		"rows_per_query": w.rowsPerQuery,
+	}
+}
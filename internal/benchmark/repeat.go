@@ -0,0 +1,108 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// runRepeats runs a task execution.repeat_count times, cleaning up before each run, and
+// pools the repeats into one result per storage type
+func (r *Runner) runRepeats(ctx context.Context, t task) ([]*ScenarioResult, error) {
+	repeats := r.config.Execution.RepeatCount
+	if repeats < 1 {
+		repeats = 1
+	}
+
+	var runs [][]*ScenarioResult
+	for i := 0; i < repeats; i++ {
+		if repeats > 1 {
+			slog.Info("Starting repeat", "task", t.String(), "repeat", i+1, "repeats", repeats)
+		}
+		if err := r.cleanupBeforeRun(ctx, t); err != nil {
+			return nil, fmt.Errorf("cleanup failed: %w", err)
+		}
+
+		runResults, err := r.runScenario(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, runResults)
+	}
+
+	return r.combineRepeats(runs), nil
+}
+
+// combineRepeats pools the measurements of every repeat per storage type. The pooled
+// Metrics are computed from all samples; the individual repeats are kept in Repeats so
+// run-to-run variance can be reported. DBStats come from the last repeat.
+func (r *Runner) combineRepeats(runs [][]*ScenarioResult) []*ScenarioResult {
+	var combined []*ScenarioResult
+	for i, first := range runs[0] {
+		pooled := r.newCollector()
+		pooledThreads := r.newThreadCollectors(len(first.threadCollectors))
+		result := *first
+		result.Duration = 0
+		result.Repeats = nil
+		if first.Durability != nil {
+			result.Durability = &DurabilityCheck{}
+		}
+
+		for _, run := range runs {
+			repeat := run[i]
+			if repeat.collector != nil {
+				pooled.Merge(repeat.collector)
+			}
+			mergeThreadCollectors(pooledThreads, repeat.threadCollectors)
+			result.Duration += repeat.Duration
+			result.DBStats = repeat.DBStats
+			if repeat.Durability != nil && result.Durability != nil {
+				result.Durability.Merge(repeat.Durability)
+			}
+			// A repeat that failed, e.g. its data verification, fails the pooled result
+			if !repeat.Success {
+				result.Success = false
+				result.Error = repeat.Error
+				result.Verification = repeat.Verification
+			}
+			if len(runs) > 1 {
+				result.Repeats = append(result.Repeats, repeat.Metrics)
+			}
+		}
+
+		result.collector = pooled
+		result.Metrics = pooled.Results()
+		if len(runs) > 1 {
+			result.threadCollectors = pooledThreads
+			result.PerThread = newThreadMetrics(pooledThreads)
+		}
+		result.rawLatencies = r.rawSamples(pooled)
+		if result.RateTarget != nil {
+			result.RateTarget = newRateTarget(result.RateTarget.TargetOpsPerSec, result.Metrics)
+		}
+		if len(runs) > 1 {
+			logRepeatSpread(&result)
+		}
+		combined = append(combined, &result)
+	}
+	return combined
+}
+
+// logRepeatSpread logs the throughput range across repeats so unstable runs stand out
+func logRepeatSpread(result *ScenarioResult) {
+	lowest, highest := result.Repeats[0].OperationsPerSecond, result.Repeats[0].OperationsPerSecond
+	for _, m := range result.Repeats[1:] {
+		if m.OperationsPerSecond < lowest {
+			lowest = m.OperationsPerSecond
+		}
+		if m.OperationsPerSecond > highest {
+			highest = m.OperationsPerSecond
+		}
+	}
+	slog.Info("Pooled repeats",
+		"storage_type", result.StorageType,
+		"repeats", len(result.Repeats),
+		"ops_per_sec", result.Metrics.OperationsPerSecond,
+		"min_ops_per_sec", lowest,
+		"max_ops_per_sec", highest)
+}
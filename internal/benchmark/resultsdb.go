@@ -0,0 +1,86 @@
+package benchmark
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/l22io/nfsvsdirectbench/internal/resultsdb"
+)
+
+// openResultsDB opens reporting.results_db, if configured, and records this run in it.
+// A resumed run keeps the row of the run it resumes, found by output directory, so the
+// results of its remaining tasks join those recorded before the interruption and tasks
+// that run again replace their failed results.
+func (r *Runner) openResultsDB(results *Results) error {
+	path := r.config.Reporting.ResultsDB
+	if path == "" {
+		return nil
+	}
+	db, err := resultsdb.Open(path)
+	if err != nil {
+		return err
+	}
+	if r.resumeDir != "" {
+		runID, ok, err := db.FindRun(results.OutputDir)
+		if err != nil {
+			db.Close()
+			return err
+		}
+		if ok {
+			r.resultsDB, r.resultsRunID = db, runID
+			slog.Info("Appending results to the resumed run in results database", "path", path, "run_id", runID)
+			return nil
+		}
+		slog.Warn("Resumed run not found in results database; recording it as a new run", "path", path, "output", results.OutputDir)
+	}
+	runID, err := db.AddRun(resultsdb.Run{
+		StartedAt: results.StartTime,
+		OutputDir: results.OutputDir,
+		Config:    r.config.Effective(),
+	})
+	if err != nil {
+		db.Close()
+		return fmt.Errorf("failed to record run in %s: %w", path, err)
+	}
+	r.resultsDB, r.resultsRunID = db, runID
+	slog.Info("Appending results to results database", "path", path, "run_id", runID)
+	return nil
+}
+
+// closeResultsDB closes the results database opened by openResultsDB
+func (r *Runner) closeResultsDB() {
+	if r.resultsDB == nil {
+		return
+	}
+	if err := r.resultsDB.Close(); err != nil {
+		slog.Error("Failed to close results database", "error", err)
+	}
+	r.resultsDB = nil
+}
+
+// recordResults appends a task's results to the results database. A failure is logged
+// rather than failing the run, like a failure to write the results files. Callers must
+// hold results.mu.
+func (r *Runner) recordResults(taskResults []*ScenarioResult) {
+	if r.resultsDB == nil {
+		return
+	}
+	for _, result := range taskResults {
+		err := r.resultsDB.AddResult(r.resultsRunID, resultsdb.Result{
+			Database:    result.Database,
+			Scenario:    result.Name,
+			StorageType: result.StorageType,
+			MountOption: result.MountOption,
+			InsertMode:  result.InsertMode,
+			Success:     result.Success,
+			Error:       result.Error,
+			Duration:    result.Duration,
+			Metrics:     result.Metrics,
+			Parameters:  result.Parameters,
+			DBStats:     result.DBStats,
+		})
+		if err != nil {
+			slog.Error("Failed to record result in results database", "task", resultKey(result.Database, result.Name, resultLabel(result)), "error", err)
+		}
+	}
+}
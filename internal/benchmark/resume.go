@@ -0,0 +1,139 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProgressFile is the file in the output directory that records which tasks completed,
+// so that an interrupted run can be resumed with ResumeFrom
+const ProgressFile = "progress.json"
+
+// progress is the contents of progress.json
+type progress struct {
+	Completed []string `json:"completed"` // tasks that succeeded, as task.String(), in completion order
+}
+
+// ResumeFrom makes RunAll continue the run whose output directory is dir instead of
+// starting a new one: tasks its progress.json lists as completed are skipped, and their
+// results are loaded from the directory's results files so the combined files and the
+// summary still cover them. Failed and unfinished tasks run again.
+func (r *Runner) ResumeFrom(dir string) {
+	r.resumeDir = dir
+}
+
+// resumeOutputDir reopens the output directory of the run being resumed, taking over its
+// run ID so {run_id} tables keep their names, and reads which of its tasks completed
+func (r *Runner) resumeOutputDir() (string, error) {
+	info, err := os.Stat(r.resumeDir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", r.resumeDir)
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.resumeDir, ProgressFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%s has no %s to resume from", r.resumeDir, ProgressFile)
+	}
+	if err != nil {
+		return "", err
+	}
+	var p progress
+	if err := json.Unmarshal(data, &p); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", ProgressFile, err)
+	}
+
+	r.completed = make(map[string]bool, len(p.Completed))
+	for _, t := range p.Completed {
+		r.completed[t] = true
+	}
+	r.completedOrder = p.Completed
+	dir := filepath.Clean(r.resumeDir)
+	r.runID = tableSafeID(strings.TrimPrefix(filepath.Base(dir), "run_"))
+	return dir, nil
+}
+
+// remainingTasks drops the tasks that completed in the run being resumed, logging each
+func (r *Runner) remainingTasks(tasks []task) []task {
+	var remaining []task
+	for _, t := range tasks {
+		if r.completed[t.String()] {
+			slog.Info("Skipping task completed before resuming", "task", t.String())
+			continue
+		}
+		remaining = append(remaining, t)
+	}
+	slog.Info("Resuming run", "output", r.resumeDir, "completed", len(tasks)-len(remaining), "remaining", len(remaining))
+	return remaining
+}
+
+// markCompleted records in progress.json that t succeeded. The file is replaced
+// atomically, so a run killed while writing it still leaves the previous version.
+func (r *Runner) markCompleted(outputDir string, t task) error {
+	r.progressMu.Lock()
+	defer r.progressMu.Unlock()
+	if r.completed == nil {
+		r.completed = make(map[string]bool)
+	}
+	r.completed[t.String()] = true
+	r.completedOrder = append(r.completedOrder, t.String())
+
+	data, err := json.MarshalIndent(progress{Completed: r.completedOrder}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(outputDir, ProgressFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// restoreResults loads the successful results saved in the output directory by the run
+// being resumed into results, so saving a combination's results file after one of its
+// remaining tasks keeps the results of the ones that completed before. Results of tasks
+// that run again are replaced as they complete.
+func restoreResults(results *Results) error {
+	paths, err := filepath.Glob(filepath.Join(results.OutputDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	compressed, err := filepath.Glob(filepath.Join(results.OutputDir, "*.json.gz"))
+	if err != nil {
+		return err
+	}
+	for _, path := range append(paths, compressed...) {
+		if strings.HasSuffix(path, ".raw.json.gz") || filepath.Base(path) == ProgressFile {
+			continue
+		}
+		var entries map[string]json.RawMessage
+		data, err := ReadResultsFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, &entries)
+		}
+		if err != nil {
+			slog.Warn("Skipping unreadable results file", "path", path, "error", err)
+			continue
+		}
+		for label, raw := range entries {
+			if label == ResultsConfigKey {
+				continue
+			}
+			var result ScenarioResult
+			// Failed results don't decode, since their Error is an interface; they run again
+			if err := json.Unmarshal(raw, &result); err != nil || !result.Success {
+				continue
+			}
+			results.ScenarioResults[resultKey(result.Database, result.Name, resultLabel(&result))] = &result
+		}
+	}
+	return nil
+}
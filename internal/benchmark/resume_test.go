@@ -0,0 +1,187 @@
+package benchmark
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+)
+
+func TestResumeProgress(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "run_20250101_120000")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	newTask := func(scenario string) task {
+		return task{Database: "postgresql", Scenario: config.ScenarioConfig{Name: scenario}, StorageTypes: []string{"direct", "nfs"}}
+	}
+	tasks := []task{newTask("heavy_inserts"), newTask("heavy_reads"), newTask("mixed")}
+	writeProgress(t, dir, progress{Completed: []string{tasks[1].String()}})
+
+	r := NewRunner(&config.Config{})
+	r.ResumeFrom(dir + "/")
+	outputDir, err := r.resumeOutputDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outputDir != dir {
+		t.Errorf("Expected output directory %s, got %s", dir, outputDir)
+	}
+	if r.runID != "20250101_120000" {
+		t.Errorf("Expected the run ID of the resumed directory, got %q", r.runID)
+	}
+
+	remaining := r.remainingTasks(tasks)
+	if len(remaining) != 2 || remaining[0].String() != tasks[0].String() || remaining[1].String() != tasks[2].String() {
+		t.Errorf("Expected the tasks before and after the completed one, got %v", remaining)
+	}
+
+	if err := r.markCompleted(outputDir, tasks[2]); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ProgressFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p progress
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Completed) != 2 || p.Completed[0] != tasks[1].String() || p.Completed[1] != tasks[2].String() {
+		t.Errorf("Expected completed tasks in completion order, got %v", p.Completed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ProgressFile+".tmp")); !os.IsNotExist(err) {
+		t.Errorf("Expected the temporary progress file to be renamed, got %v", err)
+	}
+}
+
+func TestResumeWithoutProgress(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		setup func(dir string) string
+	}{
+		{"missing directory", func(dir string) string { return filepath.Join(dir, "missing") }},
+		{"not a directory", func(dir string) string {
+			path := filepath.Join(dir, "file")
+			os.WriteFile(path, nil, 0644)
+			return path
+		}},
+		{"no progress file", func(dir string) string { return dir }},
+		{"corrupt progress file", func(dir string) string {
+			os.WriteFile(filepath.Join(dir, ProgressFile), []byte("{"), 0644)
+			return dir
+		}},
+	} {
+		r := NewRunner(&config.Config{})
+		r.ResumeFrom(tc.setup(t.TempDir()))
+		if _, err := r.resumeOutputDir(); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+func TestRestoreResults(t *testing.T) {
+	succeeded := func(scenario, storageType string) json.RawMessage {
+		return json.RawMessage(`{"Name":"` + scenario + `","Database":"postgresql","StorageType":"` + storageType + `","Success":true}`)
+	}
+	// The runner writes a failed result's error as an object, which doesn't decode
+	failed := func(scenario, storageType string) json.RawMessage {
+		return json.RawMessage(`{"Name":"` + scenario + `","Database":"postgresql","StorageType":"` + storageType + `","Success":false,"Error":{}}`)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		files map[string]map[string]json.RawMessage
+		want  []string
+	}{
+		{
+			name: "plain results",
+			files: map[string]map[string]json.RawMessage{
+				"postgresql_heavy_inserts.json": {
+					"direct":         succeeded("heavy_inserts", "direct"),
+					"nfs":            succeeded("heavy_inserts", "nfs"),
+					ResultsConfigKey: json.RawMessage(`{"scenarios":[]}`),
+				},
+			},
+			want: []string{"postgresql_heavy_inserts_direct", "postgresql_heavy_inserts_nfs"},
+		},
+		{
+			name: "compressed results",
+			files: map[string]map[string]json.RawMessage{
+				"postgresql_heavy_inserts.json.gz": {"direct": succeeded("heavy_inserts", "direct")},
+			},
+			want: []string{"postgresql_heavy_inserts_direct"},
+		},
+		{
+			name: "failed results run again",
+			files: map[string]map[string]json.RawMessage{
+				"postgresql_heavy_inserts.json": {
+					"direct": succeeded("heavy_inserts", "direct"),
+					"nfs":    failed("heavy_inserts", "nfs"),
+				},
+			},
+			want: []string{"postgresql_heavy_inserts_direct"},
+		},
+		{
+			name: "raw latency exports and progress skipped",
+			files: map[string]map[string]json.RawMessage{
+				"postgresql_heavy_inserts_nfs.raw.json.gz": {"nfs": succeeded("heavy_inserts", "nfs")},
+				ProgressFile: {"direct": succeeded("heavy_inserts", "direct")},
+			},
+		},
+	} {
+		results := &Results{OutputDir: t.TempDir(), ScenarioResults: make(map[string]*ScenarioResult)}
+		for name, entries := range tc.files {
+			writeResults(t, filepath.Join(results.OutputDir, name), entries)
+		}
+		if err := restoreResults(results); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(results.ScenarioResults) != len(tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, results.ScenarioResults)
+		}
+		for _, key := range tc.want {
+			if results.ScenarioResults[key] == nil {
+				t.Errorf("%s: expected %s to be restored, got %v", tc.name, key, results.ScenarioResults)
+			}
+		}
+	}
+}
+
+func writeProgress(t *testing.T, dir string, p progress) {
+	t.Helper()
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ProgressFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeResults writes a results file, gzipped if path ends in .gz
+func writeResults(t *testing.T, path string, entries map[string]json.RawMessage) {
+	t.Helper()
+	data, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if filepath.Ext(path) == ".gz" {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		_, err = gz.Write(data)
+	} else {
+		_, err = f.Write(data)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
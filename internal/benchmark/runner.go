@@ -1,19 +1,30 @@
 package benchmark
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/l22io/nfsvsdirectbench/internal/config"
 	"github.com/l22io/nfsvsdirectbench/internal/database"
 	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+	"github.com/l22io/nfsvsdirectbench/internal/nfs"
+	"github.com/l22io/nfsvsdirectbench/internal/resultsdb"
 )
 
 // Results contains benchmark execution results
@@ -23,6 +34,8 @@ type Results struct {
 	ScenarioResults map[string]*ScenarioResult
 	StartTime     time.Time
 	EndTime       time.Time
+
+	mu sync.Mutex // guards ScenarioResults and result files while tasks run concurrently
 }
 
 // ScenarioResult contains results for a single scenario
@@ -30,16 +43,60 @@ type ScenarioResult struct {
 	Name        string
 	Database    string
 	StorageType string
+	MountOption string `json:",omitempty"` // NFS mount option variant, when variants are configured
 	Duration    time.Duration
 	Success     bool
 	Error       error
 	Metrics     *metrics.Results
+	Repeats     []*metrics.Results `json:",omitempty"` // per-repeat metrics when repeat_count > 1; Metrics pools them
 	DBStats     map[string]interface{}
+	Verification *Verification `json:",omitempty"` // stored data checked against what was written, with execution.verify_data
+	Durability   *DurabilityCheck `json:",omitempty"` // committed rows read back, with the verify_durability parameter
+	RateTarget  *RateTarget `json:",omitempty"` // target vs achieved rate, with target_ops_per_sec
+	Parameters  map[string]interface{} `json:",omitempty"` // effective scenario parameters, including --set overrides
+	SessionSettings map[string]string `json:",omitempty"` // session_settings as the server reported them after applying
+	NFSVersion  string `json:",omitempty"` // NFS version of the storage, from a versioned mount option variant or the mount check
+	InsertMode  string `json:",omitempty"` // the run's insert_mode, when the scenario lists several
+	PerThread   *ThreadMetrics `json:",omitempty"` // metrics by worker thread, with metrics.per_thread
+
+	collector    *metrics.Collector // measurements behind Metrics, pooled across repeats
+	threadCollectors []*metrics.Collector // measurements behind PerThread, pooled across repeats
+	rawLatencies []time.Duration    // samples for metrics.export_raw, written alongside the results
+}
+
+// Failed returns the keys of the scenario runs that did not succeed, sorted
+func (r *Results) Failed() []string {
+	var keys []string
+	for key, result := range r.ScenarioResults {
+		if !result.Success {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // Runner orchestrates benchmark execution
 type Runner struct {
-	config *config.Config
+	config   *config.Config
+	parallel bool // more than one task group runs at a time
+
+	cacheWarning sync.Once // reports a failure to drop caches only once
+
+	resultsDB    *resultsdb.DB // reporting.results_db, if configured
+	resultsRunID int64         // this run's row in resultsDB
+	live         *liveMetrics  // runs exposed on reporting.metrics_addr, if configured
+
+	mountVersions map[string]string // NFS versions found by the mount check, by target/mount option
+
+	runID string // the output directory's timestamp, made safe for table names
+
+	timelineStream *timelineStream // metrics.timeline_stream, if configured
+
+	resumeDir      string          // output directory of the run to resume, see ResumeFrom
+	progressMu     sync.Mutex      // guards completed and completedOrder
+	completed      map[string]bool // tasks that succeeded, by task.String()
+	completedOrder []string        // completed in the order they finished, as written to progress.json
 }
 
 // NewRunner creates a new benchmark runner
@@ -53,47 +110,290 @@ func NewRunner(cfg *config.Config) *Runner {
 func (r *Runner) RunAll(ctx context.Context) (*Results, error) {
 	startTime := time.Now()
 	
-	// Create output directory
-	outputDir, err := r.createOutputDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	// Create output directory, or reopen the one of the run being resumed
+	var outputDir string
+	var err error
+	if r.resumeDir != "" {
+		outputDir, err = r.resumeOutputDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resume run: %w", err)
+		}
+	} else {
+		outputDir, err = r.createOutputDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
 	}
 	
-	log.Printf("Starting benchmark suite - output: %s", outputDir)
+	slog.Info("Starting benchmark suite", "output", outputDir)
 	
 	results := &Results{
 		OutputDir:       outputDir,
 		ScenarioResults: make(map[string]*ScenarioResult),
 		StartTime:       startTime,
 	}
+	if r.resumeDir != "" {
+		if err := restoreResults(results); err != nil {
+			return nil, fmt.Errorf("failed to load results of the run being resumed: %w", err)
+		}
+	}
+
+	if err := r.openResultsDB(results); err != nil {
+		return nil, err
+	}
+	defer r.closeResultsDB()
+
+	if err := r.openTimelineStream(outputDir); err != nil {
+		return nil, err
+	}
+	defer r.closeTimelineStream()
+
+	if addr := r.config.Reporting.MetricsAddr; addr != "" {
+		stopMetrics, err := r.serveMetrics(addr)
+		if err != nil {
+			return nil, err
+		}
+		defer stopMetrics()
+	}
 	
-	// Get enabled databases and scenarios
-	databases := r.config.GetEnabledDatabases()
-	scenarios := r.config.GetEnabledScenarios()
+	tasks := r.planTasks()
+	if r.config.Execution.RandomizeOrder {
+		r.shuffleTasks(tasks)
+	}
+
+	r.warnUnsupportedCleanup()
+
+	if r.config.Execution.Deterministic {
+		slog.Info("Deterministic mode: insert threads run in lockstep and count-bound loads are split evenly", "seed", r.config.Global.Seed)
+	}
+
+	slog.Info("Planned tasks", "count", len(tasks))
+	for i, t := range tasks {
+		slog.Info("Planned task", "order", i+1, "task", t.String())
+	}
+	if r.resumeDir != "" {
+		tasks = r.remainingTasks(tasks)
+	}
+
+	if err := r.verifyNFSMounts(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	if err := r.checkDiskSpace(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	r.checkPoolSizes(tasks)
+
+	if err := r.runTaskGroups(ctx, groupTasks(tasks), results); err != nil {
+		return nil, err
+	}
 	
-	log.Printf("Running %d scenarios against %d databases", len(scenarios), len(databases))
+	results.EndTime = time.Now()
+	results.TotalDuration = results.EndTime.Sub(results.StartTime)
 	
-	// Execute each scenario against each database
+	return results, nil
+}
+
+// groupTasks splits tasks into groups that may run concurrently, one per database.
+// Scenarios against the same database share its host and benchmark table, so tasks
+// within a group (including direct vs NFS of one combination) always run in order.
+func groupTasks(tasks []task) [][]task {
+	var groups [][]task
+	index := make(map[string]int)
+	for _, t := range tasks {
+		i, ok := index[t.Database]
+		if !ok {
+			i = len(groups)
+			index[t.Database] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], t)
+	}
+	return groups
+}
+
+// runTaskGroups runs task groups concurrently, bounded by global.max_workers. With
+// fail_fast set, the first failure cancels all remaining work and is returned.
+func (r *Runner) runTaskGroups(ctx context.Context, groups [][]task, results *Results) error {
+	workers := r.workerCount(len(groups))
+	r.parallel = workers > 1
+	if r.parallel {
+		slog.Info("Running independent task groups in parallel", "groups", len(groups), "workers", workers)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, workers)
+
+	for _, group := range groups {
+		wg.Add(1)
+		go func(group []task) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var previous string
+			for _, t := range group {
+				if ctx.Err() != nil {
+					return
+				}
+				first, last := r.storageEnds(t)
+				if previous != "" && first != previous {
+					if err := r.pauseBetweenStorageTypes(ctx, previous, first); err != nil {
+						return
+					}
+				}
+				previous = last
+				if err := r.runTask(ctx, t, results); err != nil {
+					if r.config.Execution.FailFast {
+						errMu.Lock()
+						if firstErr == nil {
+							firstErr = fmt.Errorf("scenario %s failed on %s (%s): %w", t.Scenario.Name, t.Database, strings.Join(t.StorageTypes, "+"), err)
+						}
+						errMu.Unlock()
+						cancel()
+						return
+					}
+					slog.Error("Scenario failed, continuing", "task", t.String(), "error", err)
+					continue
+				}
+				if ctx.Err() != nil {
+					return // interrupted, perhaps mid-measurement, so the task runs again on resume
+				}
+				if err := r.markCompleted(results.OutputDir, t); err != nil {
+					slog.Error("Failed to record progress", "task", t.String(), "error", err)
+				}
+			}
+		}(group)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// workerCount returns how many of the given number of task groups run at a time
+func (r *Runner) workerCount(groups int) int {
+	workers := r.config.Global.MaxWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > groups {
+		workers = groups
+	}
+	return workers
+}
+
+// storageEnds returns the storage labels a task measures first and last, so the pause
+// between tasks compares where one task ended with where the next begins. Interleaved
+// slices alternate in ABBA order, so after an even number of rounds a task ends on the
+// storage type it started with; cold/warm cache passes run each storage type in turn.
+func (r *Runner) storageEnds(t task) (first, last string) {
+	first = storageLabel(t.StorageTypes[0], t.MountOption)
+	last = storageLabel(t.StorageTypes[len(t.StorageTypes)-1], t.MountOption)
+	if len(t.StorageTypes) > 1 && fmt.Sprintf("%v", t.Scenario.Parameters["cache_mode"]) != cacheModeColdWarm &&
+		sliceRounds(r.config, t)%2 == 0 {
+		last = first
+	}
+	return first, last
+}
+
+// task is a single benchmark run of one scenario on one database. It normally covers one
+// storage type; in interleave mode it covers all of them, alternating between slices.
+type task struct {
+	Database     string
+	Scenario     config.ScenarioConfig
+	StorageTypes []string
+	MountOption  string // NFS mount option variant; only set on single-storage NFS tasks
+}
+
+func (t task) String() string {
+	return fmt.Sprintf("%s/%s/%s", t.Database, t.Scenario.Name, storageLabel(strings.Join(t.StorageTypes, "+"), t.MountOption))
+}
+
+// planTasks builds the ordered list of runnable tasks from the enabled databases and scenarios
+func (r *Runner) planTasks() []task {
+	databases := r.config.GetEnabledDatabases()
+	sort.Strings(databases)
+	scenarios := r.config.GetEnabledScenarios()
+	storageTypes := r.config.Execution.StorageTypes
+
+	slog.Info("Planning tasks", "scenarios", len(scenarios), "databases", len(databases), "storage_types", storageTypes)
+
+	var tasks []task
+
+	// Filesystem scenarios don't use a database, so they are planned once rather than
+	// per database, always with one storage type per task
+	for _, scenario := range scenarios {
+		if scenario.WorkloadName() != fsyncLatencyScenario {
+			continue
+		}
+		for _, storageType := range storageTypes {
+			tasks = append(tasks, r.storageTasks(filesystemTarget, scenario, storageType)...)
+		}
+	}
+
 	for _, db := range databases {
+		// Only implement PostgreSQL for now
+		if db != "postgresql" {
+			slog.Warn("Skipping database - only PostgreSQL implemented", "database", db)
+			continue
+		}
+
 		for _, scenario := range scenarios {
-			if err := r.runScenario(ctx, db, scenario, results); err != nil {
-				if r.config.Execution.FailFast {
-					return nil, fmt.Errorf("scenario %s failed on %s: %w", scenario.Name, db, err)
-				}
-				log.Printf("Scenario %s failed on %s: %v (continuing)", scenario.Name, db, err)
+			if scenario.WorkloadName() == fsyncLatencyScenario {
+				continue
+			}
+			spec, ok := workloads[scenario.WorkloadName()]
+			if !ok {
+				slog.Warn("Skipping scenario - not implemented", "scenario", scenario.Name, "workload", scenario.WorkloadName())
+				continue
+			}
+
+			// Count-bound workloads end on their work rather than a duration, so they
+			// cannot be split into interleaved slices
+			if r.config.Execution.Interleave && !spec.countBound {
+				tasks = append(tasks, task{Database: db, Scenario: scenario, StorageTypes: storageTypes})
+				continue
+			}
+			for _, storageType := range storageTypes {
+				tasks = append(tasks, r.storageTasks(db, scenario, storageType)...)
 			}
 		}
 	}
-	
-	results.EndTime = time.Now()
-	results.TotalDuration = results.EndTime.Sub(results.StartTime)
-	
-	return results, nil
+
+	return tasks
+}
+
+// shuffleTasks randomizes task order so no storage type consistently benefits from a warm cache.
+// The seed is logged so a particular order can be reproduced via execution.seed; in
+// deterministic mode global.seed is used when execution.seed is unset.
+func (r *Runner) shuffleTasks(tasks []task) {
+	seed := r.config.Execution.Seed
+	if seed == 0 && r.config.Execution.Deterministic {
+		seed = r.config.Global.Seed
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	slog.Info("Randomizing task order", "seed", seed)
+
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(tasks), func(i, j int) {
+		tasks[i], tasks[j] = tasks[j], tasks[i]
+	})
 }
 
 func (r *Runner) createOutputDir() (string, error) {
 	timestamp := time.Now().Format(r.config.Global.TimestampFormat)
 	outputDir := filepath.Join(r.config.Global.OutputDir, fmt.Sprintf("run_%s", timestamp))
+	r.runID = tableSafeID(timestamp)
 	
 	// Create the directory
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
@@ -103,202 +403,888 @@ func (r *Runner) createOutputDir() (string, error) {
 	return outputDir, nil
 }
 
-func (r *Runner) runScenario(ctx context.Context, database string, scenario config.ScenarioConfig, results *Results) error {
-	log.Printf("Running scenario '%s' on database '%s'", scenario.Name, database)
-	
-	scenarioStart := time.Now()
+// tableSafeID lowercases id and replaces anything but letters and digits with
+// underscores, so it can be used in a table name
+func tableSafeID(id string) string {
+	return strings.Map(func(c rune) rune {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			return c
+		}
+		return '_'
+	}, strings.ToLower(id))
+}
+
+// runTask executes a single task, records its results, and saves the combination's results file
+func (r *Runner) runTask(ctx context.Context, t task, results *Results) error {
+	slog.Info("Running scenario", "scenario", t.Scenario.Name, "database", t.Database, "storage_types", t.StorageTypes, "parameters", t.Scenario.Parameters)
 	
-	// Only implement PostgreSQL for now
-	if database != "postgresql" {
-		log.Printf("Skipping %s - only PostgreSQL implemented", database)
-		return nil
+	taskStart := time.Now()
+
+	taskResults, err := r.runRepeats(ctx, t)
+	if err != nil {
+		slog.Error("Storage benchmark failed", "task", t.String(), "error", err)
+		taskResults = nil
+		for _, storageType := range t.StorageTypes {
+			taskResults = append(taskResults, &ScenarioResult{
+				Name:        t.Scenario.Name,
+				Database:    t.Database,
+				StorageType: storageType,
+				MountOption: t.MountOption,
+				Success:     false,
+				Error:       err,
+			})
+		}
 	}
 
-	// Only implement heavy_inserts for now
-	if scenario.Name != "heavy_inserts" {
-		log.Printf("Skipping scenario %s - only heavy_inserts implemented", scenario.Name)
-		return nil
+	// Store results. A task's results are usually all named after its scenario, but
+	// cache_mode passes each get a name of their own, and with several insert modes each
+	// storage type has a result per mode.
+	results.mu.Lock()
+	var names, modes []string
+	for _, result := range taskResults {
+		result.Parameters = t.Scenario.Parameters
+		result.NFSVersion = r.nfsVersion(t.Database, result.StorageType, result.MountOption)
+		results.ScenarioResults[resultKey(t.Database, result.Name, resultLabel(result))] = result
+		if !containsString(names, result.Name) {
+			names = append(names, result.Name)
+		}
+		if !containsString(modes, result.InsertMode) {
+			modes = append(modes, result.InsertMode)
+		}
 	}
 
-	// Run benchmark on direct storage
-	directResult, err := r.runPostgreSQLHeavyInserts(ctx, "direct", scenario)
-	if err != nil {
-		log.Printf("Direct storage benchmark failed: %v", err)
-		directResult = &ScenarioResult{
-			Name:        scenario.Name,
-			Database:    database,
-			StorageType: "direct",
-			Success:     false,
-			Error:       err,
+	// Save results to JSON file
+	for _, name := range names {
+		if saveErr := r.saveScenarioResults(results, t.Database, name, modes); saveErr != nil {
+			slog.Error("Failed to save results", "error", saveErr)
+		}
+	}
+	r.recordResults(taskResults)
+	results.mu.Unlock()
+
+	for _, result := range taskResults {
+		if result.rawLatencies == nil {
+			continue
+		}
+		path := rawExportPath(results.OutputDir, result)
+		if rawErr := writeRawLatencies(path, result, result.rawLatencies); rawErr != nil {
+			slog.Error("Failed to write raw latencies", "path", path, "error", rawErr)
+			continue
+		}
+		slog.Info("Wrote raw latency samples", "samples", len(result.rawLatencies), "path", path)
+		result.rawLatencies = nil
+	}
+
+	slog.Info("Completed scenario", "scenario", t.Scenario.Name, "database", t.Database, "storage_types", t.StorageTypes, "elapsed", time.Since(taskStart))
+
+	return err
+}
+
+// runScenario runs one execution of a task, bounding setup and measurement together by
+// the scenario's max_scenario_runtime so a run stuck in setup fails instead of hanging
+func (r *Runner) runScenario(ctx context.Context, t task) ([]*ScenarioResult, error) {
+	maxRuntime := t.Scenario.GetMaxRuntime()
+	if maxRuntime == 0 {
+		return r.dispatchScenario(ctx, t)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, maxRuntime)
+	defer cancel()
+	taskResults, err := r.dispatchScenario(runCtx, t)
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, fmt.Errorf("scenario exceeded max_scenario_runtime of %s: %w", maxRuntime, context.DeadlineExceeded)
+	}
+	return taskResults, err
+}
+
+// dispatchScenario hands a task to the implementation of its scenario: the filesystem
+// scenarios run on their own, and database scenarios drive their registered workload
+func (r *Runner) dispatchScenario(ctx context.Context, t task) ([]*ScenarioResult, error) {
+	if t.Database == filesystemTarget {
+		var taskResults []*ScenarioResult
+		for _, storageType := range t.StorageTypes {
+			result, err := r.runFsyncLatency(ctx, storageType, t.MountOption, t.Scenario)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", storageType, err)
+			}
+			taskResults = append(taskResults, result)
 		}
+		return taskResults, nil
+	}
+
+	spec, ok := workloads[t.Scenario.WorkloadName()]
+	if !ok {
+		return nil, fmt.Errorf("no workload %q registered for scenario %q", t.Scenario.WorkloadName(), t.Scenario.Name)
+	}
+	modes, err := parseInsertModes(t.Scenario.Parameters)
+	if err != nil {
+		return nil, err
 	}
+	if len(modes) > 1 {
+		return r.runInsertModes(ctx, t.StorageTypes, t.MountOption, t.Scenario, spec, modes)
+	}
+	return r.runWorkload(ctx, t.StorageTypes, t.MountOption, t.Scenario, spec)
+}
 
-	// Run benchmark on NFS storage
-	nfsResult, err := r.runPostgreSQLHeavyInserts(ctx, "nfs", scenario)
+// resultKey identifies a result in Results.ScenarioResults by its storage label
+func resultKey(database, scenario, label string) string {
+	return fmt.Sprintf("%s_%s_%s", database, scenario, label)
+}
+
+// workloadRun holds the connection, workload, and accumulated metrics of one storage type
+// while its scenario's workload is measured
+type workloadRun struct {
+	scenario    string
+	storageType string
+	mountOption string
+	db          *database.PostgresDB
+	workload    Workload
+	countBound  bool
+	threads     int
+	seed        int64
+	thinkTime   thinkTime
+	limiter     *rate.Limiter // paces all threads to target_ops_per_sec, if set
+	targetRate  float64
+	collector   *metrics.Collector
+	threadCollectors []*metrics.Collector // per-thread measurements with metrics.per_thread, merged like collector
+	nfsBefore   *nfs.MountStats // NFS client counters at the start of the run, if available
+}
+
+// runWorkload benchmarks a registered workload on the given storage types. With a single
+// storage type the workload runs for the full scenario duration; with several (interleave mode)
+// the duration is split into slices that alternate between storage types, so slow drift in
+// background load affects all of them equally. Count-bound workloads run each storage type
+// until the workload has no work left instead.
+func (r *Runner) runWorkload(ctx context.Context, storageTypes []string, mountOption string, scenario config.ScenarioConfig, spec workloadSpec) ([]*ScenarioResult, error) {
+	coldWarm, err := parseCacheMode(scenario, spec)
 	if err != nil {
-		log.Printf("NFS storage benchmark failed: %v", err)
-		nfsResult = &ScenarioResult{
-			Name:        scenario.Name,
-			Database:    database,
-			StorageType: "nfs",
-			Success:     false,
-			Error:       err,
+		return nil, err
+	}
+
+	var runs []*workloadRun
+	defer func() {
+		for _, run := range runs {
+			if err := run.workload.Teardown(context.Background(), run.db); err != nil {
+				slog.Warn("Workload teardown failed", "storage_type", run.storageType, "error", err)
+			}
+			r.cleanupAfterRun(context.Background(), run)
+			run.db.Close()
+		}
+	}()
+
+	for _, storageType := range storageTypes {
+		run, err := r.setupWorkload(ctx, storageType, mountOption, scenario, spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", storageType, err)
+		}
+		runs = append(runs, run)
+	}
+
+	if coldWarm {
+		return r.runCachePasses(ctx, runs, scenario)
+	}
+	if spec.countBound {
+		for i, run := range runs {
+			if i > 0 {
+				from := storageLabel(runs[i-1].storageType, runs[i-1].mountOption)
+				if err := r.pauseBetweenStorageTypes(ctx, from, storageLabel(run.storageType, run.mountOption)); err != nil {
+					return nil, err
+				}
+			}
+			if err := r.measureWorkload(ctx, run, 0); err != nil {
+				return nil, fmt.Errorf("%s: %w", run.storageType, err)
+			}
 		}
+	} else if err := r.interleaveWorkload(ctx, runs, time.Duration(scenario.Duration)*time.Second); err != nil {
+		return nil, err
 	}
 
-	// Store results
-	directKey := fmt.Sprintf("%s_%s_direct", database, scenario.Name)
-	nfsKey := fmt.Sprintf("%s_%s_nfs", database, scenario.Name)
-	results.ScenarioResults[directKey] = directResult
-	results.ScenarioResults[nfsKey] = nfsResult
+	var scenarioResults []*ScenarioResult
+	for _, run := range runs {
+		scenarioResults = append(scenarioResults, r.finishWorkload(ctx, run, scenario))
+	}
+	return scenarioResults, nil
+}
 
-	// Save results to JSON file
-	if err := r.saveScenarioResults(results.OutputDir, directResult, nfsResult); err != nil {
-		log.Printf("Failed to save results: %v", err)
+// interleaveWorkload measures duration-bound runs for the scenario duration, in slices
+// when there is more than one run
+func (r *Runner) interleaveWorkload(ctx context.Context, runs []*workloadRun, duration time.Duration) error {
+	slice := duration
+	if len(runs) > 1 {
+		slice = r.config.GetSliceDuration()
 	}
 
-	scenarioDuration := time.Since(scenarioStart)
-	log.Printf("Completed scenario '%s' on '%s' in %v", scenario.Name, database, scenarioDuration)
+	var previous *workloadRun
+	for round := 0; duration > 0; round++ {
+		current := slice
+		if current > duration {
+			current = duration
+		}
+
+		// Alternate which storage type goes first each round (ABBA ordering)
+		for i := range runs {
+			run := runs[i]
+			if round%2 == 1 {
+				run = runs[len(runs)-1-i]
+			}
+			if previous != nil && previous != run {
+				from := storageLabel(previous.storageType, previous.mountOption)
+				if err := r.pauseBetweenStorageTypes(ctx, from, storageLabel(run.storageType, run.mountOption)); err != nil {
+					return err
+				}
+			}
+			previous = run
+			if err := r.measureWorkload(ctx, run, current); err != nil {
+				return fmt.Errorf("%s: %w", run.storageType, err)
+			}
+		}
 
+		duration -= current
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
 	return nil
 }
 
-func (r *Runner) runPostgreSQLHeavyInserts(ctx context.Context, storageType string, scenario config.ScenarioConfig) (*ScenarioResult, error) {
+// setupWorkload connects to the storage type's database, builds the scenario's workload,
+// and lets it prepare the database. The workload is built afresh for every storage type,
+// so each one starts from the same seeds.
+func (r *Runner) setupWorkload(ctx context.Context, storageType, mountOption string, scenario config.ScenarioConfig, spec workloadSpec) (*workloadRun, error) {
 	// Get database config
-	var dbConfig config.DatabaseConnectionConfig
-	postgresConfig := r.config.Databases["postgresql"]
-	if storageType == "direct" {
-		dbConfig = postgresConfig.Direct
-	} else {
-		dbConfig = postgresConfig.NFS
+	dbConfig, err := r.connectionConfig("postgresql", storageType, mountOption)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get scenario parameters shared by all workloads
+	threads, err := intParam(scenario.Parameters, "threads", 1)
+	if err != nil {
+		return nil, err
+	}
+	if threads < 1 {
+		return nil, fmt.Errorf("threads must be at least 1, got %d", threads)
+	}
+	seed := r.config.Global.Seed
+	if v, ok := scenario.Parameters["seed"]; ok {
+		seed, err = strconv.ParseInt(fmt.Sprintf("%v", v), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed parameter %v: %w", v, err)
+		}
 	}
 
+	thinkTime, err := parseThinkTime(scenario.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	limiter, targetRate, err := parseTargetRate(scenario.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	workload, err := spec.factory(scenario, WorkloadOptions{
+		Threads:       threads,
+		Seed:          seed,
+		Deterministic: r.config.Execution.Deterministic,
+		ResetTable:    r.config.Execution.Cleanup.ResetDatabases,
+		Verify:        r.config.Execution.VerifyData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Size the pool for the scenario so connection contention doesn't mask storage behavior
+	if dbConfig.Pool.MaxOpen == 0 && threads > database.DefaultMaxOpenConns {
+		dbConfig.Pool.MaxOpen = threads
+	}
+	dbConfig.SessionSettings = scenario.GetSessionSettings()
+
 	// Connect to database
-	db, err := database.NewPostgresDB(dbConfig, fmt.Sprintf("postgresql-%s", storageType))
+	db, err := database.ConnectPostgresDB(ctx, dbConfig, "postgresql-"+storageLabel(storageType, mountOption))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-	defer db.Close()
 
-	// Setup benchmark table
-	if err := db.CreateBenchmarkTable(); err != nil {
-		return nil, fmt.Errorf("failed to create benchmark table: %w", err)
+	if err := workload.Setup(ctx, db); err != nil {
+		db.Close()
+		return nil, err
 	}
 
-	if err := db.ClearBenchmarkTable(); err != nil {
-		return nil, fmt.Errorf("failed to clear benchmark table: %w", err)
-	}
+	slog.Info("Starting benchmark",
+		"scenario", scenario.Name,
+		"storage_type", storageType,
+		"mount_option", mountOption,
+		"threads", threads,
+		"seed", seed,
+		"think_time", thinkTime.mean,
+		"target_ops_per_sec", targetRate,
+		"session_settings", dbConfig.SessionSettings,
+		"duration_seconds", scenario.Duration)
 
-	// Get scenario parameters
-	threads, _ := strconv.Atoi(fmt.Sprintf("%v", scenario.Parameters["threads"]))
-	batchSize, _ := strconv.Atoi(fmt.Sprintf("%v", scenario.Parameters["batch_size"]))
-	recordSizeStr := fmt.Sprintf("%v", scenario.Parameters["record_size"])
-	recordSize := database.RecordSize(recordSizeStr)
+	var nfsBefore *nfs.MountStats
+	if storageType == "nfs" {
+		if nfsBefore, err = snapshotNFSStats(db); err != nil {
+			slog.Warn("NFS statistics unavailable", "error", err)
+		}
+	}
+	db.MarkPoolStats(ctx)
 
-	log.Printf("Starting %s benchmark: %d threads, %d batch size, %s records for %ds", 
-		storageType, threads, batchSize, recordSize, scenario.Duration)
+	return &workloadRun{
+		scenario:    scenario.Name,
+		storageType: storageType,
+		mountOption: mountOption,
+		db:          db,
+		workload:    workload,
+		countBound:  spec.countBound,
+		threads:     threads,
+		seed:        seed,
+		thinkTime:   thinkTime,
+		limiter:     limiter,
+		targetRate:  targetRate,
+		collector:   r.newCollector(),
+		threadCollectors: r.newThreadCollectors(threads),
+		nfsBefore:   nfsBefore,
+	}, nil
+}
 
-	// Create metrics collector
-	collector := metrics.NewCollector()
+// measureWorkload runs the workload threads for the given duration, or until every thread
+// runs out of work when duration is 0, and merges the measurements into the run's
+// collector. If a thread gives up after too many errors in a row, or the error rate
+// exceeds execution.abort_on_error_rate, the threads are stopped and the error returned.
+func (r *Runner) measureWorkload(ctx context.Context, run *workloadRun, duration time.Duration) error {
+	collector := r.newCollector(r.streamTimeline("postgresql", run.scenario, run.storageType, run.mountOption)...)
 	collector.Start()
+	defer r.live.track(&liveRun{
+		database:    "postgresql",
+		scenario:    run.scenario,
+		storageType: run.storageType,
+		mountOption: run.mountOption,
+		collector:   collector,
+	})()
 
-	// Run workload for specified duration
+	// Operations run under the run's context rather than the slice deadline, so an
+	// operation in flight when the slice ends still completes while cancelling the run
+	// aborts it.
 	var wg sync.WaitGroup
-	ctx, cancel := context.WithTimeout(ctx, time.Duration(scenario.Duration)*time.Second)
+	queryCtx := ctx
+	var cancel context.CancelFunc
+	if duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, duration)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
 	defer cancel()
 
-	var totalInserted int64
+	var totalItems int64
+	var threadErr error
 	var mu sync.Mutex
 
-	for i := 0; i < threads; i++ {
+	// Count-bound workloads already split their work evenly, and a thread finishing its
+	// share must not stop the others
+	var barrier *roundBarrier
+	if r.config.Execution.Deterministic && duration > 0 {
+		barrier = newRoundBarrier(run.threads)
+	}
+
+	threadCollectors := r.newThreadCollectors(run.threads)
+	for _, c := range threadCollectors {
+		c.Start()
+	}
+
+	for i := 0; i < run.threads; i++ {
 		wg.Add(1)
 		go func(threadID int) {
 			defer wg.Done()
-			threadInserted := r.runInsertThread(ctx, db, batchSize, recordSize, collector)
+			perThread := threadCollector(threadCollectors, threadID)
+			items, err := r.runWorkloadThread(ctx, queryCtx, run, threadID, collector, perThread, barrier)
+			if perThread != nil {
+				perThread.End()
+				perThread.SetThroughput(items)
+			}
 			mu.Lock()
-			totalInserted += threadInserted
+			totalItems += items
+			if err != nil && threadErr == nil {
+				threadErr = err
+				cancel()
+			}
 			mu.Unlock()
 		}(i)
 	}
 
+	// Without a duration the total is unknown, so progress is logged without a bar
+	progressDone := make(chan struct{})
+	progressCtx, stopProgress := context.WithCancel(ctx)
+	watchDone := make(chan struct{})
+	go func() {
+		defer close(watchDone)
+		r.watchErrorRate(progressCtx, run.db.GetName(), collector, func(err error) {
+			mu.Lock()
+			if threadErr == nil {
+				threadErr = err
+				cancel()
+			}
+			mu.Unlock()
+		})
+	}()
+	if reporter := r.newProgressReporter(run.db.GetName(), duration, collector); reporter != nil {
+		if duration == 0 {
+			reporter.showBar = false
+		}
+		go func() {
+			defer close(progressDone)
+			reporter.Run(progressCtx)
+		}()
+	} else {
+		close(progressDone)
+	}
+
 	wg.Wait()
+	stopProgress()
+	<-progressDone
+	<-watchDone
 	collector.End()
-	collector.SetThroughput(totalInserted)
+	collector.SetThroughput(totalItems)
+	run.collector.Merge(collector)
+	mergeThreadCollectors(run.threadCollectors, threadCollectors)
 
+	// A count-bound workload cut short has no meaningful result
+	if threadErr == nil && duration == 0 {
+		threadErr = queryCtx.Err()
+	}
+	if threadErr != nil && duration == 0 {
+		return fmt.Errorf("workload stopped after %d items: %w", totalItems, threadErr)
+	}
+	return threadErr
+}
+
+// newCollector creates a metrics collector using the configured latency recorder, with
+// any extra options
+func (r *Runner) newCollector(extra ...metrics.Option) *metrics.Collector {
+	opts := []metrics.Option{metrics.WithPercentiles(r.config.Metrics.LatencyPercentiles)}
+	if r.config.Metrics.LatencyRecorder == "histogram" {
+		opts = append(opts, metrics.WithHistogram())
+	}
+	if r.config.Metrics.Timeline || r.config.Metrics.TimelineStream {
+		opts = append(opts, metrics.WithTimeline(r.config.GetCollectionInterval()))
+	}
+	if r.config.Metrics.FailedLatency {
+		opts = append(opts, metrics.WithFailedLatency())
+	}
+	return metrics.NewCollector(append(opts, extra...)...)
+}
+
+// finishWorkload gathers final database stats and builds the storage type's result
+func (r *Runner) finishWorkload(ctx context.Context, run *workloadRun, scenario config.ScenarioConfig) *ScenarioResult {
 	// Get final database stats
-	dbStats, err := db.GetStats()
+	dbStats, err := run.db.GetStats(ctx)
 	if err != nil {
-		log.Printf("Failed to get database stats: %v", err)
+		slog.Error("Failed to get database stats", "error", err)
 		dbStats = make(map[string]interface{})
 	}
 
 	// Get final record count
-	recordCount, err := db.CountRecords()
+	recordCount, err := run.db.CountRecords(ctx)
 	if err != nil {
-		log.Printf("Failed to count records: %v", err)
+		slog.Error("Failed to count records", "error", err)
 	}
 	dbStats["final_record_count"] = recordCount
 
-	results := collector.Results()
-	log.Printf("%s results: %d ops in %v (%.2f ops/sec), avg latency: %v, p95: %v", 
-		storageType, results.TotalOperations, results.TotalDuration, 
-		results.OperationsPerSecond, results.AverageLatency, results.P95Latency)
-
-	return &ScenarioResult{
-		Name:        scenario.Name,
-		Database:    "postgresql",
-		StorageType: storageType,
-		Duration:    results.TotalDuration,
-		Success:     true,
-		Metrics:     results,
-		DBStats:     dbStats,
-	}, nil
+	if run.nfsBefore != nil {
+		addNFSStats(dbStats, run.db, run.nfsBefore)
+	}
+	if reporter, ok := run.workload.(WorkloadStats); ok {
+		for key, value := range reporter.Stats(ctx, run.db) {
+			dbStats[key] = value
+		}
+	}
+
+	results := run.collector.Results()
+	slog.Info("Benchmark results",
+		"storage_type", run.storageType,
+		"operations", results.TotalOperations,
+		"duration", results.TotalDuration,
+		"ops_per_sec", results.OperationsPerSecond,
+		"mb_per_sec", results.MegabytesPerSecond(),
+		"avg_latency", results.AverageLatency,
+		"stddev_latency", results.StdDevLatency,
+		"cv", results.CoefficientOfVariation,
+		"p95_latency", results.P95Latency,
+		"p95_conn_acquire", results.Phases[PhaseConnAcquire].P95Latency,
+		"p95_execute", results.Phases[PhaseExecute].P95Latency,
+		"p95_commit", results.Phases[PhaseCommit].P95Latency,
+		"errors", results.ErrorCount,
+		"timeouts", results.Timeouts,
+		"error_rate", results.ErrorRate)
+	for _, e := range results.TopErrors {
+		slog.Warn("Benchmark errors", "storage_type", run.storageType, "count", e.Count, "message", e.Message)
+	}
+	if results.Failed != nil && results.Failed.Count > 0 {
+		slog.Warn("Time spent on failed attempts", "storage_type", run.storageType, "attempts", results.Failed.Count, "total", results.Failed.TotalTime, "p95", results.Failed.P95Latency)
+	}
+
+	result := &ScenarioResult{
+		Name:         scenario.Name,
+		Database:     "postgresql",
+		StorageType:  run.storageType,
+		MountOption:  run.mountOption,
+		Duration:     results.TotalDuration,
+		Success:      true,
+		Metrics:      results,
+		DBStats:      dbStats,
+		PerThread:    newThreadMetrics(run.threadCollectors),
+		collector:    run.collector,
+		threadCollectors: run.threadCollectors,
+	}
+	logThreadMetrics(run.storageType, result.PerThread)
+	if run.limiter != nil {
+		result.RateTarget = newRateTarget(run.targetRate, results)
+		logRateTarget(run.storageType, result.RateTarget)
+	}
+	if result.SessionSettings, err = run.db.SessionSettings(ctx); err != nil {
+		slog.Warn("Failed to read session settings", "storage_type", run.storageType, "error", err)
+	}
+	r.verifyWorkload(ctx, run, result)
+	checkDurability(run, result)
+	return result
 }
 
-func (r *Runner) runInsertThread(ctx context.Context, db database.Database, batchSize int, recordSize database.RecordSize, collector *metrics.Collector) int64 {
-	var inserted int64
+// checkDurability records the rows read back with verify_durability. Committed rows that
+// went missing fail the result, keeping its metrics, like a failed data verification.
+func checkDurability(run *workloadRun, result *ScenarioResult) {
+	checker, ok := run.workload.(WorkloadDurability)
+	if !ok {
+		return
+	}
+	durability := checker.Durability()
+	if durability == nil {
+		return
+	}
+	result.Durability = durability
+	if err := durability.Err(); err != nil {
+		result.Success = false
+		if result.Error == nil {
+			result.Error = err
+		}
+		slog.Error("DURABILITY CHECK FAILED",
+			"storage_type", run.storageType,
+			"mount_option", run.mountOption,
+			"sampled_rows", durability.SampledRows,
+			"missing_rows", durability.MissingRows)
+		return
+	}
+	slog.Info("Durability checked",
+		"storage_type", run.storageType,
+		"sampled_rows", durability.SampledRows,
+		"check_errors", durability.CheckErrors)
+}
+
+// verifyWorkload checks the stored data when execution.verify_data is set. A mismatch
+// fails the result, keeping its metrics, since rows were lost or altered on the way to
+// storage.
+func (r *Runner) verifyWorkload(ctx context.Context, run *workloadRun, result *ScenarioResult) {
+	if !r.config.Execution.VerifyData {
+		return
+	}
+	verifier, ok := run.workload.(WorkloadVerifier)
+	if !ok {
+		slog.Warn("Scenario does not support data verification", "scenario", result.Name)
+		return
+	}
+
+	verification, err := verifier.Verify(ctx, run.db)
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Errorf("data verification could not run: %w", err)
+		slog.Error("Data verification could not run", "storage_type", run.storageType, "error", err)
+		return
+	}
+	result.Verification = verification
+	if err := verification.Err(); err != nil {
+		result.Success = false
+		result.Error = err
+		slog.Error("DATA VERIFICATION FAILED",
+			"storage_type", run.storageType,
+			"mount_option", run.mountOption,
+			"expected_rows", verification.Expected.Rows,
+			"actual_rows", verification.Actual.Rows,
+			"expected_checksum", verification.Expected.Sum,
+			"actual_checksum", verification.Actual.Sum)
+		return
+	}
+	slog.Info("Data verified", "storage_type", run.storageType, "rows", verification.Actual.Rows)
+}
+
+// rawSamples returns the latency samples to export when metrics.export_raw is set
+func (r *Runner) rawSamples(collector *metrics.Collector) []time.Duration {
+	if !r.config.Metrics.ExportRaw {
+		return nil
+	}
+	if r.config.Metrics.LatencyRecorder == "histogram" {
+		slog.Warn("Raw latency export needs the exact latency recorder; skipping")
+		return nil
+	}
+	return collector.Samples(r.config.Metrics.RawSampleLimit)
+}
+
+// runWorkloadThread performs the workload's operations for one thread until ctx is done
+// or the workload has no work left for it, issuing them under queryCtx. It returns the
+// items processed, and an error if it gives up after execution.max_consecutive_errors
+// failures in a row. After every successful operation it pauses for the think_time, then
+// with a barrier waits for the other threads. Operations are also recorded into
+// threadCollector, if not nil.
+func (r *Runner) runWorkloadThread(ctx, queryCtx context.Context, run *workloadRun, thread int, collector, threadCollector *metrics.Collector, barrier *roundBarrier) (int64, error) {
+	var items int64
+	backoff := r.newErrorBackoff()
+	// Separate from the workload's generators, so think times don't change record content
+	thinkRng := rand.New(rand.NewSource(run.seed + int64(thread)))
+	if barrier != nil {
+		defer barrier.Break()
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			return inserted
+			return items, nil
 		default:
-			// Generate batch of records
-			batch := database.GenerateBenchmarkRecords(batchSize, recordSize)
-
-			// Measure insert latency
-			start := time.Now()
-			err := db.InsertBatch(batch)
-			latency := time.Since(start)
-
+			if run.limiter != nil && run.limiter.Wait(ctx) != nil {
+				return items, nil // the run ends before the next operation is due
+			}
+			op, err := r.runOp(queryCtx, run, thread)
+			if errors.Is(err, ErrWorkloadDone) {
+				return items, nil
+			}
 			if err != nil {
+				// A failed operation may still have completed some of its items
+				items += op.Items
+				if op.Bytes > 0 {
+					collector.AddBytes(op.Bytes)
+					if threadCollector != nil {
+						threadCollector.AddBytes(op.Bytes)
+					}
+				}
+				if queryCtx.Err() != nil {
+					return items, nil // run cancelled; not a storage error
+				}
+				timeout := isTimeout(err)
 				collector.AddError(err)
-				time.Sleep(time.Millisecond * 100) // Brief pause on error
+				if timeout {
+					collector.AddTimeout()
+				}
+				if op.Latency > 0 {
+					collector.AddFailedLatency(op.Latency)
+				}
+				if threadCollector != nil {
+					threadCollector.AddError(err)
+					if timeout {
+						threadCollector.AddTimeout()
+					}
+				}
+				if err := backoff.failed(ctx, err); err != nil {
+					return items, err
+				}
 				continue
 			}
 
-			collector.AddLatency(latency)
-			inserted += int64(batchSize)
+			backoff.succeeded()
+			collector.AddLatency(op.Latency)
+			for phase, latency := range op.Phases {
+				collector.AddPhaseLatency(phase, latency)
+			}
+			if op.Bytes > 0 {
+				collector.AddBytes(op.Bytes)
+			}
+			if threadCollector != nil {
+				threadCollector.AddLatency(op.Latency)
+				threadCollector.AddBytes(op.Bytes)
+			}
+			items += op.Items
+
+			run.thinkTime.pause(ctx, thinkRng)
+			if barrier != nil && !barrier.Wait() {
+				return items, nil
+			}
 		}
 	}
 }
 
-func (r *Runner) saveScenarioResults(outputDir string, directResult, nfsResult *ScenarioResult) error {
-	results := map[string]*ScenarioResult{
-		"direct": directResult,
-		"nfs":    nfsResult,
+// runOp performs one of the workload's operations, cancelling it if it runs longer than
+// execution.statement_timeout_ms
+func (r *Runner) runOp(ctx context.Context, run *workloadRun, thread int) (OpResult, error) {
+	timeout := r.config.GetStatementTimeout()
+	if timeout <= 0 {
+		return run.workload.RunOp(ctx, run.db, thread)
+	}
+	opCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return run.workload.RunOp(opCtx, run.db, thread)
+}
+
+// isTimeout reports whether a failed operation ran out of time, on the server or against
+// the statement timeout's deadline
+func isTimeout(err error) bool {
+	return errors.Is(err, database.ErrTimeout) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Phases recorded for each insert batch in Results.Phases
+const (
+	PhaseConnAcquire = "conn_acquire" // waiting for a pooled connection
+	PhaseExecute     = "execute"      // running the batch transaction on the connection
+	PhaseWrite       = "write"        // part of execute spent executing the inserts
+	PhaseCommit      = "commit"       // part of execute spent committing, where storage is flushed
+)
+
+// saveScenarioResults writes the results gathered so far for a database/scenario combination,
+// keyed by storage label, with the insert mode appended when a scenario runs several
+// (modes lists them, or holds only "" otherwise). Each NFS mount option variant and each
+// insert mode is also written to <database>_<scenario>_<variant>_<mode>.json (leaving out
+// whichever of the two does not apply) alongside the direct result, in the same direct/nfs
+// shape as a run without either. Callers must hold results.mu.
+func (r *Runner) saveScenarioResults(results *Results, database, scenario string, modes []string) error {
+	combined := make(map[string]*ScenarioResult)
+	for _, label := range r.storageLabels(database) {
+		for _, mode := range modes {
+			if result, ok := results.ScenarioResults[resultKey(database, scenario, modeLabel(label, mode))]; ok {
+				combined[modeLabel(label, mode)] = result
+			}
+		}
+	}
+
+	if err := r.writeResultsFile(filepath.Join(results.OutputDir, fmt.Sprintf("%s_%s.json", database, scenario)), combined); err != nil {
+		return err
 	}
 
-	filePath := filepath.Join(outputDir, "postgresql_heavy_inserts.json")
-	file, err := os.Create(filePath)
+	variants := r.mountVariants(database)
+	for _, mode := range modes {
+		direct, ok := combined[modeLabel("direct", mode)]
+		if !ok {
+			continue
+		}
+		modeVariants := variants
+		if len(modeVariants) == 0 {
+			if mode == "" {
+				continue // the combined file already is the direct/nfs pair
+			}
+			modeVariants = []string{""}
+		}
+		for _, variant := range modeVariants {
+			result, ok := combined[modeLabel(storageLabel("nfs", variant), mode)]
+			if !ok {
+				continue
+			}
+			suffix := strings.Trim(variant+"_"+mode, "_")
+			path := filepath.Join(results.OutputDir, fmt.Sprintf("%s_%s_%s.json", database, scenario, suffix))
+			if err := r.writeResultsFile(path, map[string]*ScenarioResult{"direct": direct, "nfs": result}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ResultsConfigKey is the results file entry holding the effective config of the run,
+// next to the entries keyed by storage label
+const ResultsConfigKey = "config"
+
+// writeResultsFile writes results keyed by storage label, plus the effective config, as
+// indented JSON, gzipped to path.gz when reporting.compress is set
+func (r *Runner) writeResultsFile(path string, results map[string]*ScenarioResult) error {
+	entries := make(map[string]interface{}, len(results)+1)
+	for label, result := range results {
+		entries[label] = result
+	}
+	entries[ResultsConfigKey] = r.config.Effective()
+
+	if r.config.Reporting.Compress {
+		path += ".gz"
+	}
+	file, err := os.Create(path)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
+	var w io.Writer = file
+	var gz *gzip.Writer
+	if r.config.Reporting.Compress {
+		gz = gzip.NewWriter(file)
+		w = gz
+	}
+
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(results)
+	if err := encoder.Encode(entries); err != nil {
+		return err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// ReadResultsFile reads a results file written by the runner, decompressing it if it is
+// gzipped (reporting.compress writes .json.gz files)
+func ReadResultsFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadResults(f)
+}
+
+// ReadResults reads results JSON, decompressing it if it is gzipped
+func ReadResults(r io.Reader) ([]byte, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return io.ReadAll(gz)
+	}
+	return io.ReadAll(br)
+}
+
+// stringListParam reads a scenario parameter given either as a YAML list or a comma-separated string
+func stringListParam(value interface{}) []string {
+	var items []string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, item := range v {
+			items = append(items, fmt.Sprintf("%v", item))
+		}
+	case []string:
+		items = v
+	default:
+		items = strings.Split(fmt.Sprintf("%v", v), ",")
+	}
+
+	var list []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// intParam reads an integer scenario parameter, returning def when it is not set
+func intParam(params map[string]interface{}, key string, def int) (int, error) {
+	value, ok := params[key]
+	if !ok || value == nil {
+		return def, nil
+	}
+	n, err := strconv.Atoi(fmt.Sprintf("%v", value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s parameter %v: %w", key, value, err)
+	}
+	return n, nil
 }
 
 // GetOverheadPercent calculates the performance overhead of NFS vs direct storage
@@ -0,0 +1,56 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+)
+
+func TestStorageEnds(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Execution.SliceDuration = 10
+	r := NewRunner(cfg)
+
+	scenario := func(duration int, params map[string]interface{}) config.ScenarioConfig {
+		return config.ScenarioConfig{Name: "heavy_inserts", Duration: duration, Parameters: params}
+	}
+	for _, tc := range []struct {
+		name        string
+		task        task
+		first, last string
+	}{
+		{"single storage", task{Scenario: scenario(60, nil), StorageTypes: []string{"nfs"}}, "nfs", "nfs"},
+		{"mount option variant", task{Scenario: scenario(60, nil), StorageTypes: []string{"nfs"}, MountOption: "soft"}, "nfs_soft", "nfs_soft"},
+		// 3 rounds: direct,nfs / nfs,direct / direct,nfs
+		{"odd rounds", task{Scenario: scenario(30, nil), StorageTypes: []string{"direct", "nfs"}}, "direct", "nfs"},
+		// 2 rounds (the last one partial): direct,nfs / nfs,direct
+		{"even rounds", task{Scenario: scenario(15, nil), StorageTypes: []string{"direct", "nfs"}}, "direct", "direct"},
+		{"cold/warm passes", task{Scenario: scenario(20, map[string]interface{}{"cache_mode": cacheModeColdWarm}), StorageTypes: []string{"direct", "nfs"}}, "direct", "nfs"},
+	} {
+		first, last := r.storageEnds(tc.task)
+		if first != tc.first || last != tc.last {
+			t.Errorf("%s: expected %s to %s, got %s to %s", tc.name, tc.first, tc.last, first, last)
+		}
+	}
+}
+
+func TestEstimatePausesBetweenInterleavedTasks(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Databases = map[string]config.DatabaseConfig{"postgresql": {Enabled: true}}
+	cfg.Execution.StorageTypes = []string{"direct", "nfs"}
+	cfg.Execution.Interleave = true
+	cfg.Execution.SliceDuration = 10
+	cfg.Execution.InterRunPause = 5
+	cfg.Scenarios = []config.ScenarioConfig{
+		{Name: "heavy_inserts", Enabled: true, Duration: 30},
+		{Name: "heavy_inserts_large", Workload: "heavy_inserts", Enabled: true, Duration: 30},
+	}
+
+	// Each task runs 3 rounds with 1 switch each and ends on nfs, so the second task,
+	// starting on direct, adds a switch between the tasks
+	estimate := EstimateRuntime(cfg)
+	want := 2*(60+3*5) + 5
+	if got := int(estimate.Total.Seconds()); got != want {
+		t.Errorf("Expected %ds, got %ds (%d tasks)", want, got, estimate.Tasks)
+	}
+}
@@ -0,0 +1,66 @@
+package benchmark
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"golang.org/x/time/rate"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// sustainedRateFraction is the share of target_ops_per_sec a run must achieve for the
+// target to count as sustained
+const sustainedRateFraction = 0.95
+
+// RateTarget records how a run with target_ops_per_sec fared against its target
+type RateTarget struct {
+	TargetOpsPerSec   float64 `json:"target_ops_per_sec"`
+	AchievedOpsPerSec float64 `json:"achieved_ops_per_sec"` // the saturation point when not sustained
+	Sustained         bool    `json:"sustained"`
+}
+
+// newRateTarget compares a run's measured operation rate with its target
+func newRateTarget(target float64, results *metrics.Results) *RateTarget {
+	return &RateTarget{
+		TargetOpsPerSec:   target,
+		AchievedOpsPerSec: results.OperationsPerSecond,
+		Sustained:         results.OperationsPerSecond >= target*sustainedRateFraction,
+	}
+}
+
+// parseTargetRate reads the target_ops_per_sec parameter and returns a limiter that
+// paces all of a run's threads together to that many operations per second, or nil when
+// the parameter is unset. The threads still wait for each operation to finish before
+// starting the next, so a storage type that cannot keep up falls short of the target
+// instead of queueing work.
+func parseTargetRate(params map[string]interface{}) (*rate.Limiter, float64, error) {
+	value, ok := params["target_ops_per_sec"]
+	if !ok || value == nil {
+		return nil, 0, nil
+	}
+	target, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid target_ops_per_sec parameter %v: %w", value, err)
+	}
+	if target <= 0 {
+		return nil, 0, fmt.Errorf("target_ops_per_sec must be positive, got %v", target)
+	}
+	return rate.NewLimiter(rate.Limit(target), 1), target, nil
+}
+
+// logRateTarget reports whether a storage type sustained its target rate
+func logRateTarget(storageType string, target *RateTarget) {
+	if target.Sustained {
+		slog.Info("Target rate sustained",
+			"storage_type", storageType,
+			"target_ops_per_sec", target.TargetOpsPerSec,
+			"achieved_ops_per_sec", target.AchievedOpsPerSec)
+		return
+	}
+	slog.Warn("Target rate not sustained; storage saturated below it",
+		"storage_type", storageType,
+		"target_ops_per_sec", target.TargetOpsPerSec,
+		"achieved_ops_per_sec", target.AchievedOpsPerSec)
+}
@@ -0,0 +1,66 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// thinkTime is the pause a workload thread takes between operations, modelling an
+// application that does other work between queries. Running below saturation shows the
+// latency NFS adds at moderate load rather than only its peak throughput.
+type thinkTime struct {
+	mean        time.Duration
+	exponential bool // draw each pause from an exponential distribution with the mean
+}
+
+// parseThinkTime reads the think_time parameter, given in milliseconds or as a duration
+// string such as "2.5ms", and think_time_distribution: fixed (default) or exponential
+func parseThinkTime(params map[string]interface{}) (thinkTime, error) {
+	var t thinkTime
+	switch v := params["think_time"].(type) {
+	case nil:
+		return t, nil
+	case int:
+		t.mean = time.Duration(v) * time.Millisecond
+	case float64:
+		t.mean = time.Duration(v * float64(time.Millisecond))
+	default:
+		d, err := time.ParseDuration(fmt.Sprintf("%v", v))
+		if err != nil {
+			return t, fmt.Errorf("invalid think_time parameter %v: want milliseconds or a duration like 5ms", v)
+		}
+		t.mean = d
+	}
+	if t.mean < 0 {
+		return t, fmt.Errorf("think_time must not be negative, got %s", t.mean)
+	}
+
+	switch distribution := fmt.Sprintf("%v", params["think_time_distribution"]); distribution {
+	case "<nil>", "", "fixed":
+	case "exponential":
+		t.exponential = true
+	default:
+		return t, fmt.Errorf("invalid think_time_distribution %q (valid: fixed, exponential)", distribution)
+	}
+	return t, nil
+}
+
+// pause sleeps for one think time, returning early if ctx ends
+func (t thinkTime) pause(ctx context.Context, rng *rand.Rand) {
+	if t.mean <= 0 {
+		return
+	}
+	d := t.mean
+	if t.exponential {
+		d = time.Duration(rng.ExpFloat64() * float64(t.mean))
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,87 @@
+package benchmark
+
+import (
+	"log/slog"
+	"math"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// ThreadMetrics breaks a run's measurements down by worker thread, with
+// metrics.per_thread, to show whether the load was balanced: a thread stuck on a slow
+// connection has visibly lower throughput and a worse tail than the others
+type ThreadMetrics struct {
+	Threads      []*metrics.Results `json:"threads"`       // indexed by thread
+	ThroughputCV float64            `json:"throughput_cv"` // stddev / mean of the threads' ops/sec; 0 is perfectly even
+	Slowest      int                `json:"slowest"`       // thread with the lowest ops/sec
+}
+
+// newThreadCollectors returns a collector per thread when metrics.per_thread is set, or
+// nil. They record into histograms, so the breakdown costs a fixed amount of memory
+// however long the run.
+func (r *Runner) newThreadCollectors(threads int) []*metrics.Collector {
+	if !r.config.Metrics.PerThread {
+		return nil
+	}
+	collectors := make([]*metrics.Collector, threads)
+	for i := range collectors {
+		collectors[i] = metrics.NewCollector(metrics.WithHistogram(), metrics.WithPercentiles(r.config.Metrics.LatencyPercentiles))
+	}
+	return collectors
+}
+
+// threadCollector returns a thread's collector, or nil without per-thread metrics
+func threadCollector(collectors []*metrics.Collector, thread int) *metrics.Collector {
+	if collectors == nil {
+		return nil
+	}
+	return collectors[thread]
+}
+
+// mergeThreadCollectors adds each thread's measurements in from to its collector in
+// into, by thread index
+func mergeThreadCollectors(into, from []*metrics.Collector) {
+	for i := range from {
+		if i < len(into) {
+			into[i].Merge(from[i])
+		}
+	}
+}
+
+// newThreadMetrics summarizes per-thread collectors, or returns nil without any
+func newThreadMetrics(collectors []*metrics.Collector) *ThreadMetrics {
+	if len(collectors) == 0 {
+		return nil
+	}
+	tm := &ThreadMetrics{Threads: make([]*metrics.Results, len(collectors))}
+	var sum, sumSquares float64
+	for i, c := range collectors {
+		tm.Threads[i] = c.Results()
+		ops := tm.Threads[i].OperationsPerSecond
+		sum += ops
+		sumSquares += ops * ops
+		if ops < tm.Threads[tm.Slowest].OperationsPerSecond {
+			tm.Slowest = i
+		}
+	}
+	n := float64(len(collectors))
+	if mean := sum / n; mean > 0 {
+		tm.ThroughputCV = math.Sqrt(math.Max(sumSquares/n-mean*mean, 0)) / mean
+	}
+	return tm
+}
+
+// logThreadMetrics logs how evenly the threads shared the load
+func logThreadMetrics(storageType string, tm *ThreadMetrics) {
+	if tm == nil {
+		return
+	}
+	slowest := tm.Threads[tm.Slowest]
+	slog.Info("Per-thread throughput",
+		"storage_type", storageType,
+		"threads", len(tm.Threads),
+		"throughput_cv", tm.ThroughputCV,
+		"slowest_thread", tm.Slowest,
+		"slowest_ops_per_sec", slowest.OperationsPerSecond,
+		"slowest_p99_latency", slowest.P99Latency)
+}
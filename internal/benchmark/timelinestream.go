@@ -0,0 +1,112 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// timelineStreamFile is the file in the output directory that metrics.timeline_stream
+// appends timeline intervals to
+const timelineStreamFile = "timeline.jsonl"
+
+// timelineStream writes the latency timeline intervals of every measurement to
+// timeline.jsonl, one JSON object per line, as soon as each interval closes. Lines are
+// written straight to the file, so a dashboard can follow the run and a run killed
+// midway leaves the intervals measured so far on disk.
+type timelineStream struct {
+	mu     sync.Mutex
+	f      *os.File
+	failed bool // a write failed; further writes are skipped
+}
+
+// timelineLine is one interval of one run in timeline.jsonl
+type timelineLine struct {
+	Time        time.Time       `json:"time"` // wall clock start of the interval
+	Database    string          `json:"database"`
+	Scenario    string          `json:"scenario"`
+	StorageType string          `json:"storage_type"`
+	MountOption string          `json:"mount_option,omitempty"`
+	Start       time.Duration   `json:"start"` // offset from the start of the measurement window
+	Operations  int64           `json:"operations"`
+	Percentiles []float64       `json:"percentiles"`
+	Latencies   []time.Duration `json:"latencies"` // one per percentile, in the same order; null without operations
+}
+
+// openTimelineStream starts streaming timeline intervals into the output directory if
+// metrics.timeline_stream is set
+func (r *Runner) openTimelineStream(outputDir string) error {
+	if !r.config.Metrics.TimelineStream {
+		return nil
+	}
+	path := filepath.Join(outputDir, timelineStreamFile)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create timeline stream: %w", err)
+	}
+	r.timelineStream = &timelineStream{f: f}
+	slog.Info("Streaming latency timeline", "path", path, "interval", r.config.GetCollectionInterval())
+	return nil
+}
+
+// closeTimelineStream closes the file opened by openTimelineStream
+func (r *Runner) closeTimelineStream() {
+	if r.timelineStream == nil {
+		return
+	}
+	if err := r.timelineStream.f.Close(); err != nil {
+		slog.Error("Failed to close timeline stream", "error", err)
+	}
+	r.timelineStream = nil
+}
+
+// streamTimeline returns the collector options that stream the timeline of a run's
+// measurement window, or none without a stream
+func (r *Runner) streamTimeline(database, scenario, storageType, mountOption string) []metrics.Option {
+	s := r.timelineStream
+	if s == nil {
+		return nil
+	}
+	percentiles := r.config.Metrics.LatencyPercentiles
+	if len(percentiles) == 0 {
+		percentiles = metrics.DefaultPercentiles
+	}
+	return []metrics.Option{metrics.WithTimelineSink(func(start time.Time, bucket metrics.TimelineBucket) {
+		s.write(timelineLine{
+			Time:        start,
+			Database:    database,
+			Scenario:    scenario,
+			StorageType: storageType,
+			MountOption: mountOption,
+			Start:       bucket.Start,
+			Operations:  bucket.Operations,
+			Percentiles: percentiles,
+			Latencies:   bucket.Latencies,
+		})
+	})}
+}
+
+// write appends one line. A failure is logged once and stops the stream rather than
+// failing the run, like a failure to write the results files.
+func (s *timelineStream) write(line timelineLine) {
+	data, err := json.Marshal(line)
+	if err != nil {
+		slog.Error("Failed to encode timeline interval", "error", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failed {
+		return
+	}
+	if _, err := s.f.Write(append(data, '\n')); err != nil {
+		s.failed = true
+		slog.Error("Failed to write timeline stream; streaming stopped", "path", s.f.Name(), "error", err)
+	}
+}
@@ -0,0 +1,127 @@
+package benchmark
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/nfs"
+)
+
+// mountVariants returns the names of the NFS mount option variants mapped for a target:
+// those with a connection for a database, or with a path for filesystem scenarios. When
+// there are none, NFS runs use the target's single NFS connection or nfs.path. Variants
+// with a version only run if nfs.versions selects it.
+func (r *Runner) mountVariants(target string) []string {
+	var names []string
+	for _, option := range r.config.NFS.MountOptions {
+		if option.Version != "" && len(r.config.NFS.Versions) > 0 && !nfs.MatchesVersion(option.Version, r.config.NFS.Versions) {
+			continue
+		}
+		if target == filesystemTarget {
+			if option.Path != "" {
+				names = append(names, option.Name)
+			}
+		} else if _, ok := option.Connections[target]; ok {
+			names = append(names, option.Name)
+		}
+	}
+	return names
+}
+
+// mountOption returns the configured mount option variant with the given name
+func (r *Runner) mountOption(name string) (config.NFSMountOption, error) {
+	for _, option := range r.config.NFS.MountOptions {
+		if option.Name == name {
+			return option, nil
+		}
+	}
+	return config.NFSMountOption{}, fmt.Errorf("unknown NFS mount option variant %q", name)
+}
+
+// connectionConfig returns the connection settings of a database for a storage type and,
+// for NFS, a mount option variant (empty for the database's own NFS connection), with
+// {run_id} in its table replaced by the run's id
+func (r *Runner) connectionConfig(db, storageType, mountOption string) (config.DatabaseConnectionConfig, error) {
+	dbConfig := r.config.Databases[db]
+	conn := dbConfig.Direct
+	if storageType != "direct" {
+		conn = dbConfig.NFS
+	}
+	if storageType != "direct" && mountOption != "" {
+		option, err := r.mountOption(mountOption)
+		if err != nil {
+			return config.DatabaseConnectionConfig{}, err
+		}
+		var ok bool
+		conn, ok = option.Connection(db, dbConfig.NFS)
+		if !ok {
+			return config.DatabaseConnectionConfig{}, fmt.Errorf("NFS mount option variant %q has no connection for %s", mountOption, db)
+		}
+	}
+	conn.Table = strings.ReplaceAll(conn.Table, config.RunIDPlaceholder, r.runID)
+	return conn, nil
+}
+
+// storageLabel names a storage type in result keys and file names, qualifying NFS runs
+// of a mount option variant with the variant's name (e.g. nfs_sync_mode)
+func storageLabel(storageType, mountOption string) string {
+	if mountOption == "" {
+		return storageType
+	}
+	return storageType + "_" + mountOption
+}
+
+// mountOptionsFor returns the mount option variants a storage type of a target runs
+// with: every mapped variant for NFS, otherwise only the default ("")
+func (r *Runner) mountOptionsFor(target, storageType string) []string {
+	if storageType == "nfs" {
+		if variants := r.mountVariants(target); len(variants) > 0 {
+			return variants
+		}
+	}
+	return []string{""}
+}
+
+// storageLabels lists the storage labels a target's results are saved under, in
+// execution.storage_types order with NFS expanded into its mount option variants
+func (r *Runner) storageLabels(target string) []string {
+	var labels []string
+	for _, storageType := range r.config.Execution.StorageTypes {
+		for _, mountOption := range r.mountOptionsFor(target, storageType) {
+			labels = append(labels, storageLabel(storageType, mountOption))
+		}
+	}
+	return labels
+}
+
+// storageTasks plans a scenario on one storage type of a target: a single task, or for
+// NFS with mapped mount option variants, one task per variant
+func (r *Runner) storageTasks(target string, scenario config.ScenarioConfig, storageType string) []task {
+	var tasks []task
+	for _, mountOption := range r.mountOptionsFor(target, storageType) {
+		tasks = append(tasks, task{Database: target, Scenario: scenario, StorageTypes: []string{storageType}, MountOption: mountOption})
+	}
+	return tasks
+}
+
+// expectedNFSVersions returns the NFS versions the mount of a mount option variant may
+// have: a versioned variant's own version, otherwise any of nfs.versions
+func (r *Runner) expectedNFSVersions(mountOption string) []string {
+	if option, err := r.mountOption(mountOption); err == nil && option.Version != "" {
+		return []string{option.Version}
+	}
+	return r.config.NFS.Versions
+}
+
+// nfsVersion returns the NFS version a result's storage ran on: a versioned variant's
+// version, or the version the mount check found, or "" if neither is known
+func (r *Runner) nfsVersion(target, storageType, mountOption string) string {
+	if storageType != "nfs" {
+		return ""
+	}
+	if option, err := r.mountOption(mountOption); err == nil && option.Version != "" {
+		return option.Version
+	}
+	return r.mountVersions[target+"/"+mountOption]
+}
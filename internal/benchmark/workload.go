@@ -0,0 +1,163 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+)
+
+// Workload generates the operations of a database scenario. The runner owns everything
+// around it: connecting, threads, metrics, error backoff, progress, and interleaving. A
+// workload only prepares the database and performs one operation at a time, so adding a
+// scenario takes a Workload and a RegisterWorkload call rather than changes to the runner.
+type Workload interface {
+	// Setup prepares the database before measurement, e.g. creating and clearing tables
+	Setup(ctx context.Context, db database.Database) error
+
+	// RunOp performs one operation on behalf of a thread, numbered from 0 to
+	// WorkloadOptions.Threads-1. Threads call it concurrently, but each thread waits for
+	// its previous operation to return. A failed operation is retried by calling RunOp
+	// again. It returns ErrWorkloadDone once the thread has no work left.
+	RunOp(ctx context.Context, db database.Database, thread int) (OpResult, error)
+
+	// Teardown cleans up after measurement, once the result's stats are gathered
+	Teardown(ctx context.Context, db database.Database) error
+}
+
+// OpResult describes one successful workload operation. Returned with an error, only
+// Latency is used: the time the failed attempt took, if the workload measured it.
+type OpResult struct {
+	Latency time.Duration            // recorded as the operation's latency, or the failed attempt's
+	Items   int64                    // rows (or other units) processed, summed into throughput, even when the operation failed
+	Bytes   int64                    // payload bytes written, summed into Results.Bytes, even when the operation failed
+	Phases  map[string]time.Duration // optional latency breakdown, recorded in Results.Phases
+}
+
+// WorkloadStats is implemented by workloads that add entries to their result's DBStats.
+// Stats is called after measurement, before Teardown.
+type WorkloadStats interface {
+	Stats(ctx context.Context, db database.Database) map[string]interface{}
+}
+
+// WorkloadVerifier is implemented by workloads that can check, after measurement, that
+// the database holds exactly the data they wrote. The runner calls Verify when
+// execution.verify_data is set.
+type WorkloadVerifier interface {
+	Verify(ctx context.Context, db database.Database) (*Verification, error)
+}
+
+// WorkloadReplayer is implemented by count-bound workloads that can run exactly the same
+// operations again. The runner calls Replay between the passes of cache_mode: cold_warm,
+// so the warm pass repeats the cold pass's operations.
+type WorkloadReplayer interface {
+	Replay()
+}
+
+// WorkloadDurability is implemented by workloads that can read back a sample of the rows
+// they committed (the verify_durability parameter). Durability returns the outcome so
+// far, or nil if the workload does not check.
+type WorkloadDurability interface {
+	Durability() *DurabilityCheck
+}
+
+// Verification compares the data a workload wrote with what the database holds
+type Verification struct {
+	Expected database.RecordChecksum `json:"expected"`
+	Actual   database.RecordChecksum `json:"actual"`
+	Match    bool                    `json:"match"`
+}
+
+// Err describes a mismatch, or returns nil if the data matched
+func (v *Verification) Err() error {
+	if v.Match {
+		return nil
+	}
+	if v.Actual.Rows != v.Expected.Rows {
+		return fmt.Errorf("data verification failed: expected %d rows, found %d", v.Expected.Rows, v.Actual.Rows)
+	}
+	return fmt.Errorf("data verification failed: %d rows as expected, but their checksum differs", v.Actual.Rows)
+}
+
+// maxReportedMissingIDs caps the ids a DurabilityCheck lists, so a storage losing every
+// row doesn't bloat the results
+const maxReportedMissingIDs = 100
+
+// DurabilityCheck counts the committed rows that were sampled and read back right after
+// their commit. A row the database reported committed but can't find is a durability
+// failure, which is counted apart from failed operations: the insert itself succeeded.
+type DurabilityCheck struct {
+	SampledRows int64   `json:"sampled_rows"`
+	MissingRows int64   `json:"missing_rows"`
+	MissingIDs  []int64 `json:"missing_ids,omitempty"` // the first missing ids, up to maxReportedMissingIDs
+	CheckErrors int64   `json:"check_errors"`          // samples that could not be read back, e.g. on a lost connection
+}
+
+// add counts a sample of n rows, of which missing were not found
+func (d *DurabilityCheck) add(n int, missing []int64) {
+	d.SampledRows += int64(n)
+	d.MissingRows += int64(len(missing))
+	d.listMissing(missing)
+}
+
+// Merge adds the counts of another check, e.g. of another repeat
+func (d *DurabilityCheck) Merge(other *DurabilityCheck) {
+	d.SampledRows += other.SampledRows
+	d.MissingRows += other.MissingRows
+	d.CheckErrors += other.CheckErrors
+	d.listMissing(other.MissingIDs)
+}
+
+func (d *DurabilityCheck) listMissing(ids []int64) {
+	if room := maxReportedMissingIDs - len(d.MissingIDs); len(ids) > room {
+		ids = ids[:room]
+	}
+	d.MissingIDs = append(d.MissingIDs, ids...)
+}
+
+// Err describes missing rows, or returns nil if every sampled row was found
+func (d *DurabilityCheck) Err() error {
+	if d.MissingRows == 0 {
+		return nil
+	}
+	return fmt.Errorf("durability verification failed: %d of %d sampled committed rows are missing", d.MissingRows, d.SampledRows)
+}
+
+// ErrWorkloadDone is returned by RunOp when a count-bound workload has no work left for
+// the thread
+var ErrWorkloadDone = errors.New("workload done")
+
+// WorkloadOptions are the run settings a workload is built with
+type WorkloadOptions struct {
+	Threads       int   // threads that will call RunOp
+	Seed          int64 // global.seed, or the scenario's seed parameter
+	Deterministic bool  // execution.deterministic
+	ResetTable    bool  // execution.cleanup.reset_databases: recreate tables rather than truncate them
+	Verify        bool  // execution.verify_data: track what is written for WorkloadVerifier
+}
+
+// WorkloadFactory builds a workload from a scenario's parameters. It is called once per
+// storage type, so each storage type gets fresh workload state.
+type WorkloadFactory func(scenario config.ScenarioConfig, opts WorkloadOptions) (Workload, error)
+
+type workloadSpec struct {
+	factory    WorkloadFactory
+	countBound bool // runs until every thread gets ErrWorkloadDone instead of for the duration
+}
+
+// workloads maps scenario names to their registered workloads
+var workloads = make(map[string]workloadSpec)
+
+// RegisterWorkload makes a workload available under a scenario name. A count-bound
+// workload runs until it returns ErrWorkloadDone on every thread, ignoring the scenario
+// duration; otherwise it runs for the duration and may be interleaved. It panics if the
+// name is already registered.
+func RegisterWorkload(name string, countBound bool, factory WorkloadFactory) {
+	if _, ok := workloads[name]; ok {
+		panic(fmt.Sprintf("workload %q registered twice", name))
+	}
+	workloads[name] = workloadSpec{factory: factory, countBound: countBound}
+}
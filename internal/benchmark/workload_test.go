@@ -0,0 +1,43 @@
+package benchmark
+
+import "testing"
+
+func TestDurabilityCheck(t *testing.T) {
+	var d DurabilityCheck
+	d.add(5, nil)
+	if d.SampledRows != 5 || d.MissingRows != 0 || d.Err() != nil {
+		t.Errorf("Expected 5 sampled rows and none missing, got %+v, %v", d, d.Err())
+	}
+	d.add(5, []int64{3, 4})
+	if d.SampledRows != 10 || d.MissingRows != 2 || d.Err() == nil {
+		t.Errorf("Expected 2 of 10 sampled rows missing, got %+v, %v", d, d.Err())
+	}
+
+	other := DurabilityCheck{SampledRows: 4, MissingRows: 1, MissingIDs: []int64{9}, CheckErrors: 2}
+	d.Merge(&other)
+	if d.SampledRows != 14 || d.MissingRows != 3 || d.CheckErrors != 2 {
+		t.Errorf("Expected merged counts, got %+v", d)
+	}
+	if len(d.MissingIDs) != 3 || d.MissingIDs[0] != 3 || d.MissingIDs[2] != 9 {
+		t.Errorf("Expected missing ids 3, 4, 9, got %v", d.MissingIDs)
+	}
+}
+
+func TestDurabilityCheckCapsMissingIDs(t *testing.T) {
+	missing := make([]int64, maxReportedMissingIDs-1)
+	for i := range missing {
+		missing[i] = int64(i)
+	}
+	var d DurabilityCheck
+	d.add(len(missing), missing)
+	d.add(3, []int64{1000, 1001, 1002})
+	d.Merge(&DurabilityCheck{SampledRows: 1, MissingRows: 1, MissingIDs: []int64{2000}})
+
+	// Every missing row is counted, but only the first ids are listed
+	if d.MissingRows != maxReportedMissingIDs+3 {
+		t.Errorf("Expected %d missing rows, got %d", maxReportedMissingIDs+3, d.MissingRows)
+	}
+	if len(d.MissingIDs) != maxReportedMissingIDs || d.MissingIDs[maxReportedMissingIDs-1] != 1000 {
+		t.Errorf("Expected the first %d missing ids, ending with 1000, got %d ending with %d", maxReportedMissingIDs, len(d.MissingIDs), d.MissingIDs[len(d.MissingIDs)-1])
+	}
+}
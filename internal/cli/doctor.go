@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/l22io/nfsvsdirectbench/internal/benchmark"
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the environment is ready to benchmark",
+	Long: `Check the runtime environment before a long benchmark run: the
+configuration loads, every enabled database is reachable and can create
+tables, NFS data directories are really mounted over NFS, and cache
+clearing works when it is configured.
+
+Exits non-zero if any check fails.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		color := benchmark.IsTerminal(os.Stdout)
+
+		configCheck := benchmark.Check{Name: "configuration loads"}
+		if files := configFilesUsed(); files != "" {
+			configCheck.Name = fmt.Sprintf("configuration loads (%s)", files)
+		}
+		if err := readConfig(); err != nil {
+			configCheck.Err = err
+		}
+		cfg, err := config.Load()
+		if configCheck.Err == nil {
+			configCheck.Err = err
+		}
+		if configCheck.Err == nil {
+			configCheck.Err = setupLogging(cfg)
+		}
+		printCheck(configCheck, color)
+		if configCheck.Err != nil {
+			return fmt.Errorf("configuration could not be loaded")
+		}
+
+		failed := 0
+		for _, check := range benchmark.Diagnose(context.Background(), cfg) {
+			printCheck(check, color)
+			if check.Err != nil {
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d check(s) failed", failed)
+		}
+		fmt.Println("\nAll checks passed")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// printCheck prints one checklist line, colored when writing to a terminal
+func printCheck(check benchmark.Check, color bool) {
+	mark, code := "✓", "32" // green
+	switch {
+	case check.Err != nil:
+		mark, code = "✗", "31" // red
+	case check.Skipped != "":
+		mark, code = "-", "33" // yellow
+	}
+	if color {
+		mark = "\033[" + code + "m" + mark + "\033[0m"
+	}
+
+	switch {
+	case check.Err != nil:
+		fmt.Printf("%s %s\n    %v\n", mark, check.Name, check.Err)
+	case check.Skipped != "":
+		fmt.Printf("%s %s (skipped: %s)\n", mark, check.Name, check.Skipped)
+	default:
+		fmt.Printf("%s %s\n", mark, check.Name)
+	}
+}
@@ -0,0 +1,33 @@
+package cli
+
+import "errors"
+
+// Exit codes of nfsbench, so scripts and CI can tell why a command failed
+const (
+	ExitOK               = 0 // every scenario run succeeded
+	ExitError            = 1 // invalid configuration, setup failure, or any other error
+	ExitScenarioFailures = 2 // the run completed but some scenario runs failed
+	ExitRegression       = 3 // the run completed but NFS overhead regressed against --baseline
+)
+
+// exitError is an error that maps to a specific exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var ee *exitError
+	if errors.As(err, &ee) {
+		return ee.code
+	}
+	return ExitError
+}
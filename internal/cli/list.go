@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+)
+
+var (
+	listDatabases bool
+	listScenarios bool
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured databases and scenarios",
+	Long: `List the databases and scenarios defined in the loaded configuration,
+including whether each is enabled. Use the names shown here with the
+-d/--databases and -s/--scenarios filters of the run command.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+
+		// Without a subselector, list everything
+		showAll := !listDatabases && !listScenarios
+
+		if showAll || listDatabases {
+			printDatabases(cfg)
+		}
+		if showAll {
+			fmt.Println()
+		}
+		if showAll || listScenarios {
+			printScenarios(cfg)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().BoolVar(&listDatabases, "databases", false, "List only databases")
+	listCmd.Flags().BoolVar(&listScenarios, "scenarios", false, "List only scenarios")
+}
+
+func enabledLabel(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func printDatabases(cfg *config.Config) {
+	fmt.Println("Databases:")
+
+	names := make([]string, 0, len(cfg.Databases))
+	for name := range cfg.Databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		db := cfg.Databases[name]
+		fmt.Printf("  %-12s [%s]\n", name, enabledLabel(db.Enabled))
+		fmt.Printf("    direct: %s\n", describeConnection(db.Direct))
+		fmt.Printf("    nfs:    %s\n", describeConnection(db.NFS))
+	}
+}
+
+func describeConnection(conn config.DatabaseConnectionConfig) string {
+	if conn.Path != "" {
+		return conn.Path
+	}
+	if conn.DSN != "" {
+		return "dsn " + config.RedactDSN(conn.DSN)
+	}
+	if conn.Host == "" {
+		return "(not configured)"
+	}
+	return fmt.Sprintf("%s:%d/%s", conn.Host, conn.Port, conn.Database)
+}
+
+func printScenarios(cfg *config.Config) {
+	fmt.Println("Scenarios:")
+
+	for _, scenario := range cfg.Scenarios {
+		fmt.Printf("  %-22s [%s] %s\n", scenario.Name, enabledLabel(scenario.Enabled), scenario.Description)
+		fmt.Printf("    Duration: %ds\n", scenario.Duration)
+
+		keys := make([]string, 0, len(scenario.Parameters))
+		for key := range scenario.Parameters {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Printf("    %s: %v\n", key, scenario.Parameters[key])
+		}
+	}
+}
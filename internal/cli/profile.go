@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// profiling holds the files of the run command's --cpuprofile, --memprofile, and --trace
+// flags, which profile the benchmark tool itself rather than the database
+type profiling struct {
+	cpuPath   string
+	memPath   string
+	tracePath string
+
+	cpuFile   *os.File
+	traceFile *os.File
+}
+
+// start begins CPU profiling and tracing, if requested. On error, anything already
+// started is stopped.
+func (p *profiling) start() error {
+	if p.cpuPath != "" {
+		f, err := os.Create(p.cpuPath)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		p.cpuFile = f
+	}
+
+	if p.tracePath != "" {
+		f, err := os.Create(p.tracePath)
+		if err != nil {
+			p.stop()
+			return fmt.Errorf("failed to create trace: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			p.stop()
+			return fmt.Errorf("failed to start trace: %w", err)
+		}
+		p.traceFile = f
+	}
+	return nil
+}
+
+// stop ends CPU profiling and tracing and writes the heap profile, if requested. Failures
+// are logged, since the benchmark results matter more than the profiles.
+func (p *profiling) stop() {
+	if p.cpuFile != nil {
+		pprof.StopCPUProfile()
+		closeProfile(p.cpuFile, "CPU profile")
+		p.cpuFile = nil
+	}
+	if p.traceFile != nil {
+		trace.Stop()
+		closeProfile(p.traceFile, "trace")
+		p.traceFile = nil
+	}
+
+	if p.memPath != "" {
+		f, err := os.Create(p.memPath)
+		if err != nil {
+			slog.Error("Failed to create memory profile", "error", err)
+			return
+		}
+		// Collect garbage first so the profile shows live allocations
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			slog.Error("Failed to write memory profile", "error", err)
+		}
+		closeProfile(f, "memory profile")
+	}
+}
+
+func closeProfile(f *os.File, kind string) {
+	if err := f.Close(); err != nil {
+		slog.Error("Failed to write "+kind, "path", f.Name(), "error", err)
+		return
+	}
+	slog.Info("Wrote "+kind, "path", f.Name())
+}
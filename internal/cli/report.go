@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/l22io/nfsvsdirectbench/internal/report"
+)
+
+var reportFormat string
+
+var reportCmd = &cobra.Command{
+	Use:   "report <results.json>",
+	Short: "Print a summary of a results file",
+	Long: `Print a direct vs NFS comparison table for a results file written by
+the run command, including throughput, latency percentiles, sizes, and the
+NFS overhead for each metric.
+
+Use --format markdown to produce a table that can be pasted into an issue,
+or --format json for a versioned, machine-readable document with absolute
+values, overhead percentages, and a better/worse/similar verdict per metric.
+--output (-o) is an alias for --format.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		comparison, err := report.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		switch reportFormat {
+		case "text":
+			return comparison.WriteText(os.Stdout)
+		case "markdown":
+			return comparison.WriteMarkdown(os.Stdout)
+		case "json":
+			return comparison.WriteJSON(os.Stdout)
+		default:
+			return fmt.Errorf("unknown output format %q (valid: text, markdown, json)", reportFormat)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVar(&reportFormat, "format", "text", "Output format: text, markdown, json")
+	reportCmd.Flags().StringVarP(&reportFormat, "output", "o", "text", "Alias for --format")
+	reportCmd.MarkFlagsMutuallyExclusive("format", "output")
+}
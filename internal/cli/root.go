@@ -2,15 +2,22 @@ package cli
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/logging"
 )
 
 var (
-	cfgFile string
-	verbose bool
+	cfgFiles  []string
+	verbose   bool
+	quiet     bool
+	logFormat string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -22,6 +29,8 @@ in database workloads compared to direct block storage access.
 
 This tool measures the performance difference between database storage 
 on NFS mounts versus direct block storage across PostgreSQL, MySQL, and SQLite.`,
+	// main prints the error and picks the exit code
+	SilenceErrors: true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -32,25 +41,73 @@ func Execute() error {
 func init() {
 	cobra.OnInitialize(initConfig)
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is config/default.yaml)")
+	rootCmd.PersistentFlags().StringSliceVar(&cfgFiles, "config", nil, "config file; repeat or comma-separate to merge overlays onto the first, in order (default is config/default.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only log warnings and errors, and skip progress updates")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "log format: text, json (default from global.log_format, else text)")
 	
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 }
 
-// initConfig reads in config file and ENV variables if set.
+// setupLogging configures the default logger from global.log_level and global.log_format,
+// with --verbose forcing debug level, --quiet forcing warn level, and --log-format
+// overriding the configured format
+func setupLogging(cfg *config.Config) error {
+	if verbose && quiet {
+		return fmt.Errorf("--verbose and --quiet cannot be combined")
+	}
+	level, err := logging.ParseLevel(cfg.Global.LogLevel)
+	if err != nil {
+		return err
+	}
+	if verbose {
+		level = slog.LevelDebug
+	}
+	if quiet {
+		level = slog.LevelWarn
+	}
+	if logFormat != "" {
+		cfg.Global.LogFormat = logFormat
+	}
+	return logging.Setup(os.Stderr, level, cfg.Global.LogFormat)
+}
+
+// initConfig reads in config file and ENV variables if set. A --config file that cannot
+// be read is fatal, so a mistyped overlay never silently runs on the base config; without
+// --config, a missing config/default.yaml leaves the built-in defaults.
 func initConfig() {
-	if cfgFile != "" {
-		viper.SetConfigFile(cfgFile)
-	} else {
-		viper.AddConfigPath("config")
-		viper.SetConfigName("default")
-		viper.SetConfigType("yaml")
+	if err := config.BindEnv(); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to bind environment variables:", err)
+	}
+
+	err := readConfig()
+	switch {
+	case err != nil && len(cfgFiles) > 0:
+		fmt.Fprintf(os.Stderr, "Error: failed to read config: %v\n", err)
+		os.Exit(ExitError)
+	case err != nil && verbose:
+		fmt.Fprintln(os.Stderr, "No config file read, using defaults:", err)
+	case verbose:
+		fmt.Fprintln(os.Stderr, "Using config file:", configFilesUsed())
 	}
+}
 
-	viper.AutomaticEnv()
+// readConfig reads the --config files, merging any overlays in order, or
+// config/default.yaml when none are given
+func readConfig() error {
+	if len(cfgFiles) > 0 {
+		return config.ReadFiles(cfgFiles)
+	}
+	viper.AddConfigPath("config")
+	viper.SetConfigName("default")
+	viper.SetConfigType("yaml")
+	return viper.ReadInConfig()
+}
 
-	if err := viper.ReadInConfig(); err == nil && verbose {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+// configFilesUsed names the config files read, base first
+func configFilesUsed() string {
+	if len(cfgFiles) > 0 {
+		return strings.Join(cfgFiles, ", ")
 	}
+	return viper.ConfigFileUsed()
 }
@@ -3,14 +3,19 @@ package cli
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
 	"github.com/l22io/nfsvsdirectbench/internal/benchmark"
 	"github.com/l22io/nfsvsdirectbench/internal/config"
+	"github.com/l22io/nfsvsdirectbench/internal/report"
 )
 
 var (
@@ -19,7 +24,23 @@ var (
 	storageTypes []string
 	nfsVersions  []string
 	dryRun       bool
+	checkConns   bool
 	outputDir    string
+	resumeDir    string
+	resultsDB    string
+	metricsAddr  string
+	noMountCheck bool
+	duration     time.Duration
+	repeat       int
+	paramSets    []string
+
+	allowFailures bool
+
+	baselinePath      string
+	baselineTolerance float64
+	saveBaselinePath  string
+
+	profile profiling
 )
 
 var runCmd = &cobra.Command{
@@ -31,10 +52,16 @@ This command orchestrates the benchmark execution across all enabled
 databases and scenarios, comparing NFS storage performance against 
 direct block storage.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// Flags parsed fine; later errors are about the run, not its usage
+		cmd.SilenceUsage = true
+
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load configuration: %w", err)
 		}
+		if err := setupLogging(cfg); err != nil {
+			return err
+		}
 
 		// Override config with CLI flags
 		if len(databases) > 0 {
@@ -46,9 +73,60 @@ direct block storage.`,
 		if outputDir != "" {
 			cfg.Global.OutputDir = outputDir
 		}
+		if resultsDB != "" {
+			cfg.Reporting.ResultsDB = resultsDB
+		}
+		if metricsAddr != "" {
+			cfg.Reporting.MetricsAddr = metricsAddr
+		}
+		if quiet {
+			// Progress lines are per-run chatter like the info logs --quiet hides
+			cfg.Reporting.CLI.RealTimeUpdates = false
+		}
+		if len(nfsVersions) > 0 {
+			cfg.NFS.Versions = nfsVersions
+		}
+		if noMountCheck {
+			cfg.NFS.SkipMountCheck = true
+		}
+		if cmd.Flags().Changed("duration") {
+			if err := cfg.SetScenarioDuration(duration); err != nil {
+				return err
+			}
+		}
+		if cmd.Flags().Changed("repeat") {
+			if repeat < 1 {
+				return fmt.Errorf("--repeat must be at least 1, got %d", repeat)
+			}
+			cfg.Execution.RepeatCount = repeat
+		}
+		for _, spec := range paramSets {
+			value, err := cfg.SetScenarioParam(spec)
+			if err != nil {
+				return err
+			}
+			slog.Info("Overriding scenario parameter", "set", spec, "value", value)
+		}
+		if cmd.Flags().Changed("storage-types") {
+			if err := cfg.SetStorageTypes(storageTypes); err != nil {
+				return err
+			}
+		}
 
+		if resumeDir != "" && cmd.Flags().Changed("output") {
+			return fmt.Errorf("--resume continues in the run's own directory and cannot be combined with --output")
+		}
+		if checkConns && !dryRun {
+			return fmt.Errorf("--check-connections requires --dry-run")
+		}
 		if dryRun {
-			return showExecutionPlan(cfg)
+			if err := showExecutionPlan(cfg); err != nil {
+				return err
+			}
+			if checkConns {
+				return checkConnections(cfg)
+			}
+			return nil
 		}
 
 		return runBenchmark(cfg)
@@ -65,11 +143,41 @@ func init() {
 	runCmd.Flags().StringSliceVar(&storageTypes, "storage-types", []string{"direct", "nfs"},
 		"Storage types to benchmark")
 	runCmd.Flags().StringSliceVar(&nfsVersions, "nfs-versions", nil,
-		"NFS versions to test (v3,v4)")
+		"NFS versions to test (e.g. v3,v4.1), overriding nfs.versions")
+	runCmd.Flags().DurationVar(&duration, "duration", 0,
+		"Run every enabled scenario for this long, overriding the configured durations (e.g. 10s)")
+	runCmd.Flags().IntVar(&repeat, "repeat", 0,
+		"Run each scenario this many times, overriding execution.repeat_count")
+	runCmd.Flags().StringArrayVar(&paramSets, "set", nil,
+		"Override a scenario parameter as scenario.param=value (repeatable), e.g. heavy_inserts.threads=32")
 	runCmd.Flags().BoolVar(&dryRun, "dry-run", false,
 		"Show execution plan without running benchmarks")
+	runCmd.Flags().BoolVar(&checkConns, "check-connections", false,
+		"With --dry-run, also connect to every database endpoint in the plan and report reachability")
 	runCmd.Flags().StringVarP(&outputDir, "output", "o", "",
 		"Output directory for results")
+	runCmd.Flags().StringVar(&resumeDir, "resume", "",
+		"Continue an interrupted run in its output directory (e.g. results/run_20240101_120000), skipping completed tasks")
+	runCmd.Flags().StringVar(&resultsDB, "results-db", "",
+		"Also append results to this SQLite file, overriding reporting.results_db")
+	runCmd.Flags().StringVar(&metricsAddr, "metrics-addr", "",
+		"Serve live Prometheus metrics at this address while running (e.g. :9090), overriding reporting.metrics_addr")
+	runCmd.Flags().BoolVar(&noMountCheck, "no-mount-check", false,
+		"Skip verifying that the NFS data directory is on an NFS mount")
+	runCmd.Flags().BoolVar(&allowFailures, "allow-failures", false,
+		"Exit successfully even if some scenario runs failed")
+	runCmd.Flags().StringVar(&baselinePath, "baseline", "",
+		"Compare NFS overhead against a baseline file and fail on regressions")
+	runCmd.Flags().Float64Var(&baselineTolerance, "baseline-tolerance", report.DefaultBaselineTolerance,
+		"Percentage points NFS overhead may grow over the baseline before failing")
+	runCmd.Flags().StringVar(&saveBaselinePath, "save-baseline", "",
+		"Write this run's comparisons to a baseline file")
+	runCmd.Flags().StringVar(&profile.cpuPath, "cpuprofile", "",
+		"Write a CPU profile of the benchmark tool to this file, for go tool pprof")
+	runCmd.Flags().StringVar(&profile.memPath, "memprofile", "",
+		"Write a heap profile of the benchmark tool to this file when the run finishes")
+	runCmd.Flags().StringVar(&profile.tracePath, "trace", "",
+		"Write an execution trace of the benchmark tool to this file, for go tool trace")
 }
 
 func showExecutionPlan(cfg *config.Config) error {
@@ -90,6 +198,9 @@ func showExecutionPlan(cfg *config.Config) error {
 	}
 	fmt.Println()
 	
+	fmt.Printf("Storage Types: %s\n", strings.Join(cfg.Execution.StorageTypes, ", "))
+	fmt.Println()
+	
 	fmt.Println("NFS Configurations:")
 	for _, version := range cfg.NFS.Versions {
 		fmt.Printf("  - NFS %s\n", version)
@@ -100,32 +211,239 @@ func showExecutionPlan(cfg *config.Config) error {
 	fmt.Println()
 	
 	fmt.Printf("Output Directory: %s\n", cfg.Global.OutputDir)
+	fmt.Println()
+
+	estimate := benchmark.EstimateRuntime(cfg)
+	fmt.Printf("Estimated Runtime: %s\n", estimate.Total)
+	fmt.Printf("  %d tasks, %d runs including repeats\n", estimate.Tasks, estimate.Runs)
+	if estimate.Workers > 1 {
+		fmt.Printf("  %d database groups at a time; %s if run serially\n", estimate.Workers, estimate.Serial)
+	}
+	fmt.Println("  (scenario duration per run; setup and cleanup time not included)")
 	
 	return nil
 }
 
+// checkConnections connects to each database endpoint the plan would use, printing a
+// line per endpoint, and fails if any is unreachable
+func checkConnections(cfg *config.Config) error {
+	fmt.Println()
+	fmt.Println("Connectivity:")
+	color := benchmark.IsTerminal(os.Stdout)
+	failed := 0
+	for _, check := range benchmark.CheckConnections(context.Background(), cfg) {
+		printCheck(check, color)
+		if check.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d database endpoint(s) unreachable", failed)
+	}
+	return nil
+}
+
 func runBenchmark(cfg *config.Config) error {
-	ctx := context.Background()
+	// Interrupting cancels the run, including a long prepopulation, instead of killing
+	// the process with its connections open
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	
-	if viper.GetBool("verbose") {
-		log.Printf("Starting benchmark with config: %+v", cfg)
-	}
+	slog.Debug("Starting benchmark", "config", fmt.Sprintf("%+v", cfg))
 	
 	runner := benchmark.NewRunner(cfg)
+	if resumeDir != "" {
+		runner.ResumeFrom(resumeDir)
+	}
 	
+	if err := profile.start(); err != nil {
+		return err
+	}
 	results, err := runner.RunAll(ctx)
+	profile.stop()
 	if err != nil {
 		return fmt.Errorf("benchmark failed: %w", err)
 	}
 	
-	fmt.Printf("Benchmark completed successfully\n")
+	failed := results.Failed()
+	if len(failed) == 0 {
+		fmt.Printf("Benchmark completed successfully\n")
+	} else {
+		fmt.Printf("Benchmark completed with failures\n")
+	}
 	fmt.Printf("Results saved to: %s\n", results.OutputDir)
 	
 	// Print summary
 	fmt.Println("\nSummary:")
 	fmt.Printf("- Databases tested: %s\n", strings.Join(cfg.GetEnabledDatabases(), ", "))
 	fmt.Printf("- Scenarios executed: %d\n", len(cfg.GetEnabledScenarios()))
+	fmt.Printf("- Scenario runs: %d succeeded, %d failed\n", len(results.ScenarioResults)-len(failed), len(failed))
 	fmt.Printf("- Total runtime: %s\n", results.TotalDuration.String())
-	
+
+	printVerdicts(cfg, results.OutputDir)
+	printFailureSummary(results, failed)
+	printErrorSummary(results)
+	printPoolWaitSummary(results)
+
+	if err := checkBaseline(results.OutputDir); err != nil {
+		return err
+	}
+
+	if len(failed) > 0 && !allowFailures {
+		return &exitError{
+			code: ExitScenarioFailures,
+			err:  fmt.Errorf("%d of %d scenario runs failed (use --allow-failures to ignore)", len(failed), len(results.ScenarioResults)),
+		}
+	}
 	return nil
 }
+
+// printVerdicts prints a one-sentence takeaway for every direct vs NFS comparison of the
+// run whose results both succeeded, hedged with a significance test when
+// reporting.comparison.statistical_analysis is set
+func printVerdicts(cfg *config.Config, outputDir string) {
+	comparisons, err := report.LoadDir(outputDir)
+	if err != nil {
+		slog.Warn("Failed to load results for verdicts", "error", err)
+		return
+	}
+
+	var verdicts []string
+	for _, c := range comparisons {
+		if !c.Direct.Success || !c.NFS.Success {
+			continue
+		}
+		var significance *report.Significance
+		if comparison := cfg.Reporting.Comparison; comparison.StatisticalAnalysis {
+			significance = c.LatencySignificance(comparison.SignificanceThreshold, comparison.MinimumSamples)
+		}
+		verdicts = append(verdicts, c.Verdict(significance))
+	}
+	if len(verdicts) == 0 {
+		return
+	}
+
+	fmt.Println("\nVerdict:")
+	for _, v := range verdicts {
+		fmt.Printf("- %s\n", v)
+	}
+}
+
+// printFailureSummary lists every scenario run that failed with its error
+func printFailureSummary(results *benchmark.Results, failed []string) {
+	if len(failed) == 0 {
+		return
+	}
+
+	fmt.Println("\nFailed:")
+	for _, key := range failed {
+		if err := results.ScenarioResults[key].Error; err != nil {
+			fmt.Printf("- %s: %v\n", key, err)
+		} else {
+			fmt.Printf("- %s\n", key)
+		}
+	}
+}
+
+// checkBaseline saves and/or checks the run's comparisons as requested by --save-baseline
+// and --baseline, returning an error if any metric regressed
+func checkBaseline(outputDir string) error {
+	if baselinePath == "" && saveBaselinePath == "" {
+		return nil
+	}
+
+	comparisons, err := report.LoadDir(outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to load results for baseline: %w", err)
+	}
+
+	if saveBaselinePath != "" {
+		if err := report.NewBaseline(comparisons).Save(saveBaselinePath); err != nil {
+			return fmt.Errorf("failed to save baseline: %w", err)
+		}
+		fmt.Printf("Baseline saved to: %s\n", saveBaselinePath)
+	}
+
+	if baselinePath == "" {
+		return nil
+	}
+	baseline, err := report.LoadBaseline(baselinePath)
+	if err != nil {
+		return err
+	}
+
+	result := baseline.Check(comparisons, baselineTolerance)
+	if len(result.Unmatched) > 0 {
+		fmt.Printf("\nNot in baseline %s, so not checked:\n", baselinePath)
+		for _, name := range result.Unmatched {
+			fmt.Printf("- %s\n", name)
+		}
+	}
+	if result.Checked == 0 {
+		return fmt.Errorf("no results of this run matched baseline %s, so nothing was checked", baselinePath)
+	}
+	if len(result.Regressions) == 0 {
+		fmt.Printf("\nNo regressions against baseline %s (%d comparisons checked, tolerance %.1f points)\n", baselinePath, result.Checked, baselineTolerance)
+		return nil
+	}
+
+	fmt.Printf("\nRegressions against baseline %s (tolerance %.1f points):\n", baselinePath, baselineTolerance)
+	for _, reg := range result.Regressions {
+		name := reg.Database + "/" + reg.Scenario
+		if reg.MountOption != "" {
+			name += "/" + reg.MountOption
+		}
+		fmt.Printf("- %s %s: NFS overhead %+.1f%% (baseline %+.1f%%)\n",
+			name, reg.Metric, reg.CurrentOverhead, reg.BaselineOverhead)
+	}
+	return &exitError{
+		code: ExitRegression,
+		err:  fmt.Errorf("%d metric(s) regressed against baseline", len(result.Regressions)),
+	}
+}
+
+// printPoolWaitSummary lists every scenario run whose batches had to wait for a pooled
+// connection, so pool starvation can be told apart from slow storage
+func printPoolWaitSummary(results *benchmark.Results) {
+	keys := make([]string, 0, len(results.ScenarioResults))
+	for key, result := range results.ScenarioResults {
+		if count, _ := result.DBStats["wait_count"].(int64); count > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+
+	fmt.Println("\nConnection pool waits (raise pool.max_open if these are large):")
+	for _, key := range keys {
+		stats := results.ScenarioResults[key].DBStats
+		count := stats["wait_count"].(int64)
+		total, _ := stats["wait_duration_ms"].(float64)
+		fmt.Printf("- %s: %d waits, %.1f ms total (%.2f ms avg)\n", key, count, total, total/float64(count))
+	}
+}
+
+// printErrorSummary lists the error rate and most common errors of every scenario run that had any
+func printErrorSummary(results *benchmark.Results) {
+	keys := make([]string, 0, len(results.ScenarioResults))
+	for key, result := range results.ScenarioResults {
+		if result.Metrics != nil && result.Metrics.ErrorCount > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+
+	fmt.Println("\nErrors:")
+	for _, key := range keys {
+		m := results.ScenarioResults[key].Metrics
+		fmt.Printf("- %s: %d errors (%.2f%% of attempts)\n", key, m.ErrorCount, m.ErrorRate*100)
+		for _, e := range m.TopErrors {
+			fmt.Printf("    %6dx %s\n", e.Count, e.Message)
+		}
+	}
+}
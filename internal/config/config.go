@@ -2,8 +2,13 @@ package config
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
@@ -23,9 +28,15 @@ type GlobalConfig struct {
 	OutputDir       string `mapstructure:"output_dir"`
 	TimestampFormat string `mapstructure:"timestamp_format"`
 	LogLevel        string `mapstructure:"log_level"`
+	LogFormat       string `mapstructure:"log_format"` // "text" (default) or "json"
 	MaxWorkers      int    `mapstructure:"max_workers"`
+	Seed            int64  `mapstructure:"seed"` // seed for generated record content; defaults to DefaultSeed
 }
 
+// DefaultSeed is the record generator seed used when global.seed is not set, so runs
+// produce identical record content unless configured otherwise
+const DefaultSeed int64 = 1
+
 // DatabaseConfig contains database connection settings
 type DatabaseConfig struct {
 	Enabled bool                      `mapstructure:"enabled"`
@@ -41,18 +52,129 @@ type DatabaseConnectionConfig struct {
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
 	Path     string `mapstructure:"path"` // For SQLite
+	DSN      string `mapstructure:"dsn"`  // PostgreSQL connection string used instead of the fields above and the TLS settings; connect_timeout and ssl_mode fill in what it leaves unset
+	Table    string `mapstructure:"table"` // benchmark table, optionally schema-qualified; {run_id} expands per run, empty uses benchmark_data
+	Pooler         string `mapstructure:"pooler"`           // "pgbouncer" when connecting through PgBouncer rather than to PostgreSQL
+	PoolerAdminDSN string `mapstructure:"pooler_admin_dsn"` // PgBouncer admin console, queried with SHOW STATS for pooler waits
+	Pool     PoolConfig `mapstructure:"pool"`
+	ConnectTimeout int  `mapstructure:"connect_timeout"` // seconds; 0 uses the default of 10s
+	ConnectRetries int  `mapstructure:"connect_retries"` // extra connection attempts after a failure
+	ConnectBackoff int  `mapstructure:"connect_backoff"` // seconds before the first retry, doubling each time
+	SessionSettings map[string]string `mapstructure:"-"` // SET on every new connection; the runner fills it from the scenario
+
+	// TLS settings (PostgreSQL); SSLMode defaults to "disable"
+	SSLMode     string `mapstructure:"ssl_mode"`
+	SSLRootCert string `mapstructure:"ssl_root_cert"`
+	SSLCert     string `mapstructure:"ssl_cert"`
+	SSLKey      string `mapstructure:"ssl_key"`
+}
+
+// GetConnectTimeout returns the connect timeout as a duration
+func (d *DatabaseConnectionConfig) GetConnectTimeout() time.Duration {
+	return time.Duration(d.ConnectTimeout) * time.Second
+}
+
+// GetConnectBackoff returns the initial retry backoff as a duration
+func (d *DatabaseConnectionConfig) GetConnectBackoff() time.Duration {
+	return time.Duration(d.ConnectBackoff) * time.Second
+}
+
+// PoolConfig contains connection pool sizing. Zero values use the defaults: max_open the
+// larger of 25 and the scenario's threads, max_idle 5, and conn_max_lifetime 5 minutes.
+type PoolConfig struct {
+	MaxOpen         int `mapstructure:"max_open"`
+	MaxIdle         int `mapstructure:"max_idle"`
+	ConnMaxLifetime int `mapstructure:"conn_max_lifetime"` // seconds
 }
 
 // NFSConfig contains NFS testing parameters
 type NFSConfig struct {
-	Versions     []string           `mapstructure:"versions"`
-	MountOptions []NFSMountOption   `mapstructure:"mount_options"`
+	Versions       []string         `mapstructure:"versions"`
+	MountOptions   []NFSMountOption `mapstructure:"mount_options"`
+	SkipMountCheck bool             `mapstructure:"skip_mount_check"` // don't verify the NFS data directory before running
+	Path           string           `mapstructure:"path"`             // NFS directory for filesystem scenarios
+	DirectPath     string           `mapstructure:"direct_path"`      // local directory for filesystem scenarios
 }
 
-// NFSMountOption represents NFS mount configuration
+// NFSMountOption represents NFS mount configuration. The runner cannot remount, so a
+// variant is benchmarked only where it is mapped to something already mounted with its
+// options: Path for filesystem scenarios, and Connections (keyed by database name) for a
+// database server whose data directory is on such a mount.
 type NFSMountOption struct {
-	Name    string `mapstructure:"name"`
-	Options string `mapstructure:"options"`
+	Name        string                              `mapstructure:"name"`
+	Version     string                              `mapstructure:"version"` // NFS version of the mount, e.g. "v4.1"; names the variant if name is unset
+	Options     string                              `mapstructure:"options"`
+	Path        string                              `mapstructure:"path"`
+	Connections map[string]DatabaseConnectionConfig `mapstructure:"connections"`
+}
+
+// Connection returns the connection for this variant of a database's NFS storage: the
+// database's NFS connection with every field set on the variant overriding it. It
+// returns false if the variant has no connection for the database.
+func (o NFSMountOption) Connection(database string, nfs DatabaseConnectionConfig) (DatabaseConnectionConfig, bool) {
+	variant, ok := o.Connections[database]
+	if !ok {
+		return DatabaseConnectionConfig{}, false
+	}
+	conn := nfs
+	if variant.Host != "" {
+		conn.Host = variant.Host
+	}
+	if variant.Port != 0 {
+		conn.Port = variant.Port
+	}
+	if variant.Database != "" {
+		conn.Database = variant.Database
+	}
+	if variant.Username != "" {
+		conn.Username = variant.Username
+	}
+	if variant.Password != "" {
+		conn.Password = variant.Password
+	}
+	if variant.DSN != "" {
+		conn.DSN = variant.DSN
+	}
+	if variant.Table != "" {
+		conn.Table = variant.Table
+	}
+	if variant.Pooler != "" {
+		conn.Pooler = variant.Pooler
+	}
+	if variant.PoolerAdminDSN != "" {
+		conn.PoolerAdminDSN = variant.PoolerAdminDSN
+	}
+	if variant.Pool.MaxOpen != 0 {
+		conn.Pool.MaxOpen = variant.Pool.MaxOpen
+	}
+	if variant.Pool.MaxIdle != 0 {
+		conn.Pool.MaxIdle = variant.Pool.MaxIdle
+	}
+	if variant.Pool.ConnMaxLifetime != 0 {
+		conn.Pool.ConnMaxLifetime = variant.Pool.ConnMaxLifetime
+	}
+	if variant.ConnectTimeout != 0 {
+		conn.ConnectTimeout = variant.ConnectTimeout
+	}
+	if variant.ConnectRetries != 0 {
+		conn.ConnectRetries = variant.ConnectRetries
+	}
+	if variant.ConnectBackoff != 0 {
+		conn.ConnectBackoff = variant.ConnectBackoff
+	}
+	if variant.SSLMode != "" {
+		conn.SSLMode = variant.SSLMode
+	}
+	if variant.SSLRootCert != "" {
+		conn.SSLRootCert = variant.SSLRootCert
+	}
+	if variant.SSLCert != "" {
+		conn.SSLCert = variant.SSLCert
+	}
+	if variant.SSLKey != "" {
+		conn.SSLKey = variant.SSLKey
+	}
+	return conn, true
 }
 
 // ScenarioConfig defines a benchmark scenario
@@ -61,7 +183,56 @@ type ScenarioConfig struct {
 	Description string                 `mapstructure:"description"`
 	Enabled     bool                   `mapstructure:"enabled"`
 	Duration    int                    `mapstructure:"duration"` // seconds
+	MaxRuntime  int                    `mapstructure:"max_scenario_runtime"` // seconds for setup plus measurement; 0 means no limit
+	Workload    string                 `mapstructure:"workload"`             // registered workload to run; defaults to the name
 	Parameters  map[string]interface{} `mapstructure:"parameters"`
+	SessionSettings map[string]interface{} `mapstructure:"session_settings"` // PostgreSQL settings SET on every connection
+}
+
+// GetMaxRuntime returns the limit on one run of the scenario, or 0 if there is none
+func (s ScenarioConfig) GetMaxRuntime() time.Duration {
+	return time.Duration(s.MaxRuntime) * time.Second
+}
+
+// WorkloadName returns the workload the scenario runs: its workload setting, so several
+// scenarios can run one workload with different settings, or else its name
+func (s ScenarioConfig) WorkloadName() string {
+	if s.Workload != "" {
+		return s.Workload
+	}
+	return s.Name
+}
+
+// sessionSettingName matches the PostgreSQL setting names session_settings may SET,
+// including dotted extension settings
+var sessionSettingName = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+// GetSessionSettings returns the scenario's session settings with their values as the
+// strings SET is given, or nil if it has none
+func (s ScenarioConfig) GetSessionSettings() map[string]string {
+	if len(s.SessionSettings) == 0 {
+		return nil
+	}
+	settings := make(map[string]string, len(s.SessionSettings))
+	for name, value := range s.SessionSettings {
+		settings[name] = fmt.Sprintf("%v", value)
+	}
+	return settings
+}
+
+// validateSessionSettings checks that every session setting names a setting and has a value
+func (s ScenarioConfig) validateSessionSettings() error {
+	for name, value := range s.SessionSettings {
+		if !sessionSettingName.MatchString(name) {
+			return fmt.Errorf("scenario %s: invalid session setting name %q", s.Name, name)
+		}
+		switch value.(type) {
+		case string, bool, int, int64, float64:
+		default:
+			return fmt.Errorf("scenario %s: session setting %s must be a single value, got %v", s.Name, name, value)
+		}
+	}
+	return nil
 }
 
 // MetricsConfig defines metrics collection settings
@@ -70,8 +241,18 @@ type MetricsConfig struct {
 	SystemMetrics       SystemMetrics  `mapstructure:"system_metrics"`
 	DatabaseMetrics     DatabaseMetrics `mapstructure:"database_metrics"`
 	LatencyPercentiles  []float64      `mapstructure:"latency_percentiles"`
+	LatencyRecorder     string         `mapstructure:"latency_recorder"` // "exact" (default) or "histogram"
+	ExportRaw           bool           `mapstructure:"export_raw"`       // write raw latency samples per run
+	RawSampleLimit      int            `mapstructure:"raw_sample_limit"` // max samples exported; larger runs are subsampled
+	Timeline            bool           `mapstructure:"timeline"`         // record percentiles per collection_interval
+	FailedLatency       bool           `mapstructure:"failed_latency"`   // record the time of failed attempts in a stream of their own
+	TimelineStream      bool           `mapstructure:"timeline_stream"`  // append timeline intervals to timeline.jsonl as they close; implies timeline
+	PerThread           bool           `mapstructure:"per_thread"`       // break each run's metrics down by worker thread
 }
 
+// DefaultRawSampleLimit bounds raw latency exports when metrics.raw_sample_limit is unset
+const DefaultRawSampleLimit = 1000000
+
 // SystemMetrics defines system-level metrics to collect
 type SystemMetrics struct {
 	CPU       bool `mapstructure:"cpu"`
@@ -91,9 +272,12 @@ type DatabaseMetrics struct {
 // ReportingConfig defines output and reporting options
 type ReportingConfig struct {
 	Formats    []string          `mapstructure:"formats"`
+	Compress   bool              `mapstructure:"compress"` // write results files as .json.gz
 	CLI        CLIReporting      `mapstructure:"cli"`
 	HTML       HTMLReporting     `mapstructure:"html"`
 	Comparison ComparisonConfig  `mapstructure:"comparison"`
+	ResultsDB  string            `mapstructure:"results_db"` // SQLite file every run's results are appended to
+	MetricsAddr string           `mapstructure:"metrics_addr"` // address to serve live Prometheus metrics on while running, e.g. ":9090"
 }
 
 // CLIReporting defines CLI output settings
@@ -111,35 +295,75 @@ type HTMLReporting struct {
 
 // ComparisonConfig defines comparison analysis settings
 type ComparisonConfig struct {
-	StatisticalAnalysis   bool    `mapstructure:"statistical_analysis"`
-	SignificanceThreshold float64 `mapstructure:"significance_threshold"`
-	MinimumSamples        int     `mapstructure:"minimum_samples"`
+	StatisticalAnalysis   bool    `mapstructure:"statistical_analysis"`   // test whether direct and NFS latencies differ significantly
+	SignificanceThreshold float64 `mapstructure:"significance_threshold"` // p-value below which a difference is significant
+	MinimumSamples        int     `mapstructure:"minimum_samples"`        // operations each storage type needs for the test
 }
 
+// DefaultSignificanceThreshold is used when reporting.comparison.significance_threshold is unset
+const DefaultSignificanceThreshold = 0.05
+
 // ExecutionConfig defines test execution parameters
 type ExecutionConfig struct {
 	WarmupDuration  int               `mapstructure:"warmup_duration"`  // seconds
 	CooldownDuration int              `mapstructure:"cooldown_duration"` // seconds
 	RepeatCount     int               `mapstructure:"repeat_count"`
 	RandomizeOrder  bool              `mapstructure:"randomize_order"`
+	Seed            int64             `mapstructure:"seed"` // seed for randomized order; 0 picks one and logs it
 	FailFast        bool              `mapstructure:"fail_fast"`
+	StorageTypes    []string          `mapstructure:"storage_types"`
+	Interleave      bool              `mapstructure:"interleave"`     // alternate storage types in slices
+	Deterministic   bool              `mapstructure:"deterministic"`  // equal per-thread work and a fixed task order, for reproducibility
+	SliceDuration   int               `mapstructure:"slice_duration"` // seconds per interleaved slice
+	ErrorBackoff    int               `mapstructure:"error_backoff_ms"`     // pause after a failed operation, doubling per consecutive failure
+	MaxErrorBackoff int               `mapstructure:"max_error_backoff_ms"` // cap on the doubled pause
+	MaxConsecutiveErrors int          `mapstructure:"max_consecutive_errors"` // failures in a row that fail the scenario; 0 retries forever
+	AbortOnErrorRate float64          `mapstructure:"abort_on_error_rate"`    // fraction of failed attempts that fails the scenario mid-run; 0 disables
+	StatementTimeout int              `mapstructure:"statement_timeout_ms"`   // cancel an operation running longer than this; 0 disables
+	VerifyData      bool              `mapstructure:"verify_data"` // check the stored rows against the generated ones after each run
+	InterRunPause   int               `mapstructure:"inter_run_pause"`       // seconds to pause when switching storage types
+	InterRunDropCaches bool           `mapstructure:"inter_run_drop_caches"` // drop the OS page cache after the pause
+	DiskCheck       DiskCheckConfig   `mapstructure:"disk_check"`
 	Cleanup         CleanupConfig     `mapstructure:"cleanup"`
 }
 
+// Disk space pre-check actions
+const (
+	DiskCheckWarn  = "warn"
+	DiskCheckAbort = "abort"
+	DiskCheckOff   = "off"
+)
+
+// DefaultDiskMinFreePercent is the share of a filesystem that must be free before a
+// duration-bound run, whose data volume cannot be estimated up front
+const DefaultDiskMinFreePercent = 10
+
+// DiskCheckConfig defines the free space check on data paths before the run
+type DiskCheckConfig struct {
+	Action         string  `mapstructure:"action"`           // warn (default), abort, or off when space looks insufficient
+	MinFreePercent float64 `mapstructure:"min_free_percent"` // free share required for duration-bound runs
+}
+
 // CleanupConfig defines cleanup behavior
 type CleanupConfig struct {
 	ResetDatabases  bool `mapstructure:"reset_databases"`
 	ClearCaches     bool `mapstructure:"clear_caches"`
 	RestartServices bool `mapstructure:"restart_services"`
+	DropTable       bool `mapstructure:"drop_table"` // drop the benchmark table after each run
+	KeepData        bool `mapstructure:"keep_data"`  // leave the last run's rows in the table and log where
 }
 
 // Load loads configuration from file and environment
 func Load() (*Config, error) {
 	var cfg Config
+	var metadata mapstructure.Metadata
 	
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := viper.Unmarshal(&cfg, func(dc *mapstructure.DecoderConfig) { dc.Metadata = &metadata }); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	if err := checkUnknownKeys(metadata.Unused); err != nil {
+		return nil, err
+	}
 	
 	// Set defaults
 	if cfg.Global.OutputDir == "" {
@@ -154,10 +378,219 @@ func Load() (*Config, error) {
 	if cfg.Global.MaxWorkers == 0 {
 		cfg.Global.MaxWorkers = 4
 	}
+	if !viper.IsSet("global.seed") {
+		cfg.Global.Seed = DefaultSeed
+	}
+	switch cfg.Metrics.LatencyRecorder {
+	case "":
+		cfg.Metrics.LatencyRecorder = "exact"
+	case "exact", "histogram":
+	default:
+		return nil, fmt.Errorf("unknown metrics.latency_recorder %q (valid: exact, histogram)", cfg.Metrics.LatencyRecorder)
+	}
+	if cfg.Metrics.RawSampleLimit == 0 {
+		cfg.Metrics.RawSampleLimit = DefaultRawSampleLimit
+	}
+	if !viper.IsSet("execution.max_consecutive_errors") {
+		cfg.Execution.MaxConsecutiveErrors = DefaultMaxConsecutiveErrors
+	}
+	if cfg.Execution.MaxConsecutiveErrors < 0 {
+		return nil, fmt.Errorf("execution.max_consecutive_errors must not be negative, got %d", cfg.Execution.MaxConsecutiveErrors)
+	}
+	if cfg.Execution.Cleanup.DropTable && cfg.Execution.Cleanup.KeepData {
+		return nil, fmt.Errorf("execution.cleanup.drop_table and execution.cleanup.keep_data cannot both be set")
+	}
+	if cfg.Execution.InterRunPause < 0 {
+		return nil, fmt.Errorf("execution.inter_run_pause must not be negative, got %d", cfg.Execution.InterRunPause)
+	}
+	if cfg.Execution.StatementTimeout < 0 {
+		return nil, fmt.Errorf("execution.statement_timeout_ms must not be negative, got %d", cfg.Execution.StatementTimeout)
+	}
+	if rate := cfg.Execution.AbortOnErrorRate; rate < 0 || rate >= 1 {
+		return nil, fmt.Errorf("execution.abort_on_error_rate must be a fraction from 0 to below 1 (e.g. 0.05 for 5%%), got %g", rate)
+	}
+	switch cfg.Execution.DiskCheck.Action {
+	case "":
+		cfg.Execution.DiskCheck.Action = DiskCheckWarn
+	case DiskCheckWarn, DiskCheckAbort, DiskCheckOff:
+	default:
+		return nil, fmt.Errorf("unknown execution.disk_check.action %q (valid: warn, abort, off)", cfg.Execution.DiskCheck.Action)
+	}
+	if !viper.IsSet("execution.disk_check.min_free_percent") {
+		cfg.Execution.DiskCheck.MinFreePercent = DefaultDiskMinFreePercent
+	}
+	if p := cfg.Execution.DiskCheck.MinFreePercent; p < 0 || p >= 100 {
+		return nil, fmt.Errorf("execution.disk_check.min_free_percent must be from 0 to below 100, got %g", p)
+	}
+	if cfg.Reporting.Comparison.SignificanceThreshold == 0 {
+		cfg.Reporting.Comparison.SignificanceThreshold = DefaultSignificanceThreshold
+	}
+	if p := cfg.Reporting.Comparison.SignificanceThreshold; p < 0 || p >= 1 {
+		return nil, fmt.Errorf("reporting.comparison.significance_threshold must be a p-value from 0 to below 1, got %g", p)
+	}
+	if len(cfg.Execution.StorageTypes) == 0 {
+		cfg.Execution.StorageTypes = KnownStorageTypes
+	}
+	if err := cfg.SetStorageTypes(cfg.Execution.StorageTypes); err != nil {
+		return nil, err
+	}
+	if err := cfg.validateMountOptions(); err != nil {
+		return nil, err
+	}
+	if err := cfg.validatePoolers(); err != nil {
+		return nil, err
+	}
+	if err := cfg.validateTables(); err != nil {
+		return nil, err
+	}
+	for _, scenario := range cfg.Scenarios {
+		if scenario.MaxRuntime < 0 || (scenario.MaxRuntime > 0 && scenario.MaxRuntime <= scenario.Duration) {
+			return nil, fmt.Errorf("scenario %s: max_scenario_runtime (%ds) must be longer than duration (%ds)", scenario.Name, scenario.MaxRuntime, scenario.Duration)
+		}
+		if err := scenario.validateSessionSettings(); err != nil {
+			return nil, err
+		}
+	}
 	
 	return &cfg, nil
 }
 
+// flagKeys are viper keys bound to command line flags rather than read from the config
+var flagKeys = map[string]bool{"verbose": true}
+
+// checkUnknownKeys rejects config keys that match no field, so a typo such as
+// "sceanrios" fails loading instead of being silently ignored
+func checkUnknownKeys(unused []string) error {
+	var unknown []string
+	for _, key := range unused {
+		if !flagKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown config keys (check for typos): %s", strings.Join(unknown, ", "))
+}
+
+// validatePoolers checks the pooler setting of every database connection, including the
+// connections of mount option variants. A variant connecting elsewhere than the NFS
+// connection must name its own admin console, or its pooler stats would be read from
+// the NFS connection's pooler.
+func (c *Config) validatePoolers() error {
+	check := func(key string, conn DatabaseConnectionConfig) error {
+		switch conn.Pooler {
+		case "", "pgbouncer":
+		default:
+			return fmt.Errorf("%s.pooler: unknown pooler %q (valid: pgbouncer)", key, conn.Pooler)
+		}
+		if conn.PoolerAdminDSN != "" && conn.Pooler == "" {
+			return fmt.Errorf("%s.pooler_admin_dsn requires pooler to be set", key)
+		}
+		return nil
+	}
+	for name, db := range c.Databases {
+		if err := check("databases."+name+".direct", db.Direct); err != nil {
+			return err
+		}
+		if err := check("databases."+name+".nfs", db.NFS); err != nil {
+			return err
+		}
+	}
+	for _, option := range c.NFS.MountOptions {
+		for name, variant := range option.Connections {
+			key := "nfs.mount_options." + option.Name + ".connections." + name
+			conn, _ := option.Connection(name, c.Databases[name].NFS)
+			if err := check(key, conn); err != nil {
+				return err
+			}
+			moved := variant.Host != "" || variant.Port != 0 || variant.DSN != ""
+			if moved && conn.PoolerAdminDSN != "" && variant.PoolerAdminDSN == "" {
+				return fmt.Errorf("%s.pooler_admin_dsn must be set when the variant sets host, port, or dsn, instead of inheriting databases.%s.nfs.pooler_admin_dsn", key, name)
+			}
+		}
+	}
+	return nil
+}
+
+// RunIDPlaceholder in a connection's table is replaced with the run's id, giving each run
+// its own table
+const RunIDPlaceholder = "{run_id}"
+
+// tablePattern matches a lowercase, optionally schema-qualified table name. Names are kept
+// lowercase since COPY quotes them while other statements fold them to lowercase.
+var tablePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+// validateTables checks the table of every database connection, including the
+// connections of mount option variants
+func (c *Config) validateTables() error {
+	check := func(key, table string) error {
+		if table == "" || tablePattern.MatchString(strings.ReplaceAll(table, RunIDPlaceholder, "0")) {
+			return nil
+		}
+		return fmt.Errorf("%s.table: invalid table name %q (lowercase letters, digits and underscores, optionally schema-qualified, may contain %s)", key, table, RunIDPlaceholder)
+	}
+	for name, db := range c.Databases {
+		if err := check("databases."+name+".direct", db.Direct.Table); err != nil {
+			return err
+		}
+		if err := check("databases."+name+".nfs", db.NFS.Table); err != nil {
+			return err
+		}
+	}
+	for _, option := range c.NFS.MountOptions {
+		for name, conn := range option.Connections {
+			if err := check("nfs.mount_options."+option.Name+".connections."+name, conn.Table); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nfsVersionPattern matches an NFS version in config form, such as "v3" or "v4.1"
+var nfsVersionPattern = regexp.MustCompile(`^v[0-9]+(\.[0-9]+)?$`)
+
+// validateMountOptions checks that mapped mount option variants can be told apart in
+// results. A variant's version is normalized to config form ("4.1" becomes "v4.1") and
+// names the variant when it has no name.
+func (c *Config) validateMountOptions() error {
+	for i := range c.NFS.MountOptions {
+		option := &c.NFS.MountOptions[i]
+		if option.Version == "" {
+			continue
+		}
+		if !strings.HasPrefix(option.Version, "v") {
+			option.Version = "v" + option.Version
+		}
+		if !nfsVersionPattern.MatchString(option.Version) {
+			return fmt.Errorf("nfs.mount_options: invalid version %q (e.g. v3, v4.0, v4.1, v4.2)", option.Version)
+		}
+		if option.Name == "" {
+			option.Name = option.Version
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, option := range c.NFS.MountOptions {
+		if option.Path == "" && len(option.Connections) == 0 {
+			continue
+		}
+		if option.Name == "" {
+			return fmt.Errorf("nfs.mount_options: a variant with a path or connections needs a name")
+		}
+		if seen[option.Name] {
+			return fmt.Errorf("nfs.mount_options: duplicate variant name %q", option.Name)
+		}
+		seen[option.Name] = true
+		if c.Execution.Interleave && len(option.Connections) > 0 {
+			return fmt.Errorf("nfs.mount_options: variant %q cannot be combined with execution.interleave", option.Name)
+		}
+	}
+	return nil
+}
+
 // GetEnabledDatabases returns list of enabled database names
 func (c *Config) GetEnabledDatabases() []string {
 	var enabled []string
@@ -194,6 +627,35 @@ func (c *Config) FilterDatabases(databases []string) {
 	}
 }
 
+// KnownStorageTypes lists the storage types the benchmark can run against
+var KnownStorageTypes = []string{"direct", "nfs"}
+
+// SetStorageTypes restricts the run to the given storage types, rejecting unknown ones
+func (c *Config) SetStorageTypes(types []string) error {
+	known := make(map[string]bool)
+	for _, t := range KnownStorageTypes {
+		known[t] = true
+	}
+
+	var selected []string
+	seen := make(map[string]bool)
+	for _, t := range types {
+		if !known[t] {
+			return fmt.Errorf("unknown storage type %q (valid: %s)", t, strings.Join(KnownStorageTypes, ", "))
+		}
+		if !seen[t] {
+			seen[t] = true
+			selected = append(selected, t)
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("at least one storage type is required (valid: %s)", strings.Join(KnownStorageTypes, ", "))
+	}
+
+	c.Execution.StorageTypes = selected
+	return nil
+}
+
 // FilterScenarios enables only specified scenarios
 func (c *Config) FilterScenarios(scenarios []string) {
 	scenarioSet := make(map[string]bool)
@@ -206,6 +668,145 @@ func (c *Config) FilterScenarios(scenarios []string) {
 	}
 }
 
+// SetScenarioDuration overrides the duration of every enabled scenario, e.g. for a quick
+// smoke test of a config. The duration must be a whole number of seconds.
+func (c *Config) SetScenarioDuration(d time.Duration) error {
+	if d < time.Second || d%time.Second != 0 {
+		return fmt.Errorf("scenario duration must be a positive whole number of seconds, got %s", d)
+	}
+	seconds := int(d / time.Second)
+
+	for i := range c.Scenarios {
+		scenario := &c.Scenarios[i]
+		if !scenario.Enabled {
+			continue
+		}
+		if scenario.MaxRuntime > 0 && scenario.MaxRuntime <= seconds {
+			return fmt.Errorf("scenario %s: max_scenario_runtime (%ds) must be longer than duration (%ds)", scenario.Name, scenario.MaxRuntime, seconds)
+		}
+		scenario.Duration = seconds
+	}
+	return nil
+}
+
+// SetScenarioParam overrides one scenario parameter from a "scenario.param=value" spec,
+// as given to --set. The value is converted to the type the parameter already has in
+// the config; a new parameter becomes an integer, number, or boolean when it parses as
+// one and a string otherwise. It returns the value that was set.
+func (c *Config) SetScenarioParam(spec string) (interface{}, error) {
+	target, raw, ok := strings.Cut(spec, "=")
+	name, param, dotted := strings.Cut(target, ".")
+	if !ok || !dotted || name == "" || param == "" {
+		return nil, fmt.Errorf("invalid parameter override %q (expected scenario.param=value)", spec)
+	}
+
+	for i := range c.Scenarios {
+		scenario := &c.Scenarios[i]
+		if scenario.Name != name {
+			continue
+		}
+		value, err := coerceParam(scenario.Parameters[param], raw)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %s: parameter %s: %w", name, param, err)
+		}
+		if scenario.Parameters == nil {
+			scenario.Parameters = make(map[string]interface{})
+		}
+		scenario.Parameters[param] = value
+		return value, nil
+	}
+	return nil, fmt.Errorf("invalid parameter override %q: no scenario named %s", spec, name)
+}
+
+// coerceParam converts a command line value to the type of a parameter's current value
+func coerceParam(current interface{}, raw string) (interface{}, error) {
+	switch current.(type) {
+	case int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, got %q", raw)
+		}
+		return n, nil
+	case float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", raw)
+		}
+		return f, nil
+	case bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", raw)
+		}
+		return b, nil
+	case []interface{}:
+		list := []interface{}{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	case nil:
+		if n, err := strconv.Atoi(raw); err == nil {
+			return n, nil
+		}
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f, nil
+		}
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b, nil
+		}
+	}
+	return raw, nil
+}
+
+// DefaultCollectionInterval is the progress and timeline interval used when
+// metrics.collection_interval is unset
+const DefaultCollectionInterval = 5 * time.Second
+
+// GetCollectionInterval returns the metrics collection interval as time.Duration
+func (c *Config) GetCollectionInterval() time.Duration {
+	if c.Metrics.CollectionInterval <= 0 {
+		return DefaultCollectionInterval
+	}
+	return time.Duration(c.Metrics.CollectionInterval) * time.Second
+}
+
+// Defaults for retrying failed operations when the execution settings are unset
+const (
+	DefaultErrorBackoff         = 100 * time.Millisecond
+	DefaultMaxErrorBackoff      = 5 * time.Second
+	DefaultMaxConsecutiveErrors = 50
+)
+
+// GetErrorBackoff returns the pause after a first failed operation
+func (c *Config) GetErrorBackoff() time.Duration {
+	if c.Execution.ErrorBackoff <= 0 {
+		return DefaultErrorBackoff
+	}
+	return time.Duration(c.Execution.ErrorBackoff) * time.Millisecond
+}
+
+// GetMaxErrorBackoff returns the longest pause between retries of failed operations
+func (c *Config) GetMaxErrorBackoff() time.Duration {
+	if c.Execution.MaxErrorBackoff <= 0 {
+		return DefaultMaxErrorBackoff
+	}
+	return time.Duration(c.Execution.MaxErrorBackoff) * time.Millisecond
+}
+
+// DefaultSliceDuration is the interleaved slice length used when slice_duration is unset
+const DefaultSliceDuration = 10 * time.Second
+
+// GetSliceDuration returns the interleaved slice duration as time.Duration
+func (c *Config) GetSliceDuration() time.Duration {
+	if c.Execution.SliceDuration <= 0 {
+		return DefaultSliceDuration
+	}
+	return time.Duration(c.Execution.SliceDuration) * time.Second
+}
+
 // GetWarmupDuration returns warmup duration as time.Duration
 func (c *Config) GetWarmupDuration() time.Duration {
 	return time.Duration(c.Execution.WarmupDuration) * time.Second
@@ -215,3 +816,18 @@ func (c *Config) GetWarmupDuration() time.Duration {
 func (c *Config) GetCooldownDuration() time.Duration {
 	return time.Duration(c.Execution.CooldownDuration) * time.Second
 }
+
+// GetInterRunPause returns the pause between storage types as time.Duration
+func (c *Config) GetInterRunPause() time.Duration {
+	return time.Duration(c.Execution.InterRunPause) * time.Second
+}
+
+// GetStatementTimeout returns the limit on a single operation, or 0 for none
+func (c *Config) GetStatementTimeout() time.Duration {
+	return time.Duration(c.Execution.StatementTimeout) * time.Millisecond
+}
+
+// GetConnMaxLifetime returns the connection max lifetime as time.Duration
+func (p PoolConfig) GetConnMaxLifetime() time.Duration {
+	return time.Duration(p.ConnMaxLifetime) * time.Second
+}
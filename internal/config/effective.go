@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces secret values in the effective config
+const Redacted = "REDACTED"
+
+// secretKeys are config keys whose values are never written out
+var secretKeys = map[string]bool{"password": true}
+
+// dsnPassword matches the password of a keyword/value connection string
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// RedactDSN replaces the password in a connection string, given either as a URL or as
+// keyword/value pairs, with Redacted
+func RedactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), Redacted)
+		}
+		if query := u.Query(); query.Has("password") {
+			query.Set("password", Redacted)
+			u.RawQuery = query.Encode()
+		}
+		return u.String()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+Redacted)
+}
+
+// Effective returns the resolved config, after defaults and command line overrides,
+// keyed like the config file and with passwords redacted. It is saved with results so a
+// run describes how it was produced.
+func (c *Config) Effective() map[string]interface{} {
+	return effectiveValue(reflect.ValueOf(*c)).(map[string]interface{})
+}
+
+func effectiveValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			key := field.Tag.Get("mapstructure")
+			if key == "" || key == "-" || !field.IsExported() {
+				continue
+			}
+			out[key] = effectiveEntry(key, v.Field(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			out[key] = effectiveEntry(key, iter.Value())
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		out := make([]interface{}, v.Len())
+		for i := range out {
+			out[i] = effectiveValue(v.Index(i))
+		}
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return effectiveValue(v.Elem())
+	default:
+		return v.Interface()
+	}
+}
+
+// effectiveEntry converts the value under a key, redacting it if the key is secret and
+// redacting the password of a dsn such as pooler_admin_dsn
+func effectiveEntry(key string, v reflect.Value) interface{} {
+	if secretKeys[key] && !v.IsZero() {
+		return Redacted
+	}
+	if strings.HasSuffix(key, "dsn") && v.Kind() == reflect.String {
+		return RedactDSN(v.String())
+	}
+	return effectiveValue(v)
+}
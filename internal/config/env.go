@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix prefixes environment variables that override config keys, e.g.
+// NFSBENCH_DATABASES_POSTGRESQL_DIRECT_PASSWORD overrides databases.postgresql.direct.password
+const EnvPrefix = "NFSBENCH"
+
+// credentialDatabases and credentialKeys define the connection settings that are bound to
+// environment variables even when the config file leaves them out, so secrets never have
+// to be written to YAML
+var (
+	credentialDatabases = []string{"postgresql", "mysql", "sqlite"}
+	credentialKeys      = []string{"host", "port", "username", "password", "database", "dsn"}
+)
+
+// BindEnv lets environment variables override config values. Any key present in the config
+// file can be overridden by its upper-cased path with dots replaced by underscores and the
+// NFSBENCH_ prefix; connection credentials are additionally bound explicitly. Environment
+// variables take precedence over the config file, and command-line flags over both.
+func BindEnv() error {
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+
+	for _, db := range credentialDatabases {
+		for _, storageType := range KnownStorageTypes {
+			for _, key := range credentialKeys {
+				if err := viper.BindEnv(fmt.Sprintf("databases.%s.%s.%s", db, storageType, key)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// ReadFiles reads the first file as the base config and merges each further file onto it
+// in order, so a shared base can be combined with per-environment overlays. Nested maps
+// merge key by key; any other value in an overlay, including a list such as scenarios,
+// replaces the base value whole.
+func ReadFiles(paths []string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no config files given")
+	}
+
+	viper.SetConfigFile(paths[0])
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", paths[0], err)
+	}
+
+	for _, path := range paths[1:] {
+		overlay := viper.New()
+		overlay.SetConfigFile(path)
+		if err := overlay.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config overlay %s: %w", path, err)
+		}
+		if err := viper.MergeConfigMap(overlay.AllSettings()); err != nil {
+			return fmt.Errorf("failed to merge config overlay %s: %w", path, err)
+		}
+	}
+	return nil
+}
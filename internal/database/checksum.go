@@ -0,0 +1,36 @@
+package database
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"strconv"
+)
+
+// RecordChecksum is an order-independent fingerprint of a set of benchmark records: the
+// row count and the sum of a 28-bit hash of each record's text and number. The hash is
+// the first 7 hex digits of md5("<text>:<number>"), which the database can compute with
+// md5() too, so records generated in the benchmark can be checked against the rows that
+// were actually stored. The JSON column is left out because JSONB normalizes it.
+type RecordChecksum struct {
+	Rows int64 `json:"rows"`
+	Sum  int64 `json:"sum"`
+}
+
+// Add includes records in the checksum
+func (c *RecordChecksum) Add(records []BenchmarkRecord) {
+	for _, record := range records {
+		c.Sum += recordHash(record)
+	}
+	c.Rows += int64(len(records))
+}
+
+// Merge includes another checksum, e.g. one accumulated by a different thread
+func (c *RecordChecksum) Merge(other RecordChecksum) {
+	c.Rows += other.Rows
+	c.Sum += other.Sum
+}
+
+func recordHash(record BenchmarkRecord) int64 {
+	sum := md5.Sum([]byte(record.Text + ":" + strconv.Itoa(record.Number)))
+	return int64(binary.BigEndian.Uint32(sum[:4]) >> 4)
+}
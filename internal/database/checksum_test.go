@@ -0,0 +1,25 @@
+package database
+
+import "testing"
+
+func TestRecordChecksum(t *testing.T) {
+	a := BenchmarkRecord{Text: "abc", Number: 1}
+	b := BenchmarkRecord{Text: "hello world", Number: 42}
+
+	// First 7 hex digits of md5("abc:1") and md5("hello world:42"), as the database
+	// computes them
+	var c RecordChecksum
+	c.Add([]BenchmarkRecord{a, b})
+	if want := (RecordChecksum{Rows: 2, Sum: 0x7faac33 + 0x4889194}); c != want {
+		t.Errorf("Add = %+v, want %+v", c, want)
+	}
+
+	// Order and grouping do not matter
+	var first, second RecordChecksum
+	first.Add([]BenchmarkRecord{b})
+	second.Add([]BenchmarkRecord{a})
+	first.Merge(second)
+	if first != c {
+		t.Errorf("Merged checksum %+v differs from %+v", first, c)
+	}
+}
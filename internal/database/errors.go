@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/lib/pq"
+)
+
+// Kinds of database errors. Errors returned by PostgresDB wrap one of them when the
+// cause is recognized, so callers can tell them apart with errors.Is while the original
+// error, such as a *pq.Error, stays reachable with errors.As.
+var (
+	// ErrConnect means the connection to the server could not be opened or was lost:
+	// refused or reset connections, a server shutting down, or too many clients
+	ErrConnect = errors.New("connection failed")
+	// ErrTimeout means an operation ran out of time: a context deadline, a network
+	// timeout, or a statement or lock timeout on the server
+	ErrTimeout = errors.New("timed out")
+	// ErrWrite means the server could not read or write its files: an I/O error, a full
+	// disk, or corrupted data, as a failing NFS mount produces
+	ErrWrite = errors.New("storage I/O failed")
+	// ErrConstraint means a statement violated a table constraint, which retrying the
+	// same data cannot fix
+	ErrConstraint = errors.New("constraint violation")
+)
+
+// Error is an error from PostgresDB classified as one of the error kinds
+type Error struct {
+	Kind error // ErrConnect, ErrTimeout, ErrWrite, or ErrConstraint
+	Err  error // the underlying error
+}
+
+func (e *Error) Error() string {
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+// Unwrap returns both the kind and the underlying error, so errors.Is and errors.As
+// match either
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// classify wraps err in an Error of the kind its cause belongs to, or returns it
+// unchanged when the cause is not recognized (or err is nil or already classified)
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return err
+	}
+	if kind := errorKind(err); kind != nil {
+		return &Error{Kind: kind, Err: err}
+	}
+	return err
+}
+
+// errorKind returns the kind of err, or nil if it is none of them
+func errorKind(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErrorKind(pqErr)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrTimeout
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return ErrConnect
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrConnect
+	}
+	return nil
+}
+
+// pqErrorKind classifies a server error by its SQLSTATE code
+// (https://www.postgresql.org/docs/current/errcodes-appendix.html)
+func pqErrorKind(err *pq.Error) error {
+	code := string(err.Code)
+	switch code {
+	case "57014", "55P03": // query_canceled (statement_timeout), lock_not_available (lock_timeout)
+		return ErrTimeout
+	case "53300", "57P01", "57P02", "57P03": // too_many_connections, shutdowns, cannot_connect_now
+		return ErrConnect
+	case "53100", "XX001", "XX002": // disk_full, data_corrupted, index_corrupted
+		return ErrWrite
+	}
+	switch {
+	case strings.HasPrefix(code, "08"), strings.HasPrefix(code, "28"): // connection exception, invalid authorization
+		return ErrConnect
+	case strings.HasPrefix(code, "23"): // integrity constraint violation
+		return ErrConstraint
+	case strings.HasPrefix(code, "58"): // system error, such as io_error
+		return ErrWrite
+	}
+	return nil
+}
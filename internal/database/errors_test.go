@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"statement timeout", &pq.Error{Code: "57014"}, ErrTimeout},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), ErrTimeout},
+		{"admin shutdown", &pq.Error{Code: "57P01"}, ErrConnect},
+		{"connection exception", &pq.Error{Code: "08006"}, ErrConnect},
+		{"refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), ErrConnect},
+		{"unique violation", &pq.Error{Code: "23505"}, ErrConstraint},
+		{"io error", &pq.Error{Code: "58030"}, ErrWrite},
+		{"disk full", &pq.Error{Code: "53100"}, ErrWrite},
+		{"syntax error", &pq.Error{Code: "42601"}, nil},
+		{"unrecognized", errors.New("boom"), nil},
+	}
+
+	for _, tt := range tests {
+		err := classify(tt.err)
+		if tt.want == nil {
+			if err != tt.err {
+				t.Errorf("%s: expected the error unchanged, got %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: underlying error is no longer reachable from %v", tt.name, err)
+		}
+		if classify(err) != err {
+			t.Errorf("%s: classifying twice wrapped the error again", tt.name)
+		}
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(classify(&pq.Error{Code: "23505"}), &pqErr) || pqErr.Code != "23505" {
+		t.Error("Expected the *pq.Error to be reachable with errors.As")
+	}
+	if classify(nil) != nil {
+		t.Error("Expected nil for a nil error")
+	}
+}
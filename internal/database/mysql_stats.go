@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// InnoDBStatusVariables are the SHOW GLOBAL STATUS counters recorded for MySQL runs. Fsync
+// counts are the clearest proxy for NFS write overhead on InnoDB.
+var InnoDBStatusVariables = []string{
+	"Innodb_buffer_pool_write_requests",
+	"Innodb_data_fsyncs",
+	"Innodb_os_log_fsyncs",
+}
+
+// ReadInnoDBStatus reads the InnoDB status counters from a MySQL server. It only uses
+// database/sql, so it works with whichever MySQL driver the connection was opened with.
+func ReadInnoDBStatus(ctx context.Context, db *sql.DB) (map[string]int64, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(InnoDBStatusVariables)), ",")
+	args := make([]interface{}, len(InnoDBStatusVariables))
+	for i, name := range InnoDBStatusVariables {
+		args[i] = name
+	}
+
+	rows, err := db.QueryContext(ctx, "SHOW GLOBAL STATUS WHERE Variable_name IN ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	status := make(map[string]int64)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("status variable %s: %w", name, err)
+		}
+		status[name] = n
+	}
+	return status, rows.Err()
+}
+
+// InnoDBStatsDelta returns how much each InnoDB counter grew between two ReadInnoDBStatus
+// snapshots, keyed by the lower-cased variable name (e.g. "innodb_data_fsyncs") for DBStats.
+// Counters missing from either snapshot are left out.
+func InnoDBStatsDelta(before, after map[string]int64) map[string]interface{} {
+	stats := make(map[string]interface{})
+	for _, name := range InnoDBStatusVariables {
+		b, okBefore := before[name]
+		a, okAfter := after[name]
+		if !okBefore || !okAfter {
+			continue
+		}
+		stats[strings.ToLower(name)] = a - b
+	}
+	return stats
+}
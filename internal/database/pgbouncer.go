@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// PoolerPgBouncer is the pooler setting for connections through PgBouncer
+const PoolerPgBouncer = "pgbouncer"
+
+// pgbouncerStats are the cumulative SHOW STATS counters of one PgBouncer database, such
+// as total_wait_time (microseconds clients waited for a server connection) and
+// total_xact_count, keyed by column name
+type pgbouncerStats map[string]int64
+
+// queryPgBouncerStats reads the SHOW STATS counters of a database from the PgBouncer
+// admin console. An empty database sums every database but the console's own.
+func queryPgBouncerStats(ctx context.Context, adminDSN, database string) (pgbouncerStats, error) {
+	admin, err := sql.Open("postgres", adminDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pgbouncer admin console: %w", err)
+	}
+	defer admin.Close()
+
+	// The admin console only speaks the simple query protocol, which lib/pq uses for
+	// queries without arguments
+	rows, err := admin.QueryContext(ctx, "SHOW STATS")
+	if err != nil {
+		return nil, fmt.Errorf("SHOW STATS failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	stats := make(pgbouncerStats)
+	found := false
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		name := values[0].String
+		if (database != "" && name != database) || (database == "" && name == PoolerPgBouncer) {
+			continue
+		}
+		found = true
+		for i, column := range columns[1:] {
+			if n, err := strconv.ParseInt(values[i+1].String, 10, 64); err == nil {
+				stats[column] += n
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("pgbouncer has no statistics for database %q", database)
+	}
+	return stats, nil
+}
+
+// since returns the counters accumulated since an earlier snapshot
+func (s pgbouncerStats) since(earlier pgbouncerStats) pgbouncerStats {
+	delta := make(pgbouncerStats, len(s))
+	for column, value := range s {
+		delta[column] = value - earlier[column]
+	}
+	return delta
+}
@@ -1,103 +1,730 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/l22io/nfsvsdirectbench/internal/config"
 )
 
+// Default connection pool settings, used when the pool config leaves a value unset
+const (
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = 5
+	DefaultConnMaxLifetime = 5 * time.Minute
+	DefaultConnectTimeout  = 10 * time.Second
+	DefaultConnectBackoff  = time.Second
+	MaxConnectBackoff      = 30 * time.Second
+)
+
+// DefaultBenchmarkTable is the benchmark table used when the connection config sets none
+const DefaultBenchmarkTable = "benchmark_data"
+
 // PostgresDB represents a PostgreSQL database connection
 type PostgresDB struct {
 	db     *sql.DB
+	connector driver.Connector // opens the pool's connections, and ConnectFresh's
 	config config.DatabaseConnectionConfig
 	name   string
-}
+	table  string // the benchmark table, schema-qualified if configured so
 
-// NewPostgresDB creates a new PostgreSQL database connection
-func NewPostgresDB(cfg config.DatabaseConnectionConfig, name string) (*PostgresDB, error) {
-	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.Database)
+	poolBaseline sql.DBStats // pool counters at MarkPoolStats; waits are reported since then
+	poolerBaseline pgbouncerStats // PgBouncer counters at MarkPoolStats, with pooler_admin_dsn
+}
 
-	db, err := sql.Open("postgres", connStr)
+// NewPostgresDB creates a new PostgreSQL database connection. The initial ping is bounded
+// by the configured connect timeout so an unreachable server fails instead of hanging.
+// Session settings are applied to every connection, so one the server rejects fails the
+// ping.
+func NewPostgresDB(ctx context.Context, cfg config.DatabaseConnectionConfig, name string) (*PostgresDB, error) {
+	connector, err := newSessionConnector(buildPostgresDSN(cfg), cfg.SessionSettings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	db := sql.OpenDB(connector)
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	maxOpen := cfg.Pool.MaxOpen
+	if maxOpen == 0 {
+		maxOpen = DefaultMaxOpenConns
+	}
+	maxIdle := cfg.Pool.MaxIdle
+	if maxIdle == 0 {
+		maxIdle = DefaultMaxIdleConns
+	}
+	lifetime := cfg.Pool.GetConnMaxLifetime()
+	if lifetime == 0 {
+		lifetime = DefaultConnMaxLifetime
+	}
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(lifetime)
 
 	// Test connection
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+	connectTimeout := cfg.GetConnectTimeout()
+	if connectTimeout == 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", classify(err))
+	}
+
+	table := cfg.Table
+	if table == "" {
+		table = DefaultBenchmarkTable
 	}
 
 	return &PostgresDB{
 		db:     db,
+		connector: connector,
 		config: cfg,
 		name:   name,
+		table:  table,
 	}, nil
 }
 
+// ConnectPostgresDB connects like NewPostgresDB, retrying up to cfg.ConnectRetries times
+// with exponential backoff so transient failures (a server still starting up, an NFS
+// hiccup) don't fail the scenario
+func ConnectPostgresDB(ctx context.Context, cfg config.DatabaseConnectionConfig, name string) (*PostgresDB, error) {
+	backoff := cfg.GetConnectBackoff()
+	if backoff == 0 {
+		backoff = DefaultConnectBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		db, err := NewPostgresDB(ctx, cfg, name)
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= cfg.ConnectRetries || ctx.Err() != nil {
+			return nil, err
+		}
+
+		slog.Warn("Connection attempt failed, retrying", "database", name, "attempt", attempt+1, "attempts", cfg.ConnectRetries+1, "error", err, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > MaxConnectBackoff {
+			backoff = MaxConnectBackoff
+		}
+	}
+}
+
+// buildPostgresDSN builds a libpq keyword/value connection string from the config, or
+// returns the configured dsn with connect_timeout and sslmode added where it sets none.
+// Connections through PgBouncer also send parameters with their query, so no statement
+// is prepared on a server connection the pooler may hand to another client.
+func buildPostgresDSN(cfg config.DatabaseConnectionConfig) string {
+	if cfg.DSN != "" {
+		dsn := cfg.DSN
+		if timeout := cfg.GetConnectTimeout(); timeout > 0 {
+			dsn = withDSNSetting(dsn, "connect_timeout", strconv.Itoa(int(timeout.Seconds())))
+		}
+		// Without sslmode the driver requires TLS, which a PgBouncer without client_tls
+		// rejects, so connections through it get the structured fields' default
+		sslMode := cfg.SSLMode
+		if sslMode == "" && cfg.Pooler == PoolerPgBouncer {
+			sslMode = "disable"
+		}
+		if sslMode != "" {
+			dsn = withDSNSetting(dsn, "sslmode", sslMode)
+		}
+		if cfg.Pooler == PoolerPgBouncer {
+			dsn = withDSNSetting(dsn, "binary_parameters", "yes")
+		}
+		return dsn
+	}
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	params := []string{
+		"host=" + quoteDSNValue(cfg.Host),
+		fmt.Sprintf("port=%d", cfg.Port),
+		"user=" + quoteDSNValue(cfg.Username),
+		"password=" + quoteDSNValue(cfg.Password),
+		"dbname=" + quoteDSNValue(cfg.Database),
+		"sslmode=" + quoteDSNValue(sslMode),
+	}
+	if timeout := cfg.GetConnectTimeout(); timeout > 0 {
+		params = append(params, fmt.Sprintf("connect_timeout=%d", int(timeout.Seconds())))
+	}
+	if cfg.SSLRootCert != "" {
+		params = append(params, "sslrootcert="+quoteDSNValue(cfg.SSLRootCert))
+	}
+	if cfg.SSLCert != "" {
+		params = append(params, "sslcert="+quoteDSNValue(cfg.SSLCert))
+	}
+	if cfg.SSLKey != "" {
+		params = append(params, "sslkey="+quoteDSNValue(cfg.SSLKey))
+	}
+	if cfg.Pooler == PoolerPgBouncer {
+		params = append(params, "binary_parameters=yes")
+	}
+
+	return strings.Join(params, " ")
+}
+
+// withDSNSetting adds keyword=value to a keyword/value or URL dsn, unless the dsn
+// already sets keyword
+func withDSNSetting(dsn, keyword, value string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return dsn // the driver reports the malformed URL
+		}
+		query := u.Query()
+		if query.Get(keyword) == "" {
+			query.Set(keyword, value)
+			u.RawQuery = query.Encode()
+		}
+		return u.String()
+	}
+	if regexp.MustCompile(`(^|\s)` + regexp.QuoteMeta(keyword) + `\s*=`).MatchString(dsn) {
+		return dsn
+	}
+	return dsn + " " + keyword + "=" + quoteDSNValue(value)
+}
+
+// quoteDSNValue quotes a connection string value if it is empty or contains spaces or quotes
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return "'" + escaped + "'"
+}
+
+// inTableSchema qualifies the name of a relation belonging to the benchmark table, such
+// as an index or partition, with the table's schema if it has one
+func (p *PostgresDB) inTableSchema(name string) string {
+	if schema, _, ok := strings.Cut(p.table, "."); ok {
+		return schema + "." + name
+	}
+	return name
+}
+
+// tableName returns the benchmark table's name without its schema
+func (p *PostgresDB) tableName() string {
+	if _, name, ok := strings.Cut(p.table, "."); ok {
+		return name
+	}
+	return p.table
+}
+
+// ConnectFresh opens a new connection outside the pool, as a short-lived client would,
+// and closes it again. It returns the time to establish the connection: the handshake,
+// authentication, backend startup, and any session settings. Closing is not timed.
+func (p *PostgresDB) ConnectFresh(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	conn, err := p.connector.Connect(ctx)
+	latency := time.Since(start)
+	if err != nil {
+		return latency, classify(err)
+	}
+	return latency, classify(conn.Close())
+}
+
 // Close closes the database connection
 func (p *PostgresDB) Close() error {
 	return p.db.Close()
 }
 
-// CreateBenchmarkTable creates the benchmark table for testing
-func (p *PostgresDB) CreateBenchmarkTable() error {
-	query := `
-		CREATE TABLE IF NOT EXISTS benchmark_data (
+// CreateBenchmarkTable creates the benchmark table for testing. A partitioned table left
+// by partitioned_inserts is dropped first, so monolithic scenarios never measure one.
+func (p *PostgresDB) CreateBenchmarkTable(ctx context.Context) error {
+	kind, err := p.benchmarkTableKind(ctx)
+	if err != nil {
+		return err
+	}
+	if kind == relkindPartitioned {
+		if _, err := p.db.ExecContext(ctx, "DROP TABLE "+p.table+" CASCADE"); err != nil {
+			return fmt.Errorf("failed to drop partitioned benchmark table: %w", err)
+		}
+	}
+
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
 			id SERIAL PRIMARY KEY,
-			data_text VARCHAR(1000),
+			data_text TEXT,
 			data_int INTEGER,
 			data_timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			data_json JSONB
 		)
-	`
-	_, err := p.db.Exec(query)
+	`, p.table)
+	if _, err := p.db.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	// Tables created by older versions limited data_text to VARCHAR(1000), which is too
+	// small for byte-sized records. ALTER TABLE takes an exclusive lock, so only widen
+	// the column when it is not TEXT already.
+	var schema string
+	if s, _, ok := strings.Cut(p.table, "."); ok {
+		schema = s
+	}
+	var dataType string
+	err = p.db.QueryRowContext(ctx, `
+		SELECT data_type FROM information_schema.columns
+		WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema())
+		  AND table_name = $2 AND column_name = 'data_text'`, schema, p.tableName()).Scan(&dataType)
+	if err != nil {
+		return fmt.Errorf("failed to inspect data_text column: %w", err)
+	}
+	if dataType == "text" {
+		return nil
+	}
+	_, err = p.db.ExecContext(ctx, "ALTER TABLE "+p.table+" ALTER COLUMN data_text TYPE TEXT")
 	return err
 }
 
-// ClearBenchmarkTable clears all data from the benchmark table
-func (p *PostgresDB) ClearBenchmarkTable() error {
-	_, err := p.db.Exec("TRUNCATE TABLE benchmark_data RESTART IDENTITY")
-	return err
+// relkindPartitioned is the pg_class relkind of a partitioned table
+const relkindPartitioned = "p"
+
+// benchmarkTableKind returns the relkind of the benchmark table, or "" if it does not exist
+func (p *PostgresDB) benchmarkTableKind(ctx context.Context) (string, error) {
+	var kind string
+	err := p.db.QueryRowContext(ctx, "SELECT relkind::text FROM pg_class WHERE oid = to_regclass($1)", p.table).Scan(&kind)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect benchmark table: %w", err)
+	}
+	return kind, nil
 }
 
-// InsertBatch inserts a batch of records
-func (p *PostgresDB) InsertBatch(batch []BenchmarkRecord) error {
-	tx, err := p.db.Begin()
+// CreatePartitionedBenchmarkTable creates the benchmark table hash-partitioned on id into
+// the given number of partitions, named after the table with suffixes _p0 onwards. An
+// existing table that is not partitioned the same way is dropped and recreated.
+func (p *PostgresDB) CreatePartitionedBenchmarkTable(ctx context.Context, partitions int) error {
+	if partitions < 1 {
+		return fmt.Errorf("partitions must be at least 1, got %d", partitions)
+	}
+
+	kind, err := p.benchmarkTableKind(ctx)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
+	if kind == relkindPartitioned {
+		var existing int
+		if err := p.db.QueryRowContext(ctx, "SELECT count(*) FROM pg_inherits WHERE inhparent = $1::regclass", p.table).Scan(&existing); err != nil {
+			return fmt.Errorf("failed to count benchmark table partitions: %w", err)
+		}
+		if existing == partitions {
+			return nil
+		}
+	}
+	if kind != "" {
+		if _, err := p.db.ExecContext(ctx, "DROP TABLE "+p.table+" CASCADE"); err != nil {
+			return fmt.Errorf("failed to drop benchmark table: %w", err)
+		}
+	}
+
+	queries := []string{fmt.Sprintf(`
+		CREATE TABLE %s (
+			id SERIAL PRIMARY KEY,
+			data_text TEXT,
+			data_int INTEGER,
+			data_timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			data_json JSONB
+		) PARTITION BY HASH (id)
+	`, p.table)}
+	for i := 0; i < partitions; i++ {
+		queries = append(queries, fmt.Sprintf(
+			"CREATE TABLE %s_p%d PARTITION OF %s FOR VALUES WITH (MODULUS %d, REMAINDER %d)",
+			p.table, i, p.table, partitions, i))
+	}
+	for _, query := range queries {
+		if _, err := p.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to create partitioned benchmark table: %w", err)
+		}
+	}
+	return nil
+}
 
-	stmt, err := tx.Prepare("INSERT INTO benchmark_data (data_text, data_int, data_json) VALUES ($1, $2, $3)")
+// PartitionSizes returns the total size in bytes, including indexes and TOAST, of each
+// partition of the benchmark table
+func (p *PostgresDB) PartitionSizes(ctx context.Context) (map[string]int64, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT relid::regclass::text, pg_total_relation_size(relid)
+		FROM pg_partition_tree($1)
+		WHERE isleaf
+	`, p.table)
 	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sizes := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var size int64
+		if err := rows.Scan(&name, &size); err != nil {
+			return nil, err
+		}
+		sizes[name] = size
+	}
+	return sizes, rows.Err()
+}
+
+// ToastSize returns the size in bytes of the TOAST relations, with their indexes, holding
+// the benchmark table's values stored out of line. It is part of the table's total size,
+// but large values take a write path of their own.
+func (p *PostgresDB) ToastSize(ctx context.Context) (int64, error) {
+	var size int64
+	err := p.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(pg_total_relation_size(reltoastrelid)), 0) FROM pg_class
+		WHERE oid IN (SELECT relid FROM pg_partition_tree($1)) AND reltoastrelid <> 0
+	`, p.table).Scan(&size)
+	return size, err
+}
+
+// ClearBenchmarkTable clears all data from the benchmark table
+func (p *PostgresDB) ClearBenchmarkTable(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, "TRUNCATE TABLE "+p.table+" RESTART IDENTITY")
+	return err
+}
+
+// ResetBenchmarkTable empties the benchmark table like ClearBenchmarkTable, then
+// rewrites it with VACUUM FULL and refreshes planner statistics, so each run starts
+// from the same on-disk state
+func (p *PostgresDB) ResetBenchmarkTable(ctx context.Context) error {
+	for _, query := range []string{
+		"TRUNCATE TABLE " + p.table + " RESTART IDENTITY",
+		"VACUUM FULL " + p.table,
+		"ANALYZE " + p.table,
+	} {
+		if _, err := p.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("%s: %w", query, err)
+		}
+	}
+	return nil
+}
+
+// DropBenchmarkTable drops the benchmark table, with its partitions and indexes, if it
+// exists
+func (p *PostgresDB) DropBenchmarkTable(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, "DROP TABLE IF EXISTS "+p.table+" CASCADE")
+	return err
+}
+
+// CheckCreateTable verifies the user may create and drop tables, using a scratch table
+// so the benchmark table is left untouched
+func (p *PostgresDB) CheckCreateTable(ctx context.Context) error {
+	if _, err := p.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS nfsbench_doctor_check (id INTEGER)"); err != nil {
 		return err
 	}
-	defer stmt.Close()
+	_, err := p.db.ExecContext(ctx, "DROP TABLE nfsbench_doctor_check")
+	return err
+}
+
+// IndexableColumns lists the benchmark table columns that can carry a secondary index
+var IndexableColumns = []string{"data_int", "data_timestamp"}
+
+// EnsureIndexes creates B-tree indexes on the given columns and drops any other
+// secondary benchmark indexes, so each scenario starts with exactly the indexes it asked for
+func (p *PostgresDB) EnsureIndexes(ctx context.Context, columns []string) error {
+	wanted := make(map[string]bool)
+	for _, column := range columns {
+		if !isIndexableColumn(column) {
+			return fmt.Errorf("column %q cannot be indexed (supported: %s)", column, strings.Join(IndexableColumns, ", "))
+		}
+		wanted[column] = true
+	}
+
+	for _, column := range IndexableColumns {
+		indexName := fmt.Sprintf("%s_%s_idx", p.tableName(), column)
+		var query string
+		if wanted[column] {
+			query = fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)", indexName, p.table, column)
+		} else {
+			query = fmt.Sprintf("DROP INDEX IF EXISTS %s", p.inTableSchema(indexName))
+		}
+		if _, err := p.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to update index %s: %w", indexName, err)
+		}
+	}
+
+	return nil
+}
+
+func isIndexableColumn(column string) bool {
+	for _, c := range IndexableColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// InsertBatch inserts a batch of records on one connection, committing every rowsPerCommit
+// rows (or once for the whole batch when rowsPerCommit is 0), so the per-commit fsync cost
+// can be varied independently of batch size. The returned timing separates waiting for a
+// pooled connection from executing the batch, so pool contention isn't mistaken for
+// storage latency, and splits execution into writing the rows and committing them. The
+// commit is where the WAL flush, and so the NFS round trip, happens; the inserts are
+// mostly buffered locally. When a transaction fails, the timing still counts the rows
+// of those committed before it.
+func (p *PostgresDB) InsertBatch(ctx context.Context, batch []BenchmarkRecord, rowsPerCommit int) (BatchTiming, error) {
+	return p.insertBatch(ctx, batch, rowsPerCommit, insertRows(p.table, false, p.config.Pooler == ""), nil)
+}
+
+// InsertBatchIDs inserts a batch like InsertBatch, also returning the ids of the rows of
+// every transaction that committed, even when a later one failed. Each insert returns
+// its row's id, which adds a little work to the write phase.
+func (p *PostgresDB) InsertBatchIDs(ctx context.Context, batch []BenchmarkRecord, rowsPerCommit int) ([]int64, BatchTiming, error) {
+	ids := make([]int64, 0, len(batch))
+	timing, err := p.insertBatch(ctx, batch, rowsPerCommit, insertRows(p.table, true, p.config.Pooler == ""), &ids)
+	return ids, timing, err
+}
+
+// CopyBatch loads a batch like InsertBatch, but streams each transaction's rows with
+// COPY FROM STDIN instead of executing an INSERT per row, so the write phase costs one
+// round trip per transaction rather than one per row
+func (p *PostgresDB) CopyBatch(ctx context.Context, batch []BenchmarkRecord, rowsPerCommit int) (BatchTiming, error) {
+	return p.insertBatch(ctx, batch, rowsPerCommit, copyRows(p.table), nil)
+}
+
+// rowWriter writes records within a transaction, returning their ids if it learns them
+type rowWriter func(ctx context.Context, tx *sql.Tx, batch []BenchmarkRecord) ([]int64, error)
+
+// insertRows writes records into table with an INSERT per row, returning each row's id
+// if returnIDs is set. The INSERT is prepared once per transaction, unless prepared is
+// false, as through a pooler, which may not keep named statements.
+func insertRows(table string, returnIDs, prepared bool) rowWriter {
+	query := "INSERT INTO " + table + " (data_text, data_int, data_json) VALUES ($1, $2, $3)"
+	if returnIDs {
+		query += " RETURNING id"
+	}
+	return func(ctx context.Context, tx *sql.Tx, batch []BenchmarkRecord) ([]int64, error) {
+		exec, queryRow := tx.ExecContext, tx.QueryRowContext
+		if prepared {
+			stmt, err := tx.PrepareContext(ctx, query)
+			if err != nil {
+				return nil, err
+			}
+			defer stmt.Close()
+			exec = func(ctx context.Context, _ string, args ...interface{}) (sql.Result, error) {
+				return stmt.ExecContext(ctx, args...)
+			}
+			queryRow = func(ctx context.Context, _ string, args ...interface{}) *sql.Row {
+				return stmt.QueryRowContext(ctx, args...)
+			}
+		}
+
+		var ids []int64
+		var err error
+		for _, record := range batch {
+			if !returnIDs {
+				_, err = exec(ctx, query, record.Text, record.Number, record.JSON)
+			} else {
+				var id int64
+				err = queryRow(ctx, query, record.Text, record.Number, record.JSON).Scan(&id)
+				ids = append(ids, id)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return ids, nil
+	}
+}
 
-	for _, record := range batch {
-		_, err := stmt.Exec(record.Text, record.Number, record.JSON)
+// copyRows writes records into table with COPY FROM STDIN. The driver buffers the rows
+// and sends them when the final Exec ends the COPY.
+func copyRows(table string) rowWriter {
+	statement := pq.CopyIn(table, "data_text", "data_int", "data_json")
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		statement = pq.CopyInSchema(schema, name, "data_text", "data_int", "data_json")
+	}
+	return func(ctx context.Context, tx *sql.Tx, batch []BenchmarkRecord) ([]int64, error) {
+		stmt, err := tx.PrepareContext(ctx, statement)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		defer stmt.Close()
+
+		for _, record := range batch {
+			if _, err := stmt.ExecContext(ctx, record.Text, record.Number, record.JSON); err != nil {
+				return nil, err
+			}
+		}
+		_, err = stmt.ExecContext(ctx)
+		return nil, err
+	}
+}
+
+// insertBatch implements InsertBatch, InsertBatchIDs, and CopyBatch, writing each
+// transaction's rows with write and appending the ids of committed rows to ids unless
+// it is nil
+func (p *PostgresDB) insertBatch(ctx context.Context, batch []BenchmarkRecord, rowsPerCommit int, write rowWriter, ids *[]int64) (BatchTiming, error) {
+	var timing BatchTiming
+
+	start := time.Now()
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return timing, classify(err)
+	}
+	defer conn.Close()
+	timing.Acquire = time.Since(start)
+
+	if rowsPerCommit <= 0 {
+		rowsPerCommit = len(batch)
+	}
+
+	start = time.Now()
+	for len(batch) > 0 {
+		n := rowsPerCommit
+		if n > len(batch) {
+			n = len(batch)
+		}
+		committed, txErr := p.insertTransaction(ctx, conn, batch[:n], &timing, write)
+		if txErr != nil {
+			err = txErr
+			break
+		}
+		if ids != nil {
+			*ids = append(*ids, committed...)
+		}
+		timing.Committed += n
+		batch = batch[n:]
+	}
+	timing.Execute = time.Since(start)
+	return timing, classify(err)
+}
+
+// insertTransaction writes records in a single transaction on conn, adding the time
+// spent writing and committing them to timing, and returns the ids write returned once
+// they are committed
+func (p *PostgresDB) insertTransaction(ctx context.Context, conn *sql.Conn, batch []BenchmarkRecord, timing *BatchTiming, write rowWriter) ([]int64, error) {
+	start := time.Now()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids, err := write(ctx, tx, batch)
+	if err != nil {
+		return nil, err
+	}
+	timing.Write += time.Since(start)
+
+	start = time.Now()
+	err = tx.Commit()
+	timing.Commit += time.Since(start)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// RecordIDRange returns the lowest and highest id in the benchmark table, or 0 and 0
+// when it is empty
+func (p *PostgresDB) RecordIDRange(ctx context.Context) (min, max int64, err error) {
+	err = p.db.QueryRowContext(ctx, "SELECT COALESCE(MIN(id), 0), COALESCE(MAX(id), 0) FROM "+p.table).Scan(&min, &max)
+	return min, max, classify(err)
+}
+
+// ReadRecords reads the records with ids from fromID to fromID+count-1, transferring
+// every column, and returns how many it found
+func (p *PostgresDB) ReadRecords(ctx context.Context, fromID int64, count int) (int, error) {
+	rows, err := p.db.QueryContext(ctx, "SELECT data_text, data_int, data_json FROM "+p.table+" WHERE id >= $1 AND id < $2", fromID, fromID+int64(count))
+	if err != nil {
+		return 0, classify(err)
+	}
+	defer rows.Close()
+
+	n := 0
+	var text, json sql.NullString
+	var number sql.NullInt64
+	for rows.Next() {
+		if err := rows.Scan(&text, &number, &json); err != nil {
+			return n, classify(err)
 		}
+		n++
 	}
+	return n, classify(rows.Err())
+}
 
-	return tx.Commit()
+// MissingRecordIDs returns the ids, out of the given ones, that no row of the benchmark
+// table has
+func (p *PostgresDB) MissingRecordIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	rows, err := p.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT want.id FROM unnest($1::bigint[]) AS want(id)
+		WHERE NOT EXISTS (SELECT 1 FROM %s AS t WHERE t.id = want.id)
+		ORDER BY want.id
+	`, p.table), pq.Array(ids))
+	if err != nil {
+		return nil, classify(err)
+	}
+	defer rows.Close()
+
+	var missing []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return missing, classify(err)
+		}
+		missing = append(missing, id)
+	}
+	return missing, classify(rows.Err())
 }
 
 // CountRecords returns the total number of records in the benchmark table
-func (p *PostgresDB) CountRecords() (int, error) {
+func (p *PostgresDB) CountRecords(ctx context.Context) (int, error) {
 	var count int
-	err := p.db.QueryRow("SELECT COUNT(*) FROM benchmark_data").Scan(&count)
-	return count, err
+	err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+p.table).Scan(&count)
+	return count, classify(err)
+}
+
+// ChecksumRecords computes the RecordChecksum of every row in the benchmark table
+func (p *PostgresDB) ChecksumRecords(ctx context.Context) (RecordChecksum, error) {
+	var checksum RecordChecksum
+	err := p.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(('x' || substr(md5(data_text || ':' || data_int), 1, 7))::bit(28)::bigint), 0)
+		FROM `+p.table).Scan(&checksum.Rows, &checksum.Sum)
+	return checksum, classify(err)
+}
+
+// DataDirectory returns the server's data directory. It requires superuser or
+// pg_read_all_settings privileges.
+func (p *PostgresDB) DataDirectory() (string, error) {
+	var dir string
+	err := p.db.QueryRow("SHOW data_directory").Scan(&dir)
+	return dir, err
+}
+
+// ReadServerFile reads a file on the database server host. It requires superuser or
+// pg_read_server_files privileges.
+func (p *PostgresDB) ReadServerFile(path string) (string, error) {
+	var contents string
+	err := p.db.QueryRow("SELECT pg_read_file($1)", path).Scan(&contents)
+	return contents, err
+}
+
+// Table returns the benchmark table, schema-qualified if configured so
+func (p *PostgresDB) Table() string {
+	return p.table
 }
 
 // GetName returns the database connection name
@@ -105,8 +732,29 @@ func (p *PostgresDB) GetName() string {
 	return p.name
 }
 
+// MarkPoolStats starts the window that GetStats reports connection pool waits over,
+// so waits during setup are not counted against the measured run. With a PgBouncer
+// admin console configured, it also snapshots the pooler's own wait counters.
+func (p *PostgresDB) MarkPoolStats(ctx context.Context) {
+	p.poolBaseline = p.db.Stats()
+
+	p.poolerBaseline = nil
+	if p.config.PoolerAdminDSN == "" {
+		if p.config.Pooler != "" {
+			slog.Warn("No pooler_admin_dsn; time queued in the pooler is counted as execution latency", "database", p.name, "pooler", p.config.Pooler)
+		}
+		return
+	}
+	stats, err := queryPgBouncerStats(ctx, p.config.PoolerAdminDSN, p.config.Database)
+	if err != nil {
+		slog.Warn("PgBouncer statistics unavailable", "database", p.name, "error", err)
+		return
+	}
+	p.poolerBaseline = stats
+}
+
 // GetStats returns database statistics
-func (p *PostgresDB) GetStats() (map[string]interface{}, error) {
+func (p *PostgresDB) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 	
 	// Get connection stats
@@ -116,15 +764,85 @@ func (p *PostgresDB) GetStats() (map[string]interface{}, error) {
 	stats["in_use"] = dbStats.InUse
 	stats["idle"] = dbStats.Idle
 
+	// Waits for a free pooled connection since MarkPoolStats; many waits mean the pool,
+	// not storage, is limiting throughput
+	waitCount := dbStats.WaitCount - p.poolBaseline.WaitCount
+	waitDuration := dbStats.WaitDuration - p.poolBaseline.WaitDuration
+	stats["wait_count"] = waitCount
+	stats["wait_duration_ms"] = float64(waitDuration) / float64(time.Millisecond)
+	if waitCount > 0 {
+		stats["wait_avg_ms"] = float64(waitDuration) / float64(time.Millisecond) / float64(waitCount)
+	}
+
 	// Get table size
 	var tableSize int64
-	err := p.db.QueryRow(`
-		SELECT pg_total_relation_size('benchmark_data')
-	`).Scan(&tableSize)
+	// Summed over the partition tree, since a partitioned table has no storage of its own
+	err := p.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(pg_total_relation_size(relid)), 0) FROM pg_partition_tree($1)
+	`, p.table).Scan(&tableSize)
 	if err != nil {
 		tableSize = 0
 	}
 	stats["table_size_bytes"] = tableSize
 
+	// Get size of all indexes, including the primary key
+	var indexSize int64
+	err = p.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(pg_indexes_size(relid)), 0) FROM pg_partition_tree($1)
+	`, p.table).Scan(&indexSize)
+	if err != nil {
+		indexSize = 0
+	}
+	stats["index_size_bytes"] = indexSize
+
+	// Time spent queued in the pooler for a server connection, which would otherwise be
+	// indistinguishable from storage latency
+	if p.config.Pooler != "" {
+		stats["pooler"] = p.config.Pooler
+	}
+	if p.poolerBaseline != nil {
+		if current, err := queryPgBouncerStats(ctx, p.config.PoolerAdminDSN, p.config.Database); err == nil {
+			delta := current.since(p.poolerBaseline)
+			waitMs := float64(delta["total_wait_time"]) / 1000
+			stats["pooler_wait_time_ms"] = waitMs
+			stats["pooler_xact_count"] = delta["total_xact_count"]
+			if delta["total_xact_count"] > 0 {
+				stats["pooler_avg_wait_ms"] = waitMs / float64(delta["total_xact_count"])
+			}
+		}
+	}
+
+	// Size of each index, to see which one costs the most to maintain
+	if indexSizes, err := p.GetIndexStats(ctx); err == nil {
+		stats["index_sizes_bytes"] = indexSizes
+	}
+
 	return stats, nil
 }
+
+// GetIndexStats returns the size in bytes of every index on the benchmark table, keyed
+// by index name. A partitioned table lists the indexes of each partition.
+func (p *PostgresDB) GetIndexStats(ctx context.Context) (map[string]int64, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT i.indexname, pg_relation_size(format('%I.%I', i.schemaname, i.indexname)::regclass)
+		FROM pg_indexes i
+		JOIN pg_class c ON c.relname = i.tablename
+		JOIN pg_namespace n ON n.oid = c.relnamespace AND n.nspname = i.schemaname
+		WHERE c.oid IN (SELECT relid FROM pg_partition_tree($1))
+	`, p.table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sizes := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var size int64
+		if err := rows.Scan(&name, &size); err != nil {
+			return nil, err
+		}
+		sizes[name] = size
+	}
+	return sizes, rows.Err()
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/l22io/nfsvsdirectbench/internal/config"
+)
+
+func TestBuildPostgresDSNConnectTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		dsn     string
+		timeout int
+		want    string
+	}{
+		{"host=pgbouncer port=6432", 5, "host=pgbouncer port=6432 connect_timeout=5"},
+		{"host=pgbouncer connect_timeout=3", 5, "host=pgbouncer connect_timeout=3"},
+		{"host=pgbouncer connect_timeout = 3", 5, "host=pgbouncer connect_timeout = 3"},
+		{"host=pgbouncer", 0, "host=pgbouncer"},
+		{"postgres://user@pgbouncer:6432/db", 5, "postgres://user@pgbouncer:6432/db?connect_timeout=5"},
+		{"postgresql://user@pgbouncer/db?sslmode=require", 5, "postgresql://user@pgbouncer/db?connect_timeout=5&sslmode=require"},
+		{"postgres://user@pgbouncer/db?connect_timeout=3", 5, "postgres://user@pgbouncer/db?connect_timeout=3"},
+	} {
+		cfg := config.DatabaseConnectionConfig{DSN: tc.dsn, ConnectTimeout: tc.timeout}
+		if got := buildPostgresDSN(cfg); got != tc.want {
+			t.Errorf("dsn %q with connect_timeout %d: expected %q, got %q", tc.dsn, tc.timeout, tc.want, got)
+		}
+	}
+}
+
+func TestBuildPostgresDSNPgBouncer(t *testing.T) {
+	for _, tc := range []struct {
+		cfg  config.DatabaseConnectionConfig
+		want string
+	}{
+		{
+			config.DatabaseConnectionConfig{DSN: "host=pgbouncer port=6432", Pooler: PoolerPgBouncer},
+			"host=pgbouncer port=6432 sslmode=disable binary_parameters=yes",
+		},
+		{
+			config.DatabaseConnectionConfig{DSN: "host=pgbouncer sslmode=require", Pooler: PoolerPgBouncer, SSLMode: "verify-full"},
+			"host=pgbouncer sslmode=require binary_parameters=yes",
+		},
+		{
+			config.DatabaseConnectionConfig{DSN: "postgres://user@pgbouncer:6432/db", Pooler: PoolerPgBouncer, SSLMode: "require"},
+			"postgres://user@pgbouncer:6432/db?binary_parameters=yes&sslmode=require",
+		},
+		{
+			// Without a pooler the driver's default applies unless ssl_mode is set
+			config.DatabaseConnectionConfig{DSN: "host=db"},
+			"host=db",
+		},
+		{
+			config.DatabaseConnectionConfig{DSN: "host=db", SSLMode: "verify-ca"},
+			"host=db sslmode=verify-ca",
+		},
+		{
+			config.DatabaseConnectionConfig{Host: "pgbouncer", Port: 6432, Username: "bench", Database: "db", Pooler: PoolerPgBouncer},
+			"host=pgbouncer port=6432 user=bench password='' dbname=db sslmode=disable binary_parameters=yes",
+		},
+	} {
+		if got := buildPostgresDSN(tc.cfg); got != tc.want {
+			t.Errorf("Expected %q, got %q", tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sort"
+
+	"github.com/lib/pq"
+)
+
+// sessionConnector opens PostgreSQL connections and applies the session settings to each
+// one before the pool hands it out, so every connection runs with them, including ones
+// opened mid-run to replace expired connections
+type sessionConnector struct {
+	driver.Connector
+	statements []string
+}
+
+func newSessionConnector(dsn string, settings map[string]string) (driver.Connector, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return connector, nil
+	}
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	statements := make([]string, len(names))
+	for i, name := range names {
+		// Names are validated with the config; values are quoted as literals
+		statements[i] = fmt.Sprintf("SET %s = %s", name, pq.QuoteLiteral(settings[name]))
+	}
+	return &sessionConnector{Connector: connector, statements: statements}, nil
+}
+
+// Connect opens a connection and runs the SET statements on it
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("driver connection does not support session settings")
+	}
+	for _, statement := range c.statements {
+		if _, err := execer.ExecContext(ctx, statement, nil); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to apply session setting (%s): %w", statement, err)
+		}
+	}
+	return conn, nil
+}
+
+// SessionSettings returns the values the server reports for the configured session
+// settings, as applied to the pool's connections, or nil if none are configured
+func (p *PostgresDB) SessionSettings(ctx context.Context) (map[string]string, error) {
+	if len(p.config.SessionSettings) == 0 {
+		return nil, nil
+	}
+	applied := make(map[string]string, len(p.config.SessionSettings))
+	for name := range p.config.SessionSettings {
+		var value string
+		if err := p.db.QueryRowContext(ctx, "SELECT current_setting($1)", name).Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to read setting %s: %w", name, err)
+		}
+		applied[name] = value
+	}
+	return applied, nil
+}
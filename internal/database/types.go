@@ -1,9 +1,11 @@
 package database
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,14 +16,34 @@ type BenchmarkRecord struct {
 	JSON   string
 }
 
+// BatchTiming splits the latency of an InsertBatch call into its phases, and counts the
+// rows it committed
+type BatchTiming struct {
+	Acquire   time.Duration // waiting for a connection from the pool
+	Execute   time.Duration // running the transactions, including commits
+	Write     time.Duration // part of Execute spent beginning transactions and executing the inserts
+	Commit    time.Duration // part of Execute spent in COMMIT, where the WAL is flushed to storage
+	Committed int           // leading rows of the batch committed, fewer than all of them when a later transaction failed
+}
+
 // Database interface for database operations
 type Database interface {
-	CreateBenchmarkTable() error
-	ClearBenchmarkTable() error
-	InsertBatch(batch []BenchmarkRecord) error
-	CountRecords() (int, error)
+	CreateBenchmarkTable(ctx context.Context) error
+	ClearBenchmarkTable(ctx context.Context) error
+	ResetBenchmarkTable(ctx context.Context) error
+	DropBenchmarkTable(ctx context.Context) error
+	EnsureIndexes(ctx context.Context, columns []string) error
+	InsertBatch(ctx context.Context, batch []BenchmarkRecord, rowsPerCommit int) (BatchTiming, error)
+	InsertBatchIDs(ctx context.Context, batch []BenchmarkRecord, rowsPerCommit int) ([]int64, BatchTiming, error)
+	CopyBatch(ctx context.Context, batch []BenchmarkRecord, rowsPerCommit int) (BatchTiming, error)
+	CountRecords(ctx context.Context) (int, error)
+	RecordIDRange(ctx context.Context) (min, max int64, err error)
+	ReadRecords(ctx context.Context, fromID int64, count int) (int, error)
+	MissingRecordIDs(ctx context.Context, ids []int64) ([]int64, error)
+	ChecksumRecords(ctx context.Context) (RecordChecksum, error)
+	ConnectFresh(ctx context.Context) (time.Duration, error)
 	GetName() string
-	GetStats() (map[string]interface{}, error)
+	GetStats(ctx context.Context) (map[string]interface{}, error)
 	Close() error
 }
 
@@ -34,84 +56,212 @@ const (
 	RecordSizeLarge  RecordSize = "large"
 )
 
-// GenerateBenchmarkRecords creates a batch of benchmark records
-func GenerateBenchmarkRecords(count int, size RecordSize) []BenchmarkRecord {
+// Bytes returns the exact payload size for a record size given as a byte count
+// (e.g. "65536"). It returns false for the named presets.
+func (s RecordSize) Bytes() (int, bool) {
+	n, err := strconv.Atoi(string(s))
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// ApproxBytes returns the typical size of a generated record's text and JSON, for
+// estimating how much space a number of rows takes
+func (s RecordSize) ApproxBytes() int {
+	switch s {
+	case RecordSizeSmall:
+		return 100
+	case RecordSizeMedium:
+		return 500
+	case RecordSizeLarge:
+		return 1500
+	}
+	if n, ok := s.Bytes(); ok {
+		return n + 10
+	}
+	return 110
+}
+
+// Validate checks that the record size is a named preset or a positive byte count
+func (s RecordSize) Validate() error {
+	switch s {
+	case RecordSizeSmall, RecordSizeMedium, RecordSizeLarge:
+		return nil
+	}
+	if _, ok := s.Bytes(); ok {
+		return nil
+	}
+	return fmt.Errorf("invalid record size %q (valid: small, medium, large, or a positive byte count)", string(s))
+}
+
+// PayloadBytes returns the bytes of data_text and data_json in a batch of records: the
+// data an insert sends, leaving out the fixed-size columns and row overhead
+func PayloadBytes(batch []BenchmarkRecord) int64 {
+	var n int64
+	for _, record := range batch {
+		n += int64(len(record.Text) + len(record.JSON))
+	}
+	return n
+}
+
+// GenerateBenchmarkRecords creates a batch of benchmark records, drawing all random
+// content from rng so that the same seed yields the same records
+func GenerateBenchmarkRecords(rng *rand.Rand, count int, size RecordSize) []BenchmarkRecord {
 	records := make([]BenchmarkRecord, count)
-	
-	for i := 0; i < count; i++ {
-		records[i] = generateRecord(i, size)
+	g := recordGenerator{rng: rng, now: time.Now()}
+	for i := range records {
+		records[i] = g.record(i, size)
 	}
-	
 	return records
 }
 
-func generateRecord(id int, size RecordSize) BenchmarkRecord {
+// BatchPool is a fixed set of record batches generated up front. Inserting batches from
+// the pool instead of generating each one keeps record allocation, and the garbage
+// collection it causes, out of the measured loop, at the cost of inserting the same
+// content repeatedly.
+type BatchPool struct {
+	batches [][]BenchmarkRecord
+}
+
+// NewBatchPool generates batches batches of count records each
+func NewBatchPool(rng *rand.Rand, batches, count int, size RecordSize) *BatchPool {
+	p := &BatchPool{batches: make([][]BenchmarkRecord, batches)}
+	for i := range p.batches {
+		p.batches[i] = GenerateBenchmarkRecords(rng, count, size)
+	}
+	return p
+}
+
+// Batch returns the n-th batch, cycling through the pool. Batches are shared, so callers
+// must not modify them.
+func (p *BatchPool) Batch(n int) []BenchmarkRecord {
+	return p.batches[n%len(p.batches)]
+}
+
+// randomCharset is the alphabet of generated strings. None of its characters need
+// escaping in JSON, so generated strings are written into JSON documents as is.
+const randomCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 .,!?-"
+
+// recordGenerator generates the records of one batch. It writes each record's JSON
+// document directly into a reused buffer instead of building a map for json.Marshal,
+// which dominated generation time, so the harness keeps up with fast storage. The
+// documents are identical to what json.Marshal produced: keys in sorted order, and the
+// random content drawn from rng in the same order. Timestamps are taken once per batch.
+type recordGenerator struct {
+	rng     *rand.Rand
+	now     time.Time
+	buf     []byte
+	scratch []byte
+}
+
+func (g *recordGenerator) record(id int, size RecordSize) BenchmarkRecord {
 	var textSize int
-	var jsonData map[string]interface{}
-	
+	b := append(g.buf[:0], '{')
+
 	switch size {
 	case RecordSizeSmall:
-		textSize = 50 + rand.Intn(50)  // 50-100 chars
-		jsonData = map[string]interface{}{
-			"id": id,
-			"type": "small",
-		}
+		textSize = 50 + g.rng.Intn(50) // 50-100 chars
+		b = appendJSONInt(b, "id", int64(id))
+		b = appendJSONString(b, "type", "small")
 	case RecordSizeMedium:
-		textSize = 200 + rand.Intn(200) // 200-400 chars  
-		jsonData = map[string]interface{}{
-			"id": id,
-			"type": "medium",
-			"data": generateRandomString(100),
-			"timestamp": time.Now().Unix(),
-		}
+		textSize = 200 + g.rng.Intn(200) // 200-400 chars
+		b = appendJSONKey(b, "data")
+		b = g.appendQuotedRandom(b, 100)
+		b = appendJSONInt(b, "id", int64(id))
+		b = appendJSONInt(b, "timestamp", g.now.Unix())
+		b = appendJSONString(b, "type", "medium")
 	case RecordSizeLarge:
-		textSize = 500 + rand.Intn(500) // 500-1000 chars
-		jsonData = map[string]interface{}{
-			"id": id,
-			"type": "large",
-			"data": generateRandomString(200),
-			"metadata": map[string]interface{}{
-				"created": time.Now().Format(time.RFC3339),
-				"version": "1.0",
-				"tags": []string{"benchmark", "test", "large"},
-			},
-			"content": generateRandomString(300),
-		}
+		textSize = 500 + g.rng.Intn(500) // 500-1000 chars
+		// data is drawn before content, as the map literal this replaced evaluated them
+		data := g.appendQuotedRandom(g.scratch[:0], 200)
+		g.scratch = data
+		b = appendJSONKey(b, "content")
+		b = g.appendQuotedRandom(b, 300)
+		b = append(appendJSONKey(b, "data"), data...)
+		b = appendJSONInt(b, "id", int64(id))
+		b = append(appendJSONKey(b, "metadata"), '{')
+		b = appendJSONString(b, "created", g.now.Format(time.RFC3339))
+		b = append(appendJSONKey(b, "tags"), `["benchmark","test","large"]`...)
+		b = appendJSONString(b, "version", "1.0")
+		b = append(b, '}')
+		b = appendJSONString(b, "type", "large")
 	default:
 		textSize = 100
-		jsonData = map[string]interface{}{"id": id}
+		if n, ok := size.Bytes(); ok {
+			textSize = n // exact length so rows line up with rsize/wsize boundaries
+		}
+		b = appendJSONInt(b, "id", int64(id))
 	}
-	
-	jsonStr, _ := json.Marshal(jsonData)
-	
+	b = append(b, '}')
+	g.buf = b
+
 	return BenchmarkRecord{
-		Text:   generateRandomString(textSize),
-		Number: rand.Intn(1000000),
-		JSON:   string(jsonStr),
+		Text:   generateRandomString(g.rng, textSize),
+		Number: g.rng.Intn(1000000),
+		JSON:   string(b),
+	}
+}
+
+// appendQuotedRandom appends a random string of the given length as a JSON string
+func (g *recordGenerator) appendQuotedRandom(b []byte, length int) []byte {
+	b = append(b, '"')
+	for i := 0; i < length; i++ {
+		b = append(b, randomCharset[g.rng.Intn(len(randomCharset))])
+	}
+	return append(b, '"')
+}
+
+// appendJSONKey appends an object key, preceded by a comma unless it is the first
+func appendJSONKey(b []byte, key string) []byte {
+	if b[len(b)-1] != '{' {
+		b = append(b, ',')
 	}
+	b = append(b, '"')
+	b = append(b, key...)
+	return append(b, '"', ':')
+}
+
+func appendJSONInt(b []byte, key string, value int64) []byte {
+	return strconv.AppendInt(appendJSONKey(b, key), value, 10)
+}
+
+// appendJSONString appends a string member. The value must not need escaping.
+func appendJSONString(b []byte, key, value string) []byte {
+	b = append(appendJSONKey(b, key), '"')
+	b = append(b, value...)
+	return append(b, '"')
 }
 
-func generateRandomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 .,!?-"
-	result := make([]byte, length)
-	
-	for i := range result {
-		result[i] = charset[rand.Intn(len(charset))]
+func generateRandomString(rng *rand.Rand, length int) string {
+	var b strings.Builder
+	b.Grow(length)
+	for i := 0; i < length; i++ {
+		b.WriteByte(randomCharset[rng.Intn(len(randomCharset))])
 	}
-	
-	return string(result)
+	return b.String()
 }
 
-// FormatBytes formats byte counts into human readable format
+// FormatBytes formats byte counts into human readable format. Negative counts are
+// formatted by magnitude with a leading minus sign.
 func FormatBytes(bytes int64) string {
 	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
+	const prefixes = "KMGTPE"
+
+	sign := ""
+	magnitude := uint64(bytes)
+	if bytes < 0 {
+		sign = "-"
+		magnitude = -magnitude // two's complement, so math.MinInt64 works too
+	}
+
+	if magnitude < unit {
+		return fmt.Sprintf("%s%d B", sign, magnitude)
 	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
+	div, exp := uint64(unit), 0
+	for n := magnitude / unit; n >= unit && exp < len(prefixes)-1; n /= unit {
 		div *= unit
 		exp++
 	}
-	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+	return fmt.Sprintf("%s%.1f %cB", sign, float64(magnitude)/float64(div), prefixes[exp])
 }
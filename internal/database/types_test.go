@@ -0,0 +1,161 @@
+package database
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{1 << 50, "1.0 PB"},
+		{-1, "-1 B"},
+		{-2048, "-2.0 KB"},
+		{math.MaxInt64, "8.0 EB"},
+		{math.MinInt64, "-8.0 EB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.bytes); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
+
+func TestInnoDBStatsDelta(t *testing.T) {
+	before := map[string]int64{
+		"Innodb_buffer_pool_write_requests": 100,
+		"Innodb_data_fsyncs":                10,
+	}
+	after := map[string]int64{
+		"Innodb_buffer_pool_write_requests": 1100,
+		"Innodb_data_fsyncs":                25,
+		"Innodb_os_log_fsyncs":              7,
+	}
+
+	stats := InnoDBStatsDelta(before, after)
+
+	if got := stats["innodb_buffer_pool_write_requests"]; got != int64(1000) {
+		t.Errorf("innodb_buffer_pool_write_requests = %v, want 1000", got)
+	}
+	if got := stats["innodb_data_fsyncs"]; got != int64(15) {
+		t.Errorf("innodb_data_fsyncs = %v, want 15", got)
+	}
+	if _, ok := stats["innodb_os_log_fsyncs"]; ok {
+		t.Error("innodb_os_log_fsyncs should be omitted when missing from the first snapshot")
+	}
+}
+
+// marshalRecord generates a record the way the generator did before it wrote JSON
+// directly: by building a map and calling json.Marshal
+func marshalRecord(rng *rand.Rand, id int, size RecordSize, now time.Time) BenchmarkRecord {
+	var textSize int
+	var jsonData map[string]interface{}
+
+	switch size {
+	case RecordSizeSmall:
+		textSize = 50 + rng.Intn(50)
+		jsonData = map[string]interface{}{"id": id, "type": "small"}
+	case RecordSizeMedium:
+		textSize = 200 + rng.Intn(200)
+		jsonData = map[string]interface{}{
+			"id":        id,
+			"type":      "medium",
+			"data":      generateRandomString(rng, 100),
+			"timestamp": now.Unix(),
+		}
+	case RecordSizeLarge:
+		textSize = 500 + rng.Intn(500)
+		jsonData = map[string]interface{}{
+			"id":   id,
+			"type": "large",
+			"data": generateRandomString(rng, 200),
+			"metadata": map[string]interface{}{
+				"created": now.Format(time.RFC3339),
+				"version": "1.0",
+				"tags":    []string{"benchmark", "test", "large"},
+			},
+			"content": generateRandomString(rng, 300),
+		}
+	default:
+		textSize = 100
+		if n, ok := size.Bytes(); ok {
+			textSize = n
+		}
+		jsonData = map[string]interface{}{"id": id}
+	}
+
+	jsonStr, _ := json.Marshal(jsonData)
+	return BenchmarkRecord{
+		Text:   generateRandomString(rng, textSize),
+		Number: rng.Intn(1000000),
+		JSON:   string(jsonStr),
+	}
+}
+
+func TestGenerateRecordsMatchesMarshal(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	for _, size := range []RecordSize{RecordSizeSmall, RecordSizeMedium, RecordSizeLarge, "4096"} {
+		g := recordGenerator{rng: rand.New(rand.NewSource(42)), now: now}
+		rng := rand.New(rand.NewSource(42))
+		for i := 0; i < 50; i++ {
+			got, want := g.record(i, size), marshalRecord(rng, i, size, now)
+			if got != want {
+				t.Fatalf("%s record %d differs from json.Marshal:\n got %+v\nwant %+v", size, i, got, want)
+			}
+			if !json.Valid([]byte(got.JSON)) {
+				t.Fatalf("%s record %d has invalid JSON %s", size, i, got.JSON)
+			}
+		}
+	}
+}
+
+func BenchmarkGenerateRecords(b *testing.B) {
+	now := time.Now()
+	for _, size := range []RecordSize{RecordSizeSmall, RecordSizeMedium, RecordSizeLarge} {
+		b.Run(string(size)+"/direct", func(b *testing.B) {
+			g := recordGenerator{rng: rand.New(rand.NewSource(1)), now: now}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				g.record(i, size)
+			}
+		})
+		b.Run(string(size)+"/marshal", func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				marshalRecord(rng, i, size, now)
+			}
+		})
+	}
+}
+
+// BenchmarkInsertBatchSource compares the allocations of generating every insert batch
+// with cycling through a pregenerated pool, as pregenerate_batches does
+func BenchmarkInsertBatchSource(b *testing.B) {
+	const batchSize = 1000
+	b.Run("fresh", func(b *testing.B) {
+		rng := rand.New(rand.NewSource(1))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			GenerateBenchmarkRecords(rng, batchSize, RecordSizeMedium)
+		}
+	})
+	b.Run("pool", func(b *testing.B) {
+		pool := NewBatchPool(rand.New(rand.NewSource(1)), 16, batchSize, RecordSizeMedium)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			pool.Batch(i)
+		}
+	})
+}
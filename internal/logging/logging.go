@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Formats accepted by Setup
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ParseLevel converts a config log level (DEBUG, INFO, WARN/WARNING, ERROR; any case)
+// to an slog level
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "DEBUG":
+		return slog.LevelDebug, nil
+	case "", "INFO":
+		return slog.LevelInfo, nil
+	case "WARN", "WARNING":
+		return slog.LevelWarn, nil
+	case "ERROR":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q (valid: DEBUG, INFO, WARN, ERROR)", level)
+	}
+}
+
+// Setup installs the default slog logger writing to w at the given level, as text or
+// JSON lines. Messages from the standard log package are routed through it as well.
+func Setup(w io.Writer, level slog.Level, format string) error {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch format {
+	case "", FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("unknown log format %q (valid: text, json)", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
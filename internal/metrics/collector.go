@@ -1,7 +1,12 @@
 package metrics
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -13,15 +18,98 @@ type Collector struct {
 	startTime time.Time
 	endTime   time.Time
 	errors    []error
+	errorsByType map[string]int
+	timeouts  int // errors that were operations cancelled for running too long, see AddTimeout
 	throughput int64
+	bytes     int64         // payload bytes written, see AddBytes
+	merged    time.Duration // measurement time contributed by merged collectors
+	histogram *Histogram    // when set, latencies are recorded here instead of in the slice
+	percentiles []float64
+	phases    map[string]*Histogram // latencies of parts of an operation, by phase name
+	timeline  *timeline             // when set, per-interval percentiles are recorded too
+	timelineSink TimelineSink       // when set, receives the timeline's intervals as they close
+	stopTicks chan struct{}         // closed by End to stop closing timeline intervals on a ticker
+	failed    *Histogram            // when set, latencies of failed attempts, see AddFailedLatency
+	failedTime time.Duration        // total time of failed attempts
+}
+
+// DefaultPercentiles are reported in Results.Percentiles when none are configured
+var DefaultPercentiles = []float64{50, 90, 95, 99, 99.9}
+
+// Option configures a Collector
+type Option func(*Collector)
+
+// WithHistogram records latencies into a fixed-size Histogram instead of keeping every
+// sample, bounding memory on long runs at the cost of ~0.1% percentile resolution
+func WithHistogram() Option {
+	return func(c *Collector) {
+		c.histogram = NewHistogram()
+	}
+}
+
+// WithTimeline records the latency percentiles of every interval of the measurement in
+// Results.Timeline, in addition to the percentiles of the whole measurement
+func WithTimeline(interval time.Duration) Option {
+	return func(c *Collector) {
+		if interval > 0 {
+			c.timeline = newTimeline(interval)
+		}
+	}
+}
+
+// WithFailedLatency records the latencies of failed attempts passed to AddFailedLatency
+// in Results.Failed; without it they are discarded
+func WithFailedLatency() Option {
+	return func(c *Collector) {
+		c.failed = NewHistogram()
+	}
+}
+
+// TimelineSink receives a timeline interval as soon as it closes, with the wall clock
+// time the interval started. It is called with the collector locked, so it must not
+// call back into the collector.
+type TimelineSink func(start time.Time, bucket TimelineBucket)
+
+// WithTimelineSink passes every interval of the timeline to sink as it closes, rather
+// than only reporting the intervals in Results at the end. It needs WithTimeline.
+// Between Start and End intervals are closed on a ticker, so sink also receives the
+// intervals in which no operation completed while they happen.
+func WithTimelineSink(sink TimelineSink) Option {
+	return func(c *Collector) {
+		c.timelineSink = sink
+	}
+}
+
+// maxTopErrors limits how many distinct error messages are reported in Results
+const maxTopErrors = 5
+
+// WithPercentiles sets the percentiles reported in Results.Percentiles
+func WithPercentiles(percentiles []float64) Option {
+	return func(c *Collector) {
+		if len(percentiles) > 0 {
+			c.percentiles = append([]float64(nil), percentiles...)
+		}
+	}
 }
 
 // NewCollector creates a new metrics collector
-func NewCollector() *Collector {
-	return &Collector{
+func NewCollector(opts ...Option) *Collector {
+	c := &Collector{
 		latencies: make([]time.Duration, 0),
 		errors:    make([]error, 0),
+		errorsByType: make(map[string]int),
+		percentiles:  DefaultPercentiles,
+		phases:       make(map[string]*Histogram),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.timeline != nil && c.timelineSink != nil {
+		c.timeline.sink = func(bucket TimelineBucket) {
+			c.timelineSink(c.startTime.Add(bucket.Start), bucket)
+		}
 	}
+	return c
 }
 
 // Start marks the beginning of measurement
@@ -29,27 +117,179 @@ func (c *Collector) Start() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.startTime = time.Now()
+	if c.timeline != nil && c.timeline.sink != nil && c.stopTicks == nil {
+		c.stopTicks = make(chan struct{})
+		go c.closeIntervals(c.stopTicks)
+	}
 }
 
-// End marks the end of measurement
+// closeIntervals closes the timeline's intervals as they end until stop is closed,
+// rather than when the next operation completes
+func (c *Collector) closeIntervals(stop chan struct{}) {
+	ticker := time.NewTicker(c.timeline.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			if !c.startTime.IsZero() && c.endTime.IsZero() {
+				c.timeline.advance(time.Since(c.startTime), c.percentiles)
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// End marks the end of measurement, closing the timeline's last interval
 func (c *Collector) End() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.endTime = time.Now()
+	if c.stopTicks != nil {
+		close(c.stopTicks)
+		c.stopTicks = nil
+	}
+	if c.timeline != nil {
+		if !c.startTime.IsZero() {
+			c.timeline.advance(c.endTime.Sub(c.startTime), c.percentiles)
+		}
+		c.timeline.flush(c.percentiles)
+	}
+}
+
+// Reset discards all recorded latencies, errors, throughput, and timestamps so the
+// collector can be reused for another measurement. Its recorder and configured
+// percentiles are kept, and the latency slice keeps its capacity.
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.latencies = c.latencies[:0]
+	c.errors = c.errors[:0]
+	c.errorsByType = make(map[string]int)
+	c.timeouts = 0
+	c.throughput = 0
+	c.bytes = 0
+	c.merged = 0
+	c.startTime = time.Time{}
+	c.endTime = time.Time{}
+	if c.stopTicks != nil {
+		close(c.stopTicks)
+		c.stopTicks = nil
+	}
+	if c.histogram != nil {
+		c.histogram.Reset()
+	}
+	c.phases = make(map[string]*Histogram)
+	if c.failed != nil {
+		c.failed.Reset()
+	}
+	c.failedTime = 0
+	if c.timeline != nil {
+		c.timeline.reset()
+	}
 }
 
 // AddLatency records a latency measurement
 func (c *Collector) AddLatency(latency time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if c.timeline != nil {
+		c.timeline.record(time.Since(c.startTime), latency, c.percentiles)
+	}
+	if c.histogram != nil {
+		c.histogram.Record(latency)
+		return
+	}
 	c.latencies = append(c.latencies, latency)
 }
 
+// AddPhaseLatency records the latency of one phase of an operation, such as waiting
+// for a pooled connection, in its own stream alongside the operation's total latency.
+// Phases are always recorded in histograms.
+func (c *Collector) AddPhaseLatency(phase string, latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := c.phases[phase]
+	if !ok {
+		h = NewHistogram()
+		c.phases[phase] = h
+	}
+	h.Record(latency)
+}
+
+// latencyCount returns the number of recorded latencies; callers must hold the lock
+func (c *Collector) latencyCount() int64 {
+	if c.histogram != nil {
+		return c.histogram.Count()
+	}
+	return int64(len(c.latencies))
+}
+
+// Samples returns the recorded latencies in recording order. When more than limit were
+// recorded (and limit > 0), a uniform random subset of limit samples is returned, still in
+// recording order; the subset is the same for the same recorded data. Histogram
+// collectors keep no individual samples and return nil.
+func (c *Collector) Samples(limit int) []time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.histogram != nil {
+		return nil
+	}
+	if limit <= 0 || len(c.latencies) <= limit {
+		return append([]time.Duration(nil), c.latencies...)
+	}
+
+	rng := rand.New(rand.NewSource(int64(len(c.latencies))))
+	indexes := rng.Perm(len(c.latencies))[:limit]
+	sort.Ints(indexes)
+
+	samples := make([]time.Duration, limit)
+	for i, idx := range indexes {
+		samples[i] = c.latencies[idx]
+	}
+	return samples
+}
+
+// AddFailedLatency records the time an attempt took before it failed, in a histogram of
+// its own, so that time spent on operations that had to be retried shows up in
+// Results.Failed without affecting the latencies of successful operations. Only
+// collectors created WithFailedLatency, which the runner passes when
+// metrics.failed_latency is set, record them; others discard the latency.
+func (c *Collector) AddFailedLatency(latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failed == nil {
+		return
+	}
+	c.failed.Record(latency)
+	c.failedTime += latency
+}
+
 // AddError records an error
 func (c *Collector) AddError(err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.errors = append(c.errors, err)
+	c.errorsByType[err.Error()]++
+}
+
+// AddTimeout counts an error recorded with AddError as a timeout: an operation
+// cancelled because it ran longer than the statement timeout
+func (c *Collector) AddTimeout() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timeouts++
+}
+
+// AddBytes adds to the payload bytes the measured operations wrote, which
+// Results.BytesPerSecond divides by the measured time
+func (c *Collector) AddBytes(n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bytes += n
 }
 
 // SetThroughput sets the total throughput (operations completed)
@@ -59,51 +299,339 @@ func (c *Collector) SetThroughput(ops int64) {
 	c.throughput = ops
 }
 
+// Snapshot is a point-in-time view of an in-progress measurement
+type Snapshot struct {
+	Elapsed    time.Duration
+	Operations int64
+	Errors     int
+	P50Latency time.Duration
+	P95Latency time.Duration
+	P99Latency time.Duration
+	P999Latency time.Duration
+}
+
+// Attempts returns the successful operations and errors recorded so far. Unlike
+// Snapshot it does not compute percentiles, so it is cheap to call often.
+func (c *Collector) Attempts() (operations int64, errors int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.latencyCount(), len(c.errors)
+}
+
+// Snapshot returns the measurements collected so far without ending the measurement
+func (c *Collector) Snapshot() Snapshot {
+	c.mu.RLock()
+	snapshot := Snapshot{
+		Elapsed:    time.Since(c.startTime),
+		Operations: c.latencyCount(),
+		Errors:     len(c.errors),
+	}
+	if c.histogram != nil {
+		snapshot.P50Latency = c.histogram.Percentile(50)
+		snapshot.P95Latency = c.histogram.Percentile(95)
+		snapshot.P99Latency = c.histogram.Percentile(99)
+		snapshot.P999Latency = c.histogram.Percentile(99.9)
+		c.mu.RUnlock()
+		return snapshot
+	}
+	sorted := make([]time.Duration, len(c.latencies))
+	copy(sorted, c.latencies)
+	c.mu.RUnlock()
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	snapshot.P50Latency = calculatePercentile(sorted, 50)
+	snapshot.P95Latency = calculatePercentile(sorted, 95)
+	snapshot.P99Latency = calculatePercentile(sorted, 99)
+	snapshot.P999Latency = calculatePercentile(sorted, 99.9)
+
+	return snapshot
+}
+
+// Merge adds another collector's measurements to this one. The merged collector's
+// measurement window is added to this collector's total duration, so several short
+// windows (e.g. interleaved slices or repeats) aggregate into one result.
+func (c *Collector) Merge(other *Collector) {
+	other.mu.RLock()
+	latencies := append([]time.Duration(nil), other.latencies...)
+	var histogram *Histogram
+	if other.histogram != nil {
+		histogram = NewHistogram()
+		histogram.Merge(other.histogram)
+	}
+	errs := append([]error(nil), other.errors...)
+	timeouts := other.timeouts
+	throughput := other.throughput
+	bytes := other.bytes
+	elapsed := other.elapsed()
+	phases := make(map[string]*Histogram, len(other.phases))
+	for phase, h := range other.phases {
+		phases[phase] = NewHistogram()
+		phases[phase].Merge(h)
+	}
+	var failed *Histogram
+	if other.failed != nil {
+		failed = NewHistogram()
+		failed.Merge(other.failed)
+	}
+	failedTime := other.failedTime
+	var buckets []TimelineBucket
+	var interval time.Duration
+	if other.timeline != nil {
+		buckets = other.timeline.closed(other.percentiles)
+		interval = other.timeline.interval
+	}
+	other.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The other measurement's intervals follow this collector's measured time
+	if interval > 0 {
+		if c.timeline == nil {
+			c.timeline = newTimeline(interval)
+		}
+		c.timeline.flush(c.percentiles)
+		offset := c.elapsed()
+		for _, bucket := range buckets {
+			bucket.Start += offset
+			c.timeline.buckets = append(c.timeline.buckets, bucket)
+		}
+		c.timeline.offset = offset + elapsed
+	}
+
+	// Histogram data can't be expanded back into samples, so absorbing it
+	// switches this collector to histogram recording
+	if histogram != nil && c.histogram == nil {
+		c.histogram = NewHistogram()
+		for _, latency := range c.latencies {
+			c.histogram.Record(latency)
+		}
+		c.latencies = nil
+	}
+
+	if c.histogram != nil {
+		for _, latency := range latencies {
+			c.histogram.Record(latency)
+		}
+		if histogram != nil {
+			c.histogram.Merge(histogram)
+		}
+	} else {
+		c.latencies = append(c.latencies, latencies...)
+	}
+
+	for _, err := range errs {
+		c.errors = append(c.errors, err)
+		c.errorsByType[err.Error()]++
+	}
+	c.timeouts += timeouts
+	c.throughput += throughput
+	c.bytes += bytes
+	c.merged += elapsed
+	if failed != nil {
+		if c.failed == nil {
+			c.failed = NewHistogram()
+		}
+		c.failed.Merge(failed)
+	}
+	c.failedTime += failedTime
+	for phase, h := range phases {
+		if existing, ok := c.phases[phase]; ok {
+			existing.Merge(h)
+		} else {
+			c.phases[phase] = h
+		}
+	}
+}
+
+// elapsed returns the total measured time; callers must hold the lock
+func (c *Collector) elapsed() time.Duration {
+	return c.endTime.Sub(c.startTime) + c.merged
+}
+
 // Results returns the collected metrics
 func (c *Collector) Results() *Results {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if len(c.latencies) == 0 {
+	count := c.latencyCount()
+	if count == 0 {
 		return &Results{
-			TotalDuration: c.endTime.Sub(c.startTime),
+			TotalDuration: c.elapsed(),
 			ErrorCount:    len(c.errors),
+			Timeouts:      c.timeouts,
+			ErrorRate:     c.calculateErrorRate(),
+			TopErrors:     c.topErrors(),
 			Throughput:    c.throughput,
+			Bytes:         c.bytes,
+			Phases:        c.phaseResults(),
+			Timeline:      c.timelineResults(),
+			Failed:        c.failedResults(),
 		}
 	}
 
-	// Sort latencies for percentile calculation
-	sorted := make([]time.Duration, len(c.latencies))
-	copy(sorted, c.latencies)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i] < sorted[j]
-	})
+	var average, stddev, min, max time.Duration
+	var percentile func(float64) time.Duration
+	if c.histogram != nil {
+		average = c.histogram.Mean()
+		stddev = c.histogram.StdDev()
+		min = c.histogram.Min()
+		max = c.histogram.Max()
+		percentile = c.histogram.Percentile
+	} else {
+		// Sort latencies for percentile calculation
+		sorted := make([]time.Duration, len(c.latencies))
+		copy(sorted, c.latencies)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i] < sorted[j]
+		})
 
-	totalDuration := c.endTime.Sub(c.startTime)
+		average = c.calculateAverage(sorted)
+		stddev = c.calculateStdDev(sorted, average)
+		min = sorted[0]
+		max = sorted[len(sorted)-1]
+		percentile = func(p float64) time.Duration {
+			return calculatePercentile(sorted, p)
+		}
+	}
+
+	totalDuration := c.elapsed()
 	
 	results := &Results{
 		TotalDuration:    totalDuration,
-		TotalOperations:  int64(len(c.latencies)),
+		TotalOperations:  count,
 		Throughput:       c.throughput,
+		Bytes:            c.bytes,
 		ErrorCount:       len(c.errors),
-		AverageLatency:   c.calculateAverage(sorted),
-		P50Latency:      c.calculatePercentile(sorted, 50),
-		P90Latency:      c.calculatePercentile(sorted, 90),
-		P95Latency:      c.calculatePercentile(sorted, 95),
-		P99Latency:      c.calculatePercentile(sorted, 99),
-		P999Latency:     c.calculatePercentile(sorted, 99.9),
-		MinLatency:      sorted[0],
-		MaxLatency:      sorted[len(sorted)-1],
+		Timeouts:         c.timeouts,
+		ErrorRate:        c.calculateErrorRate(),
+		TopErrors:        c.topErrors(),
+		AverageLatency:   average,
+		StdDevLatency:    stddev,
+		P50Latency:      percentile(50),
+		P90Latency:      percentile(90),
+		P95Latency:      percentile(95),
+		P99Latency:      percentile(99),
+		P999Latency:     percentile(99.9),
+		MinLatency:      min,
+		MaxLatency:      max,
+		Percentiles:     make(PercentileMap, len(c.percentiles)),
+		Phases:          c.phaseResults(),
+		Timeline:        c.timelineResults(),
+		Failed:          c.failedResults(),
+	}
+	for _, p := range c.percentiles {
+		results.Percentiles[p] = percentile(p)
 	}
 
-	// Calculate operations per second
+	if average > 0 {
+		results.CoefficientOfVariation = float64(stddev) / float64(average)
+	}
+
+	// Calculate operations and bytes per second
 	if totalDuration.Seconds() > 0 {
 		results.OperationsPerSecond = float64(results.TotalOperations) / totalDuration.Seconds()
+		results.BytesPerSecond = float64(results.Bytes) / totalDuration.Seconds()
 	}
 
 	return results
 }
 
+// phaseResults summarizes the phase streams; callers must hold the lock
+func (c *Collector) phaseResults() map[string]PhaseLatency {
+	if len(c.phases) == 0 {
+		return nil
+	}
+	results := make(map[string]PhaseLatency, len(c.phases))
+	for phase, h := range c.phases {
+		results[phase] = PhaseLatency{
+			Count:          h.Count(),
+			AverageLatency: h.Mean(),
+			P50Latency:     h.Percentile(50),
+			P95Latency:     h.Percentile(95),
+			P99Latency:     h.Percentile(99),
+			MaxLatency:     h.Max(),
+		}
+	}
+	return results
+}
+
+// failedResults summarizes the failed attempts, if any were recorded; callers must hold the lock
+func (c *Collector) failedResults() *FailedLatency {
+	if c.failed == nil {
+		return nil
+	}
+	return &FailedLatency{
+		PhaseLatency: PhaseLatency{
+			Count:          c.failed.Count(),
+			AverageLatency: c.failed.Mean(),
+			P50Latency:     c.failed.Percentile(50),
+			P95Latency:     c.failed.Percentile(95),
+			P99Latency:     c.failed.Percentile(99),
+			MaxLatency:     c.failed.Max(),
+		},
+		TotalTime: c.failedTime,
+	}
+}
+
+// timelineResults returns the per-interval percentiles, if recorded; callers must hold the lock
+func (c *Collector) timelineResults() *Timeline {
+	if c.timeline == nil {
+		return nil
+	}
+	return &Timeline{
+		Interval:    c.timeline.interval,
+		Percentiles: append([]float64(nil), c.percentiles...),
+		Buckets:     c.timeline.closed(c.percentiles),
+	}
+}
+
+// calculateErrorRate returns failed attempts as a fraction of all attempts
+func (c *Collector) calculateErrorRate() float64 {
+	attempts := c.latencyCount() + int64(len(c.errors))
+	if attempts == 0 {
+		return 0
+	}
+	return float64(len(c.errors)) / float64(attempts)
+}
+
+// topErrors returns the most frequent distinct error messages, most common first
+func (c *Collector) topErrors() []ErrorCount {
+	if len(c.errorsByType) == 0 {
+		return nil
+	}
+
+	counts := make([]ErrorCount, 0, len(c.errorsByType))
+	for message, count := range c.errorsByType {
+		counts = append(counts, ErrorCount{Message: message, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Message < counts[j].Message
+	})
+
+	if len(counts) > maxTopErrors {
+		counts = counts[:maxTopErrors]
+	}
+	return counts
+}
+
+// ErrorsByType returns a copy of the error counts keyed by error message
+func (c *Collector) ErrorsByType() map[string]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	errorsByType := make(map[string]int, len(c.errorsByType))
+	for message, count := range c.errorsByType {
+		errorsByType[message] = count
+	}
+	return errorsByType
+}
+
 func (c *Collector) calculateAverage(latencies []time.Duration) time.Duration {
 	if len(latencies) == 0 {
 		return 0
@@ -117,35 +645,43 @@ func (c *Collector) calculateAverage(latencies []time.Duration) time.Duration {
 	return total / time.Duration(len(latencies))
 }
 
-func (c *Collector) calculatePercentile(sortedLatencies []time.Duration, percentile float64) time.Duration {
+// calculateStdDev returns the population standard deviation of latencies around mean
+func (c *Collector) calculateStdDev(latencies []time.Duration, mean time.Duration) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	var sumSquares float64
+	for _, lat := range latencies {
+		diff := float64(lat - mean)
+		sumSquares += diff * diff
+	}
+
+	return time.Duration(math.Sqrt(sumSquares / float64(len(latencies))))
+}
+
+// calculatePercentile returns the nearest-rank percentile: the smallest sample with at
+// least percentile% of the samples at or below it. The rank is rounded to tolerate
+// floating-point error first, so e.g. P99.9 of 10000 samples is the 9990th sample rather
+// than the 9991st because 99.9/100*10000 computes as 9990.000000000002. Timeline
+// intervals use it too, so their percentiles agree with the whole run's.
+func calculatePercentile(sortedLatencies []time.Duration, percentile float64) time.Duration {
 	if len(sortedLatencies) == 0 {
 		return 0
 	}
-	
-	if percentile <= 0 {
-		return sortedLatencies[0]
+
+	rank := percentile / 100 * float64(len(sortedLatencies))
+	if rounded := math.Round(rank); math.Abs(rank-rounded) < 1e-9*float64(len(sortedLatencies)) {
+		rank = rounded
 	}
-	
-	if percentile >= 100 {
-		return sortedLatencies[len(sortedLatencies)-1]
+	index := int(math.Ceil(rank)) - 1
+	if index < 0 {
+		index = 0
 	}
-	
-	index := (percentile / 100.0) * float64(len(sortedLatencies))
-	
-	if index == float64(int(index)) {
-		// Exact index
-		return sortedLatencies[int(index)-1]
-	} else {
-		// Interpolate between two values
-		lowerIndex := int(index)
-		upperIndex := lowerIndex + 1
-		
-		if upperIndex >= len(sortedLatencies) {
-			return sortedLatencies[len(sortedLatencies)-1]
-		}
-		
-		return sortedLatencies[lowerIndex]
+	if index >= len(sortedLatencies) {
+		index = len(sortedLatencies) - 1
 	}
+	return sortedLatencies[index]
 }
 
 // Results contains the collected benchmark metrics
@@ -154,8 +690,15 @@ type Results struct {
 	TotalOperations      int64         `json:"total_operations"`
 	Throughput          int64         `json:"throughput"`
 	OperationsPerSecond  float64       `json:"operations_per_second"`
+	Bytes               int64         `json:"bytes,omitempty"`            // payload bytes written, see Collector.AddBytes
+	BytesPerSecond      float64       `json:"bytes_per_second,omitempty"` // Bytes over TotalDuration
 	ErrorCount          int           `json:"error_count"`
+	Timeouts            int           `json:"timeouts,omitempty"` // errors that were timeouts, see Collector.AddTimeout
+	ErrorRate           float64       `json:"error_rate"`
+	TopErrors           []ErrorCount  `json:"top_errors,omitempty"`
 	AverageLatency      time.Duration `json:"average_latency"`
+	StdDevLatency       time.Duration `json:"stddev_latency"`
+	CoefficientOfVariation float64    `json:"coefficient_of_variation"` // stddev / mean
 	P50Latency          time.Duration `json:"p50_latency"`
 	P90Latency          time.Duration `json:"p90_latency"`
 	P95Latency          time.Duration `json:"p95_latency"`
@@ -163,6 +706,70 @@ type Results struct {
 	P999Latency         time.Duration `json:"p999_latency"`
 	MinLatency          time.Duration `json:"min_latency"`
 	MaxLatency          time.Duration `json:"max_latency"`
+	Percentiles         PercentileMap `json:"percentiles,omitempty"`
+	Phases              map[string]PhaseLatency `json:"phases,omitempty"` // see Collector.AddPhaseLatency
+	Timeline            *Timeline     `json:"timeline,omitempty"` // see WithTimeline
+	Failed              *FailedLatency `json:"failed,omitempty"`  // see Collector.AddFailedLatency
+}
+
+// PhaseLatency summarizes the latencies recorded for one phase of an operation
+type PhaseLatency struct {
+	Count          int64         `json:"count"`
+	AverageLatency time.Duration `json:"average_latency"`
+	P50Latency     time.Duration `json:"p50_latency"`
+	P95Latency     time.Duration `json:"p95_latency"`
+	P99Latency     time.Duration `json:"p99_latency"`
+	MaxLatency     time.Duration `json:"max_latency"`
+}
+
+// FailedLatency summarizes the latencies of failed attempts, with the total time they
+// took: time spent on operations that produced nothing and had to be retried
+type FailedLatency struct {
+	PhaseLatency
+	TotalTime time.Duration `json:"total_time"`
+}
+
+// PercentileMap maps a percentile (e.g. 99.99) to its latency. It is encoded in JSON
+// as an object keyed by the percentile's decimal string, e.g. {"99.99": 1234567}.
+type PercentileMap map[float64]time.Duration
+
+// MarshalJSON implements json.Marshaler
+func (m PercentileMap) MarshalJSON() ([]byte, error) {
+	encoded := make(map[string]time.Duration, len(m))
+	for p, latency := range m {
+		encoded[strconv.FormatFloat(p, 'f', -1, 64)] = latency
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (m *PercentileMap) UnmarshalJSON(data []byte) error {
+	var encoded map[string]time.Duration
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded := make(PercentileMap, len(encoded))
+	for key, latency := range encoded {
+		p, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return fmt.Errorf("invalid percentile %q: %w", key, err)
+		}
+		decoded[p] = latency
+	}
+	*m = decoded
+	return nil
+}
+
+// MegabytesPerSecond returns BytesPerSecond in MB/s (1 MB = 1024 KB, as FormatBytes
+// counts), the figure to hold against the NFS link's bandwidth
+func (r *Results) MegabytesPerSecond() float64 {
+	return r.BytesPerSecond / (1 << 20)
+}
+
+// ErrorCount is a distinct error message and how often it occurred
+type ErrorCount struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
 }
 
 // ToMap converts results to a map for easy serialization
@@ -172,8 +779,13 @@ func (r *Results) ToMap() map[string]interface{} {
 		"total_operations":      r.TotalOperations,
 		"throughput":           r.Throughput,
 		"operations_per_second": r.OperationsPerSecond,
+		"bytes_per_second":     r.BytesPerSecond,
 		"error_count":          r.ErrorCount,
+		"timeouts":             r.Timeouts,
+		"error_rate":           r.ErrorRate,
 		"average_latency_ms":   r.AverageLatency.Milliseconds(),
+		"stddev_latency_ms":    r.StdDevLatency.Milliseconds(),
+		"coefficient_of_variation": r.CoefficientOfVariation,
 		"p50_latency_ms":       r.P50Latency.Milliseconds(),
 		"p90_latency_ms":       r.P90Latency.Milliseconds(),
 		"p95_latency_ms":       r.P95Latency.Milliseconds(),
@@ -181,5 +793,14 @@ func (r *Results) ToMap() map[string]interface{} {
 		"p999_latency_ms":      r.P999Latency.Milliseconds(),
 		"min_latency_ms":       r.MinLatency.Milliseconds(),
 		"max_latency_ms":       r.MaxLatency.Milliseconds(),
+		"percentiles_ms":       r.percentilesMs(),
+	}
+}
+
+func (r *Results) percentilesMs() map[string]int64 {
+	percentiles := make(map[string]int64, len(r.Percentiles))
+	for p, latency := range r.Percentiles {
+		percentiles[strconv.FormatFloat(p, 'f', -1, 64)] = latency.Milliseconds()
 	}
+	return percentiles
 }
@@ -0,0 +1,405 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConfiguredPercentiles(t *testing.T) {
+	c := NewCollector(WithPercentiles([]float64{50, 99.99}))
+	c.Start()
+	for i := 1; i <= 10000; i++ {
+		c.AddLatency(time.Duration(i) * time.Microsecond)
+	}
+	c.End()
+
+	results := c.Results()
+	if len(results.Percentiles) != 2 {
+		t.Fatalf("Expected 2 percentiles, got %v", results.Percentiles)
+	}
+	if _, ok := results.Percentiles[99.99]; !ok {
+		t.Errorf("Expected P99.99 in results, got %v", results.Percentiles)
+	}
+
+	data, err := json.Marshal(results.Percentiles)
+	if err != nil {
+		t.Fatalf("Failed to marshal percentiles: %v", err)
+	}
+	var decoded PercentileMap
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal %s: %v", data, err)
+	}
+	if decoded[99.99] != results.Percentiles[99.99] {
+		t.Errorf("Expected P99.99 %v after round trip, got %v", results.Percentiles[99.99], decoded[99.99])
+	}
+}
+
+func TestStdDevAndCoefficientOfVariation(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithHistogram()}} {
+		c := NewCollector(opts...)
+		c.Start()
+		// Mean 10ms, population stddev 5ms
+		for _, ms := range []int{5, 15, 5, 15} {
+			c.AddLatency(time.Duration(ms) * time.Millisecond)
+		}
+		c.End()
+
+		results := c.Results()
+		if diff := results.StdDevLatency - 5*time.Millisecond; diff > 10*time.Microsecond || diff < -10*time.Microsecond {
+			t.Errorf("Expected stddev ~5ms, got %v", results.StdDevLatency)
+		}
+		if cv := results.CoefficientOfVariation; cv < 0.499 || cv > 0.501 {
+			t.Errorf("Expected CV ~0.5, got %v", cv)
+		}
+	}
+}
+
+func TestSamplesSubsetKeepsOrder(t *testing.T) {
+	c := NewCollector()
+	for i := 1; i <= 1000; i++ {
+		c.AddLatency(time.Duration(i))
+	}
+
+	if got := len(c.Samples(0)); got != 1000 {
+		t.Fatalf("Samples(0) returned %d samples, want all 1000", got)
+	}
+
+	samples := c.Samples(100)
+	if len(samples) != 100 {
+		t.Fatalf("Samples(100) returned %d samples", len(samples))
+	}
+	for i := 1; i < len(samples); i++ {
+		if samples[i] <= samples[i-1] {
+			t.Fatalf("samples out of recording order at %d: %v after %v", i, samples[i], samples[i-1])
+		}
+	}
+
+	again := c.Samples(100)
+	for i := range samples {
+		if samples[i] != again[i] {
+			t.Fatal("Samples is not deterministic for the same data")
+		}
+	}
+
+	if NewCollector(WithHistogram()).Samples(10) != nil {
+		t.Error("histogram collector returned samples")
+	}
+}
+
+func TestResetClearsResults(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithHistogram()}} {
+		c := NewCollector(opts...)
+		c.Start()
+		for i := 1; i <= 100; i++ {
+			c.AddLatency(time.Duration(i) * time.Millisecond)
+		}
+		c.AddError(errors.New("insert failed"))
+		c.SetThroughput(100)
+		c.End()
+
+		c.Reset()
+
+		results := c.Results()
+		if results.TotalOperations != 0 || results.ErrorCount != 0 || results.OperationsPerSecond != 0 {
+			t.Errorf("Expected empty results after reset, got %d ops, %d errors, %v ops/sec",
+				results.TotalOperations, results.ErrorCount, results.OperationsPerSecond)
+		}
+		if results.P99Latency != 0 || results.MaxLatency != 0 {
+			t.Errorf("Expected no latencies after reset, got p99 %v, max %v", results.P99Latency, results.MaxLatency)
+		}
+		if len(c.ErrorsByType()) != 0 {
+			t.Errorf("Expected no errors by type after reset, got %v", c.ErrorsByType())
+		}
+
+		// The collector records normally after a reset
+		c.Start()
+		c.AddLatency(7 * time.Millisecond)
+		c.SetThroughput(1)
+		c.End()
+		if got := c.Results().TotalOperations; got != 1 {
+			t.Errorf("Expected 1 operation after reuse, got %d", got)
+		}
+	}
+}
+
+func TestResetConcurrentWithAddLatency(t *testing.T) {
+	c := NewCollector()
+	c.Start()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					c.AddLatency(time.Millisecond)
+					c.AddError(errors.New("timeout"))
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		c.Reset()
+		c.Results()
+	}
+	close(stop)
+	wg.Wait()
+
+	c.Reset()
+	if results := c.Results(); results.ErrorCount != 0 || results.AverageLatency != 0 {
+		t.Errorf("Expected empty results after final reset, got %d errors, avg %v", results.ErrorCount, results.AverageLatency)
+	}
+}
+
+func TestPhaseLatenciesMerge(t *testing.T) {
+	a := NewCollector()
+	a.AddLatency(10 * time.Millisecond)
+	a.AddPhaseLatency("conn_acquire", time.Millisecond)
+	a.AddPhaseLatency("execute", 9*time.Millisecond)
+
+	b := NewCollector()
+	b.AddLatency(20 * time.Millisecond)
+	b.AddPhaseLatency("conn_acquire", 5*time.Millisecond)
+
+	a.Merge(b)
+	phases := a.Results().Phases
+	if got := phases["conn_acquire"].Count; got != 2 {
+		t.Errorf("Expected 2 conn_acquire samples, got %d", got)
+	}
+	if got := phases["execute"].Count; got != 1 {
+		t.Errorf("Expected 1 execute sample, got %d", got)
+	}
+	if max := phases["conn_acquire"].MaxLatency; max < 4990*time.Microsecond || max > 5010*time.Microsecond {
+		t.Errorf("Expected conn_acquire max ~5ms, got %v", max)
+	}
+}
+
+func TestFailedLatencies(t *testing.T) {
+	a := NewCollector(WithFailedLatency())
+	a.AddLatency(10 * time.Millisecond)
+	a.AddFailedLatency(30 * time.Millisecond)
+
+	b := NewCollector(WithFailedLatency())
+	b.AddFailedLatency(50 * time.Millisecond)
+
+	a.Merge(b)
+	results := a.Results()
+	if results.TotalOperations != 1 || results.MaxLatency != 10*time.Millisecond {
+		t.Errorf("Expected failed attempts to leave operation latencies alone, got %d ops, max %v", results.TotalOperations, results.MaxLatency)
+	}
+	if results.Failed == nil || results.Failed.Count != 2 || results.Failed.TotalTime != 80*time.Millisecond {
+		t.Fatalf("Expected 2 failed attempts totaling 80ms, got %+v", results.Failed)
+	}
+
+	off := NewCollector()
+	off.AddFailedLatency(30 * time.Millisecond)
+	if off.Results().Failed != nil {
+		t.Error("Expected failed attempts to be discarded without WithFailedLatency")
+	}
+}
+
+func TestBytesPerSecond(t *testing.T) {
+	start := time.Now()
+	a := NewCollector()
+	a.startTime, a.endTime = start, start.Add(time.Second)
+	a.AddLatency(time.Millisecond)
+	a.AddBytes(3 << 20)
+
+	b := NewCollector()
+	b.startTime, b.endTime = start, start.Add(time.Second)
+	b.AddLatency(time.Millisecond)
+	b.AddBytes(1 << 20)
+
+	a.Merge(b)
+	results := a.Results()
+	if results.Bytes != 4<<20 {
+		t.Errorf("Expected 4 MB written, got %d bytes", results.Bytes)
+	}
+	if got := results.MegabytesPerSecond(); got != 2 {
+		t.Errorf("Expected 2 MB/s over 2s, got %v", got)
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	a := NewCollector()
+	a.AddError(errors.New("timed out: canceling statement due to statement timeout"))
+	a.AddTimeout()
+	a.AddError(errors.New("connection failed"))
+
+	b := NewCollector()
+	b.AddError(errors.New("timed out: context deadline exceeded"))
+	b.AddTimeout()
+
+	a.Merge(b)
+	results := a.Results()
+	if results.ErrorCount != 3 || results.Timeouts != 2 {
+		t.Errorf("Expected 3 errors of which 2 timeouts, got %d and %d", results.ErrorCount, results.Timeouts)
+	}
+
+	a.Reset()
+	if got := a.Results().Timeouts; got != 0 {
+		t.Errorf("Expected no timeouts after reset, got %d", got)
+	}
+}
+
+func TestTimelineBuckets(t *testing.T) {
+	percentiles := []float64{50, 90, 99}
+	tl := newTimeline(time.Second)
+	for i := 1; i <= 100; i++ {
+		tl.record(100*time.Millisecond, time.Duration(i)*time.Millisecond, percentiles)
+	}
+	// Nothing in the second interval; the third has a slow tail
+	for i := 1; i <= 10; i++ {
+		tl.record(2500*time.Millisecond, time.Duration(i)*10*time.Millisecond, percentiles)
+	}
+
+	buckets := tl.closed(percentiles)
+	if len(buckets) != 3 {
+		t.Fatalf("Expected 3 buckets, got %+v", buckets)
+	}
+	if got := buckets[0]; got.Start != 0 || got.Operations != 100 || got.Latencies[0] != 50*time.Millisecond || got.Latencies[2] != 99*time.Millisecond {
+		t.Errorf("Unexpected first bucket %+v", got)
+	}
+	if got := buckets[1]; got.Start != time.Second || got.Operations != 0 || got.Latencies != nil {
+		t.Errorf("Expected an empty second bucket, got %+v", got)
+	}
+	if got := buckets[2]; got.Start != 2*time.Second || got.Operations != 10 || got.Latencies[1] != 90*time.Millisecond {
+		t.Errorf("Unexpected third bucket %+v", got)
+	}
+}
+
+func TestTimelineSinkReceivesClosedIntervals(t *testing.T) {
+	var streamed []TimelineBucket
+	c := NewCollector(WithTimeline(time.Hour), WithTimelineSink(func(start time.Time, bucket TimelineBucket) {
+		streamed = append(streamed, bucket)
+	}))
+	c.Start()
+	c.AddLatency(time.Millisecond)
+	c.AddLatency(2 * time.Millisecond)
+	if len(streamed) != 0 {
+		t.Fatalf("Expected no interval before it closes, got %+v", streamed)
+	}
+	c.End()
+	if len(streamed) != 1 || streamed[0].Operations != 2 {
+		t.Fatalf("Expected the last interval streamed at End, got %+v", streamed)
+	}
+	if got := c.Results().Timeline.Buckets; len(got) != 1 {
+		t.Errorf("Expected streamed intervals in Results once, got %+v", got)
+	}
+}
+
+func TestTimelineSinkReceivesStalledIntervals(t *testing.T) {
+	streamed := make(chan TimelineBucket, 10)
+	c := NewCollector(WithTimeline(20*time.Millisecond), WithTimelineSink(func(start time.Time, bucket TimelineBucket) {
+		streamed <- bucket
+	}))
+	c.Start()
+	defer c.End()
+	c.AddLatency(time.Millisecond)
+
+	// No further operation completes, yet the intervals still close
+	for i := 0; i < 2; i++ {
+		select {
+		case bucket := <-streamed:
+			if want := int64(1 - i); bucket.Operations != want {
+				t.Errorf("Interval %d: expected %d operations, got %+v", i, want, bucket)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Interval %d was not streamed while no operations completed", i)
+		}
+	}
+}
+
+func TestTimelineMergeFollowsMeasuredTime(t *testing.T) {
+	pooled := NewCollector(WithTimeline(time.Second))
+	for i := 0; i < 2; i++ {
+		slice := NewCollector(WithTimeline(time.Second))
+		slice.Start()
+		slice.AddLatency(time.Millisecond)
+		slice.End()
+		// Pretend each slice measured for three seconds
+		slice.startTime = slice.endTime.Add(-3 * time.Second)
+		pooled.Merge(slice)
+	}
+
+	timeline := pooled.Results().Timeline
+	if timeline == nil || len(timeline.Buckets) != 2 {
+		t.Fatalf("Expected 2 buckets, got %+v", timeline)
+	}
+	if timeline.Buckets[1].Start != 3*time.Second {
+		t.Errorf("Expected the second slice's bucket at 3s, got %v", timeline.Buckets[1].Start)
+	}
+}
+
+// TestPercentileAccuracy checks the reported percentiles of a uniform distribution of
+// 1..10000µs against their theoretical values. The exact recorder must return the
+// nearest-rank sample itself; the histogram recorder is allowed its 0.1% resolution.
+func TestPercentileAccuracy(t *testing.T) {
+	const n = 10000
+	percentiles := []float64{1, 10, 25, 50, 75, 90, 95, 99, 99.5, 99.9, 99.99}
+
+	// Feed the samples in a scrambled but reproducible order so sorting is exercised
+	samples := make([]time.Duration, n)
+	for i := range samples {
+		samples[i] = time.Duration(i*7919%n+1) * time.Microsecond
+	}
+
+	for _, tc := range []struct {
+		name      string
+		opts      []Option
+		tolerance float64 // relative error allowed
+	}{
+		{"exact", nil, 0},
+		{"histogram", []Option{WithHistogram()}, 0.001},
+	} {
+		c := NewCollector(append(tc.opts, WithPercentiles(percentiles))...)
+		c.Start()
+		for _, latency := range samples {
+			c.AddLatency(latency)
+		}
+		c.End()
+		results := c.Results()
+
+		fixed := map[float64]time.Duration{
+			50: results.P50Latency, 90: results.P90Latency, 95: results.P95Latency,
+			99: results.P99Latency, 99.9: results.P999Latency,
+		}
+		for _, p := range percentiles {
+			// The nearest-rank value of uniform 1..n is p% of n, in microseconds
+			expected := time.Duration(math.Round(p/100*n)) * time.Microsecond
+			got := results.Percentiles[p]
+			if diff := math.Abs(float64(got-expected)) / float64(expected); diff > tc.tolerance {
+				t.Errorf("%s P%v: expected %v, got %v (%.4f%% off)", tc.name, p, expected, got, diff*100)
+			}
+			if want, ok := fixed[p]; ok && want != got {
+				t.Errorf("%s P%v: Results field %v disagrees with Percentiles %v", tc.name, p, want, got)
+			}
+		}
+	}
+
+	// A timeline interval holding the same samples reports the same exact percentiles
+	tl := newTimeline(time.Minute)
+	for _, latency := range samples {
+		tl.record(time.Second, latency, percentiles)
+	}
+	buckets := tl.closed(percentiles)
+	if len(buckets) != 1 {
+		t.Fatalf("Expected 1 timeline bucket, got %d", len(buckets))
+	}
+	for i, p := range percentiles {
+		expected := time.Duration(math.Round(p/100*n)) * time.Microsecond
+		if got := buckets[0].Latencies[i]; got != expected {
+			t.Errorf("timeline P%v: expected %v, got %v", p, expected, got)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// Histogram layout: values below histogramSubBuckets are counted exactly; above that,
+// each power-of-two range is split into histogramHalfBuckets linear sub-buckets, giving
+// a relative error of at most 1/histogramHalfBuckets (~0.1%, three significant digits).
+const (
+	histogramSubBucketBits = 11
+	histogramSubBuckets    = 1 << histogramSubBucketBits
+	histogramHalfBuckets   = histogramSubBuckets / 2
+
+	// histogramMaxBits bounds the largest trackable value at 2^46ns (~19.5 hours);
+	// larger values are clamped into the top bucket
+	histogramMaxBits   = 46
+	histogramMaxValue  = int64(1)<<histogramMaxBits - 1
+	histogramCountsLen = histogramSubBuckets + (histogramMaxBits-histogramSubBucketBits)*histogramHalfBuckets
+)
+
+// Histogram is a fixed-size, HDR-style latency histogram. Its memory use does not grow
+// with the number of recorded values, so it is suited to long, high-throughput runs.
+// Histogram is not safe for concurrent use; Collector guards it with its mutex.
+type Histogram struct {
+	counts     []int64
+	total      int64
+	min        int64
+	max        int64
+	sum        float64
+	sumSquares float64
+}
+
+// NewHistogram creates an empty histogram
+func NewHistogram() *Histogram {
+	return &Histogram{
+		counts: make([]int64, histogramCountsLen),
+		min:    math.MaxInt64,
+	}
+}
+
+// Record adds a latency to the histogram
+func (h *Histogram) Record(latency time.Duration) {
+	v := int64(latency)
+	if v < 0 {
+		v = 0
+	}
+	if v > histogramMaxValue {
+		v = histogramMaxValue
+	}
+
+	h.counts[histogramIndex(v)]++
+	h.total++
+	h.sum += float64(v)
+	h.sumSquares += float64(v) * float64(v)
+	if v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+}
+
+// Merge adds all values recorded in other to h
+func (h *Histogram) Merge(other *Histogram) {
+	if other.total == 0 {
+		return
+	}
+	for i, count := range other.counts {
+		h.counts[i] += count
+	}
+	h.total += other.total
+	h.sum += other.sum
+	h.sumSquares += other.sumSquares
+	if other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+}
+
+// Reset clears all recorded values while keeping the allocated buckets
+func (h *Histogram) Reset() {
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+	h.total = 0
+	h.min = math.MaxInt64
+	h.max = 0
+	h.sum = 0
+	h.sumSquares = 0
+}
+
+// Count returns the number of recorded values
+func (h *Histogram) Count() int64 {
+	return h.total
+}
+
+// Min returns the smallest recorded value
+func (h *Histogram) Min() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return time.Duration(h.min)
+}
+
+// Max returns the largest recorded value
+func (h *Histogram) Max() time.Duration {
+	return time.Duration(h.max)
+}
+
+// Mean returns the exact mean of recorded values
+func (h *Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return time.Duration(h.sum / float64(h.total))
+}
+
+// StdDev returns the population standard deviation of recorded values
+func (h *Histogram) StdDev() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	mean := h.sum / float64(h.total)
+	variance := h.sumSquares/float64(h.total) - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	return time.Duration(math.Sqrt(variance))
+}
+
+// Percentile returns the value at the given percentile (0-100), accurate to the bucket
+// resolution and clamped to the exact recorded min and max
+func (h *Histogram) Percentile(percentile float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	if percentile <= 0 {
+		return time.Duration(h.min)
+	}
+	if percentile >= 100 {
+		return time.Duration(h.max)
+	}
+
+	target := int64(math.Ceil(percentile / 100 * float64(h.total)))
+	if target < 1 {
+		target = 1
+	}
+
+	var cumulative int64
+	for i, count := range h.counts {
+		cumulative += count
+		if cumulative >= target {
+			v := histogramMidpoint(i)
+			if v < h.min {
+				v = h.min
+			}
+			if v > h.max {
+				v = h.max
+			}
+			return time.Duration(v)
+		}
+	}
+	return time.Duration(h.max)
+}
+
+// histogramIndex maps a value to its bucket index
+func histogramIndex(v int64) int {
+	if v < histogramSubBuckets {
+		return int(v)
+	}
+	shift := bits.Len64(uint64(v)) - histogramSubBucketBits
+	sub := v >> uint(shift)
+	return histogramSubBuckets + (shift-1)*histogramHalfBuckets + int(sub-histogramHalfBuckets)
+}
+
+// histogramMidpoint returns the middle of the value range covered by a bucket index
+func histogramMidpoint(index int) int64 {
+	if index < histogramSubBuckets {
+		return int64(index)
+	}
+	offset := index - histogramSubBuckets
+	shift := uint(offset/histogramHalfBuckets + 1)
+	sub := int64(offset%histogramHalfBuckets + histogramHalfBuckets)
+	lowest := sub << shift
+	return lowest + (int64(1)<<shift)/2
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestHistogramPercentiles(t *testing.T) {
+	h := NewHistogram()
+	for i := 1; i <= 100000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+
+	if h.Count() != 100000 {
+		t.Fatalf("Expected 100000 values, got %d", h.Count())
+	}
+	if h.Min() != time.Microsecond {
+		t.Errorf("Expected min 1µs, got %v", h.Min())
+	}
+	if h.Max() != 100*time.Millisecond {
+		t.Errorf("Expected max 100ms, got %v", h.Max())
+	}
+
+	for _, p := range []float64{50, 90, 95, 99, 99.9} {
+		expected := time.Duration(p * 1000 * float64(time.Microsecond))
+		got := h.Percentile(p)
+		if diff := float64(got-expected) / float64(expected); diff > 0.001 || diff < -0.001 {
+			t.Errorf("P%v: expected ~%v, got %v (%.4f%% off)", p, expected, got, diff*100)
+		}
+	}
+}
+
+func TestHistogramMerge(t *testing.T) {
+	a, b := NewHistogram(), NewHistogram()
+	for i := 1; i <= 1000; i++ {
+		a.Record(time.Duration(i) * time.Millisecond)
+		b.Record(time.Duration(i+1000) * time.Millisecond)
+	}
+	a.Merge(b)
+
+	if a.Count() != 2000 {
+		t.Errorf("Expected 2000 values after merge, got %d", a.Count())
+	}
+	if a.Max() != 2000*time.Millisecond {
+		t.Errorf("Expected max 2s after merge, got %v", a.Max())
+	}
+}
+
+func TestHistogramCollectorDoesNotAllocate(t *testing.T) {
+	c := NewCollector(WithHistogram())
+	allocs := testing.AllocsPerRun(100000, func() {
+		c.AddLatency(3 * time.Millisecond)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected AddLatency to not allocate with histogram recorder, got %v allocs/op", allocs)
+	}
+}
+
+// BenchmarkCollectorAddLatency compares memory growth of the exact and histogram
+// recorders; the histogram's B/op stays constant as the operation count grows
+func BenchmarkCollectorAddLatency(b *testing.B) {
+	recorders := map[string][]Option{
+		"exact":     nil,
+		"histogram": {WithHistogram()},
+	}
+	for _, name := range []string{"exact", "histogram"} {
+		for _, ops := range []int{1000, 100000} {
+			b.Run(fmt.Sprintf("%s/%d", name, ops), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					c := NewCollector(recorders[name]...)
+					for j := 0; j < ops; j++ {
+						c.AddLatency(time.Duration(j) * time.Microsecond)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+)
+
+// Timeline is the latency distribution of a measurement over time: for each interval,
+// the latency at every configured percentile. It is laid out for heatmaps, with bucket
+// start time on one axis, Percentiles on the other, and latency as the value.
+type Timeline struct {
+	Interval    time.Duration    `json:"interval"`
+	Percentiles []float64        `json:"percentiles"`
+	Buckets     []TimelineBucket `json:"buckets"`
+}
+
+// TimelineBucket summarizes the latencies recorded during one interval
+type TimelineBucket struct {
+	Start      time.Duration   `json:"start"` // offset from the start of measurement
+	Operations int64           `json:"operations"`
+	Latencies  []time.Duration `json:"latencies"` // one per Timeline.Percentiles, in the same order; null when Operations is 0
+}
+
+// timeline records latencies into fixed intervals. Only the samples of the current
+// interval are kept; earlier intervals are reduced to their percentiles as soon as a
+// later one starts, so memory stays bounded however long the run. Intervals in which
+// no operation completed, such as during a stalled mount, are kept as empty buckets.
+type timeline struct {
+	interval time.Duration
+	offset   time.Duration // added to bucket starts, for measurements merged after others
+	buckets  []TimelineBucket
+	current  int // index of the interval being filled
+	samples  []time.Duration
+	sink     func(TimelineBucket) // when set, receives every interval as it closes
+}
+
+func newTimeline(interval time.Duration) *timeline {
+	return &timeline{interval: interval}
+}
+
+// record adds a latency observed elapsed after the start of measurement
+func (t *timeline) record(elapsed, latency time.Duration, percentiles []float64) {
+	t.advance(elapsed, percentiles)
+	t.samples = append(t.samples, latency)
+}
+
+// advance closes every interval that ended before elapsed, empty or not
+func (t *timeline) advance(elapsed time.Duration, percentiles []float64) {
+	index := int(elapsed / t.interval)
+	for t.current < index {
+		bucket, ok := t.pending(percentiles)
+		if !ok {
+			bucket = TimelineBucket{Start: t.offset + time.Duration(t.current)*t.interval}
+		}
+		t.close(bucket)
+		t.current++
+	}
+}
+
+// flush reduces the current interval's samples to a bucket, if it has any
+func (t *timeline) flush(percentiles []float64) {
+	if bucket, ok := t.pending(percentiles); ok {
+		t.close(bucket)
+	}
+}
+
+// close records bucket as closed and discards the samples it was computed from
+func (t *timeline) close(bucket TimelineBucket) {
+	t.buckets = append(t.buckets, bucket)
+	if t.sink != nil {
+		t.sink(bucket)
+	}
+	t.samples = t.samples[:0]
+}
+
+// pending summarizes the interval being filled without closing it
+func (t *timeline) pending(percentiles []float64) (TimelineBucket, bool) {
+	if len(t.samples) == 0 {
+		return TimelineBucket{}, false
+	}
+
+	sorted := append([]time.Duration(nil), t.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	bucket := TimelineBucket{
+		Start:      t.offset + time.Duration(t.current)*t.interval,
+		Operations: int64(len(sorted)),
+		Latencies:  make([]time.Duration, len(percentiles)),
+	}
+	for i, p := range percentiles {
+		bucket.Latencies[i] = calculatePercentile(sorted, p)
+	}
+	return bucket, true
+}
+
+// closed returns every bucket, including the interval still being filled
+func (t *timeline) closed(percentiles []float64) []TimelineBucket {
+	buckets := append([]TimelineBucket(nil), t.buckets...)
+	if bucket, ok := t.pending(percentiles); ok {
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// reset discards all recorded intervals
+func (t *timeline) reset() {
+	t.offset = 0
+	t.buckets = nil
+	t.current = 0
+	t.samples = t.samples[:0]
+}
@@ -0,0 +1,145 @@
+package nfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Mount is one entry of /proc/mounts
+type Mount struct {
+	Source     string
+	MountPoint string
+	FSType     string
+	Options    map[string]string
+}
+
+// IsNFS reports whether the mount is an NFS mount of any version
+func (m Mount) IsNFS() bool {
+	return m.FSType == "nfs" || m.FSType == "nfs4"
+}
+
+// Version returns the negotiated NFS version in config form (e.g. "v3", "v4.2"),
+// or "" if it cannot be determined
+func (m Mount) Version() string {
+	if v, ok := m.Options["vers"]; ok {
+		return "v" + v
+	}
+	if v, ok := m.Options["nfsvers"]; ok {
+		return "v" + v
+	}
+	if m.FSType == "nfs4" {
+		return "v4"
+	}
+	return ""
+}
+
+// ParseMounts parses the contents of /proc/mounts
+func ParseMounts(r io.Reader) ([]Mount, error) {
+	var mounts []Mount
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+
+		options := make(map[string]string)
+		for _, opt := range strings.Split(fields[3], ",") {
+			key, value, _ := strings.Cut(opt, "=")
+			options[key] = value
+		}
+
+		mounts = append(mounts, Mount{
+			Source:     unescapeMountField(fields[0]),
+			MountPoint: unescapeMountField(fields[1]),
+			FSType:     fields[2],
+			Options:    options,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mounts: %w", err)
+	}
+	return mounts, nil
+}
+
+// unescapeMountField decodes the octal escapes (e.g. \040 for space) used in /proc/mounts
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// FindMount returns the mount containing dir, i.e. the entry with the longest mount
+// point that is a parent of dir. Later entries win ties, as they shadow earlier ones.
+func FindMount(mounts []Mount, dir string) (Mount, bool) {
+	dir = path.Clean(dir)
+	var best Mount
+	found := false
+	for _, m := range mounts {
+		mp := path.Clean(m.MountPoint)
+		if dir != mp && mp != "/" && !strings.HasPrefix(dir, mp+"/") {
+			continue
+		}
+		if !found || len(mp) >= len(path.Clean(best.MountPoint)) {
+			best = m
+			found = true
+		}
+	}
+	return best, found
+}
+
+// VerifyMount checks that dir lives on an NFS mount whose version is one of versions.
+// An empty versions list accepts any NFS version. A configured major version such as
+// "v4" accepts any minor version ("v4.1", "v4.2").
+func VerifyMount(mounts []Mount, dir string, versions []string) (Mount, error) {
+	m, ok := FindMount(mounts, dir)
+	if !ok {
+		return Mount{}, fmt.Errorf("no mount found for %s", dir)
+	}
+	if !m.IsNFS() {
+		return m, fmt.Errorf("%s is on %s (%s mounted at %s), not NFS", dir, m.FSType, m.Source, m.MountPoint)
+	}
+	if len(versions) == 0 {
+		return m, nil
+	}
+
+	actual := m.Version()
+	if MatchesVersion(actual, versions) {
+		return m, nil
+	}
+	return m, fmt.Errorf("%s is mounted with NFS %s, expected one of %s", dir, versionLabel(actual), strings.Join(versions, ", "))
+}
+
+// MatchesVersion reports whether an NFS version is one of versions, where a major version
+// such as "v4" matches any of its minor versions ("v4.1", "v4.2")
+func MatchesVersion(version string, versions []string) bool {
+	for _, v := range versions {
+		if version == v || strings.HasPrefix(version, v+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func versionLabel(version string) string {
+	if version == "" {
+		return "an unknown version"
+	}
+	return version
+}
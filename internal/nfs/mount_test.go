@@ -0,0 +1,102 @@
+package nfs
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleMounts = `overlay / overlay rw,relatime,lowerdir=/var/lib/docker 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda1 /var/lib/postgresql ext4 rw,relatime 0 0
+nfs-server:/nfsshare /mnt/nfs nfs rw,relatime,vers=3,rsize=8192,wsize=8192,hard,proto=tcp 0 0
+nfs-server:/other /mnt/nfs\040v4 nfs4 rw,relatime,vers=4.2,rsize=65536 0 0
+`
+
+func TestVerifyMount(t *testing.T) {
+	mounts, err := ParseMounts(strings.NewReader(sampleMounts))
+	if err != nil {
+		t.Fatalf("ParseMounts: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		dir      string
+		versions []string
+		wantErr  bool
+	}{
+		{"nfs v3", "/mnt/nfs/postgresql/data", []string{"v3", "v4"}, false},
+		{"wrong version", "/mnt/nfs/postgresql/data", []string{"v4"}, true},
+		{"minor version matches major", "/mnt/nfs v4/data", []string{"v4"}, false},
+		{"any version", "/mnt/nfs/data", nil, false},
+		{"local disk", "/var/lib/postgresql/data", []string{"v3"}, true},
+		{"mount point prefix is not a parent", "/mnt/nfsdata", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyMount(mounts, tt.dir, tt.versions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyMount(%q, %v) error = %v, wantErr %v", tt.dir, tt.versions, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchesVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		versions []string
+		want     bool
+	}{
+		{"v4.1", []string{"v4.1"}, true},
+		{"v4.1", []string{"v4"}, true},
+		{"v4.2", []string{"v4.1"}, false},
+		{"v4", []string{"v4.1"}, false},
+		{"v3", []string{"v4", "v3"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchesVersion(tt.version, tt.versions); got != tt.want {
+			t.Errorf("MatchesVersion(%q, %v) = %v, want %v", tt.version, tt.versions, got, tt.want)
+		}
+	}
+}
+
+const sampleMountStats = `device proc mounted on /proc with fstype proc
+device nfs-server:/nfsshare mounted on /mnt/nfs with fstype nfs statvers=1.1
+	opts:	rw,vers=3,rsize=8192,wsize=8192
+	age:	120
+	per-op statistics
+	        NULL: 0 0 0 0 0 0 0 0
+	     GETATTR: 100 102 0 12000 11200 5 80 90
+	       WRITE: 50 55 1 409600 6000 10 400 420 0
+	      COMMIT: 5 5 0 600 700 0 30 31
+`
+
+func TestParseMountStats(t *testing.T) {
+	stats, err := ParseMountStats(strings.NewReader(sampleMountStats))
+	if err != nil {
+		t.Fatalf("ParseMountStats: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("got %d NFS mounts, want 1", len(stats))
+	}
+
+	m, ok := FindMountStats(stats, "/mnt/nfs/postgresql/data")
+	if !ok {
+		t.Fatal("mount stats not found for data directory")
+	}
+
+	write := m.Ops["WRITE"]
+	if write.Ops != 50 || write.Retransmits() != 5 || write.MajorTimeouts != 1 || write.BytesSent != 409600 {
+		t.Errorf("unexpected WRITE stats: %+v", write)
+	}
+	if got := m.Ops["GETATTR"].Retransmits(); got != 2 {
+		t.Errorf("GETATTR retransmits = %d, want 2", got)
+	}
+
+	before := MountStats{Ops: map[string]OpStats{"WRITE": {Ops: 20, Transmissions: 21}}}
+	if d := m.Sub(before)["WRITE"]; d.Ops != 30 || d.Retransmits() != 4 {
+		t.Errorf("unexpected WRITE delta: %+v", d)
+	}
+}
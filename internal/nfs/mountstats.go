@@ -0,0 +1,154 @@
+package nfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TrackedOps are the per-operation counters reported for benchmark runs
+var TrackedOps = []string{"READ", "WRITE", "COMMIT", "GETATTR"}
+
+// OpStats are the client-side counters for one NFS operation, as listed in the
+// "per-op statistics" section of /proc/self/mountstats
+type OpStats struct {
+	Ops           int64 `json:"ops"`
+	Transmissions int64 `json:"transmissions"`
+	MajorTimeouts int64 `json:"major_timeouts"`
+	BytesSent     int64 `json:"bytes_sent"`
+	BytesRecv     int64 `json:"bytes_recv"`
+	QueueMs       int64 `json:"queue_ms"`
+	RTTMs         int64 `json:"rtt_ms"`
+	ExecuteMs     int64 `json:"execute_ms"`
+}
+
+// Retransmits returns how many transmissions were resends of an earlier request
+func (s OpStats) Retransmits() int64 {
+	return s.Transmissions - s.Ops
+}
+
+// Sub returns the counter deltas between s and an earlier snapshot
+func (s OpStats) Sub(before OpStats) OpStats {
+	return OpStats{
+		Ops:           s.Ops - before.Ops,
+		Transmissions: s.Transmissions - before.Transmissions,
+		MajorTimeouts: s.MajorTimeouts - before.MajorTimeouts,
+		BytesSent:     s.BytesSent - before.BytesSent,
+		BytesRecv:     s.BytesRecv - before.BytesRecv,
+		QueueMs:       s.QueueMs - before.QueueMs,
+		RTTMs:         s.RTTMs - before.RTTMs,
+		ExecuteMs:     s.ExecuteMs - before.ExecuteMs,
+	}
+}
+
+// MountStats are the statistics of one NFS mount
+type MountStats struct {
+	Device     string
+	MountPoint string
+	FSType     string
+	Ops        map[string]OpStats
+}
+
+// Sub returns the per-operation deltas between m and an earlier snapshot of the same mount
+func (m MountStats) Sub(before MountStats) map[string]OpStats {
+	deltas := make(map[string]OpStats, len(m.Ops))
+	for op, stats := range m.Ops {
+		deltas[op] = stats.Sub(before.Ops[op])
+	}
+	return deltas
+}
+
+// ParseMountStats parses /proc/self/mountstats, returning the NFS mounts only
+func ParseMountStats(r io.Reader) ([]MountStats, error) {
+	var (
+		all     []MountStats
+		current *MountStats
+		inOps   bool
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// device <source> mounted on <mount point> with fstype <type> [statvers=...]
+		if fields[0] == "device" {
+			inOps = false
+			current = nil
+			if len(fields) >= 8 && fields[2] == "mounted" && fields[3] == "on" && fields[5] == "with" && fields[6] == "fstype" {
+				all = append(all, MountStats{
+					Device:     fields[1],
+					MountPoint: unescapeMountField(fields[4]),
+					FSType:     fields[7],
+					Ops:        make(map[string]OpStats),
+				})
+				current = &all[len(all)-1]
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if strings.TrimSpace(line) == "per-op statistics" {
+			inOps = true
+			continue
+		}
+		if !inOps || !strings.HasSuffix(fields[0], ":") || len(fields) < 9 {
+			continue
+		}
+
+		var values [8]int64
+		for i := range values {
+			v, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mountstats counter %q for %s", fields[i+1], fields[0])
+			}
+			values[i] = v
+		}
+		current.Ops[strings.TrimSuffix(fields[0], ":")] = OpStats{
+			Ops:           values[0],
+			Transmissions: values[1],
+			MajorTimeouts: values[2],
+			BytesSent:     values[3],
+			BytesRecv:     values[4],
+			QueueMs:       values[5],
+			RTTMs:         values[6],
+			ExecuteMs:     values[7],
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mountstats: %w", err)
+	}
+
+	nfsMounts := all[:0]
+	for _, m := range all {
+		if m.FSType == "nfs" || m.FSType == "nfs4" {
+			nfsMounts = append(nfsMounts, m)
+		}
+	}
+	return nfsMounts, nil
+}
+
+// FindMountStats returns the statistics of the NFS mount containing dir
+func FindMountStats(stats []MountStats, dir string) (MountStats, bool) {
+	mounts := make([]Mount, len(stats))
+	for i, s := range stats {
+		mounts[i] = Mount{Source: s.Device, MountPoint: s.MountPoint, FSType: s.FSType}
+	}
+	m, ok := FindMount(mounts, dir)
+	if !ok {
+		return MountStats{}, false
+	}
+	for i := len(stats) - 1; i >= 0; i-- {
+		if stats[i].MountPoint == m.MountPoint {
+			return stats[i], true
+		}
+	}
+	return MountStats{}, false
+}
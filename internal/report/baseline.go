@@ -0,0 +1,200 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/l22io/nfsvsdirectbench/internal/benchmark"
+)
+
+// DefaultBaselineTolerance is how many percentage points NFS overhead may grow over the
+// baseline before it counts as a regression
+const DefaultBaselineTolerance = 5.0
+
+// BaselineMetrics are the metric keys checked against a baseline. Counts and sizes depend
+// on run length rather than on storage, so they are left out.
+var BaselineMetrics = []string{
+	"throughput",
+	"average_latency_ms",
+	"p50_latency_ms",
+	"p95_latency_ms",
+	"p99_latency_ms",
+}
+
+// Baseline is a stored set of reference comparisons, one per database, scenario, and
+// NFS mount option variant
+type Baseline struct {
+	Version int           `json:"version"`
+	Reports []*JSONReport `json:"reports"`
+}
+
+// Regression is a metric whose NFS overhead grew beyond the tolerance. Overheads are
+// expressed as the percentage by which NFS is worse than direct, so a larger value is
+// always worse regardless of the metric's direction.
+type Regression struct {
+	Database         string
+	Scenario         string
+	MountOption      string
+	Key              string
+	Metric           string
+	BaselineOverhead float64
+	CurrentOverhead  float64
+}
+
+// NewBaseline builds a baseline from the comparisons of a run
+func NewBaseline(comparisons []*Comparison) *Baseline {
+	b := &Baseline{Version: SchemaVersion}
+	for _, c := range comparisons {
+		b.Reports = append(b.Reports, c.JSON())
+	}
+	return b
+}
+
+// LoadBaseline reads a baseline file written by Save
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+	if b.Version != SchemaVersion {
+		return nil, fmt.Errorf("baseline %s has version %d, expected %d", path, b.Version, SchemaVersion)
+	}
+	return &b, nil
+}
+
+// Save writes the baseline as indented JSON
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// CheckResult is the outcome of checking a run's comparisons against a baseline
+type CheckResult struct {
+	Regressions []Regression
+	Checked     int      // comparisons with a baseline entry and at least one metric compared
+	Unmatched   []string // comparisons without a baseline entry, as database/scenario[/mount option]
+}
+
+// Check compares the current comparisons against the baseline and returns every baseline
+// metric whose NFS overhead grew by more than tolerance percentage points, along with
+// how many comparisons were actually checked and which had no baseline entry. Metrics
+// without an overhead on either side are skipped.
+func (b *Baseline) Check(current []*Comparison, tolerance float64) CheckResult {
+	reports := make(map[string]*JSONReport)
+	for _, r := range b.Reports {
+		reports[baselineKey(r.Database, r.Scenario, r.MountOption)] = r
+	}
+
+	var result CheckResult
+	for _, c := range current {
+		ref, ok := reports[baselineKey(c.Database, c.Scenario, c.MountOption)]
+		if !ok {
+			name := c.Database + "/" + c.Scenario
+			if c.MountOption != "" {
+				name += "/" + c.MountOption
+			}
+			result.Unmatched = append(result.Unmatched, name)
+			continue
+		}
+		now := c.JSON()
+
+		compared := false
+		for _, key := range BaselineMetrics {
+			before, okBefore := findMetric(ref, key)
+			after, okAfter := findMetric(now, key)
+			if !okBefore || !okAfter || before.OverheadPercent == nil || after.OverheadPercent == nil {
+				continue
+			}
+			compared = true
+
+			baselineOverhead := worseOverhead(before)
+			currentOverhead := worseOverhead(after)
+			if currentOverhead-baselineOverhead > tolerance {
+				result.Regressions = append(result.Regressions, Regression{
+					Database:         c.Database,
+					Scenario:         c.Scenario,
+					MountOption:      c.MountOption,
+					Key:              key,
+					Metric:           after.Name,
+					BaselineOverhead: baselineOverhead,
+					CurrentOverhead:  currentOverhead,
+				})
+			}
+		}
+		if compared {
+			result.Checked++
+		}
+	}
+	return result
+}
+
+// LoadDir loads the comparison of every results file (.json or .json.gz) in a run's
+// output directory that has both direct and NFS results, ordered by file name. Files that
+// hold something else, like a combined file of mount option variants, are skipped; a
+// file that cannot be read or parsed is an error.
+func LoadDir(dir string) ([]*Comparison, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := filepath.Glob(filepath.Join(dir, "*.json.gz"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range compressed {
+		if !strings.HasSuffix(path, ".raw.json.gz") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var comparisons []*Comparison
+	for _, path := range paths {
+		if filepath.Base(path) == benchmark.ProgressFile {
+			continue
+		}
+		c, err := Load(path)
+		if errors.Is(err, ErrNotComparison) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		comparisons = append(comparisons, c)
+	}
+	return comparisons, nil
+}
+
+func baselineKey(database, scenario, mountOption string) string {
+	return database + "/" + scenario + "/" + mountOption
+}
+
+func findMetric(r *JSONReport, key string) (JSONMetric, bool) {
+	for _, m := range r.Metrics {
+		if m.Key == key {
+			return m, true
+		}
+	}
+	return JSONMetric{}, false
+}
+
+// worseOverhead orients a metric's overhead so positive means NFS is worse
+func worseOverhead(m JSONMetric) float64 {
+	if m.HigherIsBetter {
+		return -*m.OverheadPercent
+	}
+	return *m.OverheadPercent
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+func comparison(directOps, nfsOps float64, directP99, nfsP99 time.Duration) *Comparison {
+	return Build("test",
+		&StorageResult{Name: "heavy_inserts", Database: "postgresql", Metrics: &metrics.Results{OperationsPerSecond: directOps, P99Latency: directP99}},
+		&StorageResult{Name: "heavy_inserts", Database: "postgresql", Metrics: &metrics.Results{OperationsPerSecond: nfsOps, P99Latency: nfsP99}},
+	)
+}
+
+func TestBaselineCheck(t *testing.T) {
+	// Baseline: NFS 20% slower throughput, 50% higher p99
+	baseline := NewBaseline([]*Comparison{comparison(1000, 800, 10*time.Millisecond, 15*time.Millisecond)})
+
+	// Throughput overhead within tolerance (22% vs 20%), p99 overhead regressed (100% vs 50%)
+	current := []*Comparison{comparison(1000, 780, 10*time.Millisecond, 20*time.Millisecond)}
+
+	result := baseline.Check(current, DefaultBaselineTolerance)
+	if result.Checked != 1 || len(result.Unmatched) != 0 {
+		t.Errorf("Expected 1 comparison checked and none unmatched, got %+v", result)
+	}
+	regressions := result.Regressions
+	if len(regressions) != 1 {
+		t.Fatalf("Expected 1 regression, got %d: %+v", len(regressions), regressions)
+	}
+	if got := regressions[0]; got.Key != "p99_latency_ms" || got.BaselineOverhead != 50 || got.CurrentOverhead != 100 {
+		t.Errorf("Unexpected regression %+v", got)
+	}
+
+	// Throughput falling further counts as a regression even though its overhead is negative
+	current = []*Comparison{comparison(1000, 700, 10*time.Millisecond, 15*time.Millisecond)}
+	regressions = baseline.Check(current, DefaultBaselineTolerance).Regressions
+	if len(regressions) != 1 || regressions[0].Key != "throughput" || regressions[0].CurrentOverhead != 30 {
+		t.Errorf("Expected a throughput regression at 30%%, got %+v", regressions)
+	}
+}
+
+func TestBaselineCheckUnmatched(t *testing.T) {
+	baseline := NewBaseline([]*Comparison{comparison(1000, 800, 10*time.Millisecond, 15*time.Millisecond)})
+
+	other := comparison(1000, 500, 10*time.Millisecond, 50*time.Millisecond)
+	other.Scenario = "bulk_load"
+	result := baseline.Check([]*Comparison{other}, DefaultBaselineTolerance)
+	if result.Checked != 0 || len(result.Regressions) != 0 {
+		t.Errorf("Expected nothing checked, got %+v", result)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0] != "postgresql/bulk_load" {
+		t.Errorf("Expected postgresql/bulk_load unmatched, got %v", result.Unmatched)
+	}
+}
+
+func TestLoadDirRejectsUnreadableResults(t *testing.T) {
+	dir := t.TempDir()
+	// A combined file of mount option variants is skipped, and progress.json is not a
+	// results file at all
+	variants := `{"direct": {"Name": "heavy_inserts", "Database": "postgresql", "StorageType": "direct"},
+"nfs_soft": {"Name": "heavy_inserts", "Database": "postgresql", "StorageType": "nfs", "MountOption": "soft"}}`
+	if err := os.WriteFile(filepath.Join(dir, "postgresql_heavy_inserts.json"), []byte(variants), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "progress.json"), []byte(`{"completed": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	comparisons, err := LoadDir(dir)
+	if err != nil || len(comparisons) != 0 {
+		t.Fatalf("Expected no comparisons and no error, got %d and %v", len(comparisons), err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "postgresql_bulk_load.json"), []byte(`{"direct": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDir(dir); err == nil {
+		t.Error("Expected an error for a truncated results file")
+	}
+}
+
+func TestLoadDirReadsCompressedResults(t *testing.T) {
+	dir := t.TempDir()
+	results := `{"direct": {"Name": "heavy_inserts", "Database": "postgresql", "StorageType": "direct", "Metrics": {"operations_per_second": 1000}},
+"nfs": {"Name": "heavy_inserts", "Database": "postgresql", "StorageType": "nfs", "Metrics": {"operations_per_second": 800}}}`
+
+	f, err := os.Create(filepath.Join(dir, "postgresql_heavy_inserts.json.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write([]byte(results)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Raw sample exports share the .json.gz suffix and must be skipped
+	if err := os.WriteFile(filepath.Join(dir, "postgresql_heavy_inserts_nfs.raw.json.gz"), []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	comparisons, err := LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comparisons) != 1 {
+		t.Fatalf("Expected 1 comparison, got %d", len(comparisons))
+	}
+	if got := comparisons[0].NFS.Metrics.OperationsPerSecond; got != 800 {
+		t.Errorf("Expected NFS throughput 800, got %v", got)
+	}
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// SchemaVersion is the version of the JSON report schema. It is bumped whenever a
+// field is removed or changes meaning; new fields may be added without a bump.
+const SchemaVersion = 1
+
+// Verdicts describe how NFS compares to direct storage for a metric
+const (
+	VerdictBetter  = "better"
+	VerdictWorse   = "worse"
+	VerdictSimilar = "similar"
+)
+
+// similarThresholdPercent is the overhead below which a metric is reported as similar
+const similarThresholdPercent = 1.0
+
+// JSONReport is the machine-readable form of a Comparison
+type JSONReport struct {
+	Version     int          `json:"version"`
+	Source      string       `json:"source"`
+	Database    string       `json:"database"`
+	Scenario    string       `json:"scenario"`
+	MountOption string       `json:"mount_option,omitempty"`
+	Metrics     []JSONMetric `json:"metrics"`
+}
+
+// JSONMetric is one compared metric. OverheadPercent and Verdict describe NFS
+// relative to direct storage; OverheadPercent is null when the direct value is zero.
+type JSONMetric struct {
+	Key             string   `json:"key"`
+	Name            string   `json:"name"`
+	Unit            Unit     `json:"unit"`
+	Direct          float64  `json:"direct"`
+	NFS             float64  `json:"nfs"`
+	OverheadPercent *float64 `json:"overhead_percent"`
+	HigherIsBetter  bool     `json:"higher_is_better"`
+	Verdict         string   `json:"verdict"`
+}
+
+// JSON converts the comparison to its versioned JSON schema
+func (c *Comparison) JSON() *JSONReport {
+	out := &JSONReport{
+		Version:     SchemaVersion,
+		Source:      c.Source,
+		Database:    c.Database,
+		Scenario:    c.Scenario,
+		MountOption: c.MountOption,
+		Metrics:     make([]JSONMetric, 0, len(c.Rows)),
+	}
+
+	for _, r := range c.Rows {
+		m := JSONMetric{
+			Key:            r.Key,
+			Name:           r.Metric,
+			Unit:           r.Unit,
+			Direct:         r.Direct,
+			NFS:            r.NFS,
+			HigherIsBetter: r.HigherIsBetter,
+			Verdict:        verdict(r),
+		}
+		if r.Direct != 0 {
+			overhead := r.OverheadPercent
+			m.OverheadPercent = &overhead
+		}
+		out.Metrics = append(out.Metrics, m)
+	}
+
+	return out
+}
+
+// WriteJSON prints the comparison as indented JSON
+func (c *Comparison) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c.JSON())
+}
+
+// verdict classifies the NFS value against the direct value for a row
+func verdict(r Row) string {
+	if r.Direct == r.NFS {
+		return VerdictSimilar
+	}
+	if r.Direct != 0 && math.Abs(r.OverheadPercent) < similarThresholdPercent {
+		return VerdictSimilar
+	}
+	if (r.NFS > r.Direct) == r.HigherIsBetter {
+		return VerdictBetter
+	}
+	return VerdictWorse
+}
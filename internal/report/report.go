@@ -0,0 +1,316 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/benchmark"
+	"github.com/l22io/nfsvsdirectbench/internal/database"
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// StorageResult is one storage type's entry in a saved results file
+type StorageResult struct {
+	Name        string
+	Database    string
+	StorageType string
+	MountOption string
+	Duration    time.Duration
+	Success     bool
+	Metrics     *metrics.Results
+	DBStats     map[string]interface{}
+	Durability  *benchmark.DurabilityCheck // committed rows read back, with verify_durability
+	InsertMode  string                     // set when the scenario compared insert modes
+}
+
+// Unit describes how a row's values are formatted
+type Unit string
+
+const (
+	UnitOpsPerSec Unit = "ops/sec"
+	UnitCount     Unit = "count"
+	UnitLatency   Unit = "ms"
+	UnitPercent   Unit = "percent"
+	UnitBytes     Unit = "bytes"
+	UnitMBPerSec  Unit = "MB/s"
+)
+
+// Row compares a single metric between direct and NFS storage
+type Row struct {
+	Key             string // stable identifier used in JSON output
+	Metric          string
+	Unit            Unit
+	Direct          float64
+	NFS             float64
+	OverheadPercent float64 // GetOverheadPercent(Direct, NFS)
+	HigherIsBetter  bool
+}
+
+// Comparison is the direct vs NFS comparison of one results file
+type Comparison struct {
+	Source      string
+	Database    string
+	Scenario    string
+	MountOption string // the NFS result's mount option variant, if any
+	Direct      *StorageResult
+	NFS         *StorageResult
+	Rows        []Row
+}
+
+// ErrNotComparison is returned by Load for a valid results file that does not hold a
+// single direct vs NFS pair, such as the combined file of mount option variants
+var ErrNotComparison = errors.New("not a direct vs NFS comparison")
+
+// Load reads a results file written by the runner and builds its comparison
+func Load(path string) (*Comparison, error) {
+	data, err := benchmark.ReadResultsFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results file: %w", err)
+	}
+
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse results file: %w", err)
+	}
+	results := make(map[string]*StorageResult, len(entries))
+	for key, raw := range entries {
+		if key == benchmark.ResultsConfigKey {
+			continue
+		}
+		var result *StorageResult
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse %s results: %w", key, err)
+		}
+		results[key] = result
+	}
+
+	direct, nfs := results["direct"], results["nfs"]
+	if direct == nil || nfs == nil {
+		for _, result := range results {
+			if result != nil && result.MountOption != "" {
+				return nil, fmt.Errorf("results file %s holds NFS mount option variants; use the <database>_<scenario>_<variant>.json file of a variant instead: %w", path, ErrNotComparison)
+			}
+			if result != nil && result.InsertMode != "" {
+				return nil, fmt.Errorf("results file %s holds several insert modes; use the <database>_<scenario>_<mode>.json file of a mode instead: %w", path, ErrNotComparison)
+			}
+		}
+		return nil, fmt.Errorf("results file %s must contain both direct and nfs results: %w", path, ErrNotComparison)
+	}
+
+	return Build(path, direct, nfs), nil
+}
+
+// Build compares a direct and an NFS result
+func Build(source string, direct, nfs *StorageResult) *Comparison {
+	c := &Comparison{
+		Source:      source,
+		Database:    direct.Database,
+		Scenario:    direct.Name,
+		MountOption: nfs.MountOption,
+		Direct:      direct,
+		NFS:         nfs,
+	}
+
+	dm, nm := direct.Metrics, nfs.Metrics
+	if dm == nil {
+		dm = &metrics.Results{}
+	}
+	if nm == nil {
+		nm = &metrics.Results{}
+	}
+
+	latencyRow := func(key, name string, d, n time.Duration) Row {
+		return row(key, name, UnitLatency, float64(d)/float64(time.Millisecond), float64(n)/float64(time.Millisecond), false)
+	}
+
+	c.Rows = []Row{
+		row("throughput", "Throughput", UnitOpsPerSec, dm.OperationsPerSecond, nm.OperationsPerSecond, true),
+		row("total_operations", "Total operations", UnitCount, float64(dm.TotalOperations), float64(nm.TotalOperations), true),
+	}
+
+	// Bandwidth, for workloads that count the bytes they write; it is what maps onto
+	// NFS wire bandwidth when records vary in size
+	if dm.Bytes > 0 || nm.Bytes > 0 {
+		c.Rows = append(c.Rows,
+			row("bandwidth_mb_per_sec", "Write bandwidth", UnitMBPerSec, dm.MegabytesPerSecond(), nm.MegabytesPerSecond(), true),
+		)
+	}
+
+	c.Rows = append(c.Rows,
+		latencyRow("average_latency_ms", "Average latency", dm.AverageLatency, nm.AverageLatency),
+		latencyRow("p50_latency_ms", "P50 latency", dm.P50Latency, nm.P50Latency),
+		latencyRow("p90_latency_ms", "P90 latency", dm.P90Latency, nm.P90Latency),
+		latencyRow("p95_latency_ms", "P95 latency", dm.P95Latency, nm.P95Latency),
+		latencyRow("p99_latency_ms", "P99 latency", dm.P99Latency, nm.P99Latency),
+		latencyRow("p999_latency_ms", "P99.9 latency", dm.P999Latency, nm.P999Latency),
+		latencyRow("max_latency_ms", "Max latency", dm.MaxLatency, nm.MaxLatency),
+		latencyRow("stddev_latency_ms", "Latency stddev", dm.StdDevLatency, nm.StdDevLatency),
+		row("error_rate_percent", "Error rate", UnitPercent, dm.ErrorRate*100, nm.ErrorRate*100, false),
+		row("table_size_bytes", "Table size", UnitBytes, statFloat(direct.DBStats, "table_size_bytes"), statFloat(nfs.DBStats, "table_size_bytes"), false),
+		row("index_size_bytes", "Index size", UnitBytes, statFloat(direct.DBStats, "index_size_bytes"), statFloat(nfs.DBStats, "index_size_bytes"), false),
+		row("final_record_count", "Final records", UnitCount, statFloat(direct.DBStats, "final_record_count"), statFloat(nfs.DBStats, "final_record_count"), true),
+	)
+
+	// Out-of-line storage of large values, part of the table size
+	_, dok := direct.DBStats["toast_size_bytes"]
+	_, nok := nfs.DBStats["toast_size_bytes"]
+	if dok || nok {
+		c.Rows = append(c.Rows,
+			row("toast_size_bytes", "TOAST size", UnitBytes, statFloat(direct.DBStats, "toast_size_bytes"), statFloat(nfs.DBStats, "toast_size_bytes"), false),
+		)
+	}
+
+	// Pool waits tell connection starvation apart from slow storage
+	_, dok = direct.DBStats["wait_count"]
+	_, nok = nfs.DBStats["wait_count"]
+	if dok || nok {
+		c.Rows = append(c.Rows,
+			row("pool_wait_count", "Pool waits", UnitCount, statFloat(direct.DBStats, "wait_count"), statFloat(nfs.DBStats, "wait_count"), false),
+			row("pool_wait_duration_ms", "Pool wait time", UnitLatency, statFloat(direct.DBStats, "wait_duration_ms"), statFloat(nfs.DBStats, "wait_duration_ms"), false),
+		)
+	}
+
+	// Pooler waits are time queued in PgBouncer, included in execution latency
+	_, dok = direct.DBStats["pooler_wait_time_ms"]
+	_, nok = nfs.DBStats["pooler_wait_time_ms"]
+	if dok || nok {
+		c.Rows = append(c.Rows,
+			row("pooler_wait_time_ms", "Pooler wait time", UnitLatency, statFloat(direct.DBStats, "pooler_wait_time_ms"), statFloat(nfs.DBStats, "pooler_wait_time_ms"), false),
+		)
+	}
+
+	// Durability failures are committed rows that went missing, counted apart from errors
+	if direct.Durability != nil || nfs.Durability != nil {
+		c.Rows = append(c.Rows,
+			row("missing_committed_rows", "Missing committed rows", UnitCount, missingRows(direct.Durability), missingRows(nfs.Durability), false),
+		)
+	}
+
+	// Failed attempts are time lost to operations that had to be retried
+	if dm.Failed != nil || nm.Failed != nil {
+		c.Rows = append(c.Rows,
+			latencyRow("failed_attempt_time_ms", "Failed attempt time", failedTime(dm.Failed), failedTime(nm.Failed)),
+		)
+	}
+
+	// Phase streams split batch latency into connection wait and execution, and
+	// execution into writing and committing
+	for _, phase := range []struct{ key, name string }{
+		{benchmark.PhaseConnAcquire, "P99 connection wait"},
+		{benchmark.PhaseExecute, "P99 execution"},
+		{benchmark.PhaseWrite, "P99 write"},
+		{benchmark.PhaseCommit, "P99 commit"},
+	} {
+		d, dok := dm.Phases[phase.key]
+		n, nok := nm.Phases[phase.key]
+		if dok || nok {
+			c.Rows = append(c.Rows, latencyRow("p99_"+phase.key+"_ms", phase.name, d.P99Latency, n.P99Latency))
+		}
+	}
+
+	return c
+}
+
+// failedTime returns the total time of failed attempts
+func failedTime(f *metrics.FailedLatency) time.Duration {
+	if f == nil {
+		return 0
+	}
+	return f.TotalTime
+}
+
+// missingRows returns the committed rows a durability check found missing
+func missingRows(d *benchmark.DurabilityCheck) float64 {
+	if d == nil {
+		return 0
+	}
+	return float64(d.MissingRows)
+}
+
+func row(key, name string, unit Unit, direct, nfs float64, higherIsBetter bool) Row {
+	return Row{
+		Key:             key,
+		Metric:          name,
+		Unit:            unit,
+		Direct:          direct,
+		NFS:             nfs,
+		OverheadPercent: benchmark.GetOverheadPercent(direct, nfs),
+		HigherIsBetter:  higherIsBetter,
+	}
+}
+
+// statFloat reads a numeric database stat decoded from JSON
+func statFloat(stats map[string]interface{}, key string) float64 {
+	if v, ok := stats[key].(float64); ok {
+		return v
+	}
+	return 0
+}
+
+// formatValue renders a row value for display
+func formatValue(unit Unit, v float64) string {
+	switch unit {
+	case UnitOpsPerSec:
+		return fmt.Sprintf("%.2f", v)
+	case UnitCount:
+		return fmt.Sprintf("%.0f", v)
+	case UnitLatency:
+		return fmt.Sprintf("%.2f ms", v)
+	case UnitPercent:
+		return fmt.Sprintf("%.2f%%", v)
+	case UnitBytes:
+		return database.FormatBytes(int64(v))
+	case UnitMBPerSec:
+		return fmt.Sprintf("%.2f MB/s", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// formatDelta renders the NFS-vs-direct change, or "-" when direct is zero
+func formatDelta(r Row) string {
+	if r.Direct == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", r.OverheadPercent)
+}
+
+func (c *Comparison) title() string {
+	if c.MountOption != "" {
+		return fmt.Sprintf("%s / %s: NFS (%s) vs Direct Storage", c.Database, c.Scenario, c.MountOption)
+	}
+	return fmt.Sprintf("%s / %s: NFS vs Direct Storage", c.Database, c.Scenario)
+}
+
+// WriteText prints the comparison as an aligned terminal table
+func (c *Comparison) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, c.title())
+	fmt.Fprintln(w, strings.Repeat("=", len(c.title())))
+	fmt.Fprintf(w, "Source: %s\n\n", c.Source)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Metric\tDirect\tNFS\tNFS vs Direct\t")
+	fmt.Fprintln(tw, "------\t------\t---\t-------------\t")
+	for _, r := range c.Rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", r.Metric, formatValue(r.Unit, r.Direct), formatValue(r.Unit, r.NFS), formatDelta(r))
+	}
+	return tw.Flush()
+}
+
+// WriteMarkdown prints the comparison as a GitHub-flavored markdown table
+func (c *Comparison) WriteMarkdown(w io.Writer) error {
+	fmt.Fprintf(w, "### %s\n\n", c.title())
+	fmt.Fprintf(w, "Source: `%s`\n\n", c.Source)
+	fmt.Fprintln(w, "| Metric | Direct | NFS | NFS vs Direct |")
+	fmt.Fprintln(w, "|---|---:|---:|---:|")
+	for _, r := range c.Rows {
+		fmt.Fprintf(w, "| %s | %s | %s | %s |\n", r.Metric, formatValue(r.Unit, r.Direct), formatValue(r.Unit, r.NFS), formatDelta(r))
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
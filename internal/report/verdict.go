@@ -0,0 +1,192 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// Significance is the outcome of Welch's t-test on the mean operation latency of the
+// direct and NFS results
+type Significance struct {
+	PValue      float64
+	Significant bool // PValue is below the threshold the test was run with
+}
+
+// LatencySignificance tests whether the direct and NFS mean operation latencies differ,
+// treating each operation as a sample. It returns nil when either result has fewer than
+// minSamples operations, since the test means little on a handful of samples.
+// Operations in a run are not fully independent, so borderline p-values deserve a
+// repeat rather than trust.
+func (c *Comparison) LatencySignificance(threshold float64, minSamples int) *Significance {
+	dm, nm := c.Direct.Metrics, c.NFS.Metrics
+	if dm == nil || nm == nil || dm.TotalOperations < 2 || nm.TotalOperations < 2 ||
+		dm.TotalOperations < int64(minSamples) || nm.TotalOperations < int64(minSamples) {
+		return nil
+	}
+	p := welchPValue(latencySample(dm), latencySample(nm))
+	return &Significance{PValue: p, Significant: p < threshold}
+}
+
+// sample summarizes a set of observations by their count, mean, and standard deviation
+type sample struct {
+	n, mean, stddev float64
+}
+
+func latencySample(m *metrics.Results) sample {
+	return sample{n: float64(m.TotalOperations), mean: float64(m.AverageLatency), stddev: float64(m.StdDevLatency)}
+}
+
+// welchPValue returns the two-sided p-value of Welch's t-test for the means of a and b
+func welchPValue(a, b sample) float64 {
+	va, vb := a.stddev*a.stddev/a.n, b.stddev*b.stddev/b.n
+	if va+vb == 0 {
+		if a.mean == b.mean {
+			return 1
+		}
+		return 0
+	}
+	t := (a.mean - b.mean) / math.Sqrt(va+vb)
+	df := (va + vb) * (va + vb) / (va*va/(a.n-1) + vb*vb/(b.n-1))
+	return regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// regularizedIncompleteBeta computes I_x(a, b) with the continued fraction of
+// Numerical Recipes (betacf), which converges quickly for x < (a+1)/(a+b+2)
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1.0; m <= maxIterations; m++ {
+		// Even step
+		aa := m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// Odd step
+		aa = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
+
+// Verdict sums up the comparison in one sentence: how NFS throughput and p95 latency
+// compare to direct storage, e.g. "NFS is 23.4% slower on throughput and adds 41ms to p95
+// latency for heavy_inserts". With a significance result, the sentence says whether
+// the difference is statistically significant.
+func (c *Comparison) Verdict(significance *Significance) string {
+	var throughput, latency string
+	for _, r := range c.Rows {
+		switch r.Key {
+		case "throughput":
+			switch verdict(r) {
+			case VerdictSimilar:
+				throughput = fmt.Sprintf("NFS matches direct throughput (within %.0f%%)", similarThresholdPercent)
+			case VerdictWorse:
+				throughput = fmt.Sprintf("NFS is %.1f%% slower on throughput", -r.OverheadPercent)
+			default:
+				throughput = fmt.Sprintf("NFS is %.1f%% faster on throughput", r.OverheadPercent)
+			}
+		case "p95_latency_ms":
+			delta := r.NFS - r.Direct
+			switch verdict(r) {
+			case VerdictSimilar:
+				latency = "leaves p95 latency unchanged"
+			case VerdictWorse:
+				latency = fmt.Sprintf("adds %s to p95 latency", formatMillis(delta))
+			default:
+				latency = fmt.Sprintf("cuts %s from p95 latency", formatMillis(-delta))
+			}
+		}
+	}
+
+	sentence := fmt.Sprintf("%s and %s for %s", throughput, latency, c.name())
+	if significance != nil {
+		qualifier := "statistically significant"
+		if !significance.Significant {
+			qualifier = "not statistically significant"
+		}
+		sentence += fmt.Sprintf(" (%s, %s)", qualifier, formatPValue(significance.PValue))
+	}
+	return sentence
+}
+
+// name identifies the compared scenario run in sentences, with its mount option variant
+// and insert mode when it has them
+func (c *Comparison) name() string {
+	var qualifiers []string
+	if c.MountOption != "" {
+		qualifiers = append(qualifiers, c.MountOption)
+	}
+	if c.Direct.InsertMode != "" {
+		qualifiers = append(qualifiers, strings.ToUpper(c.Direct.InsertMode))
+	}
+	if len(qualifiers) == 0 {
+		return c.Scenario
+	}
+	return fmt.Sprintf("%s (%s)", c.Scenario, strings.Join(qualifiers, ", "))
+}
+
+// formatMillis renders a positive latency difference in milliseconds, with more
+// precision below 10ms
+func formatMillis(ms float64) string {
+	if ms >= 10 {
+		return fmt.Sprintf("%.0fms", ms)
+	}
+	return fmt.Sprintf("%.2fms", ms)
+}
+
+func formatPValue(p float64) string {
+	if p < 0.001 {
+		return "p<0.001"
+	}
+	return fmt.Sprintf("p=%.3f", p)
+}
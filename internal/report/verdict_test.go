@@ -0,0 +1,49 @@
+package report
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+func TestWelchPValue(t *testing.T) {
+	for _, tc := range []struct {
+		a, b sample
+		want float64
+	}{
+		{sample{n: 10, mean: 20, stddev: 4}, sample{n: 10, mean: 24, stddev: 4}, 0.0382},
+		{sample{n: 1e6, mean: 0, stddev: 1000}, sample{n: 1e6, mean: 1.96 * math.Sqrt2, stddev: 1000}, 0.05},
+		{sample{n: 50, mean: 5, stddev: 2}, sample{n: 50, mean: 5, stddev: 3}, 1},
+	} {
+		if got := welchPValue(tc.a, tc.b); math.Abs(got-tc.want) > 0.001 {
+			t.Errorf("welchPValue(%+v, %+v) = %.4f, want %.4f", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestVerdict(t *testing.T) {
+	direct := &StorageResult{Name: "heavy_inserts", Database: "postgresql", Metrics: &metrics.Results{
+		OperationsPerSecond: 1000, P95Latency: 10 * time.Millisecond, TotalOperations: 1000, AverageLatency: 8 * time.Millisecond, StdDevLatency: 2 * time.Millisecond}}
+	nfs := &StorageResult{Name: "heavy_inserts", Database: "postgresql", MountOption: "sync_mode", Metrics: &metrics.Results{
+		OperationsPerSecond: 766, P95Latency: 51 * time.Millisecond, TotalOperations: 766, AverageLatency: 12 * time.Millisecond, StdDevLatency: 5 * time.Millisecond}}
+	c := Build("test", direct, nfs)
+
+	significance := c.LatencySignificance(0.05, 100)
+	if significance == nil || !significance.Significant {
+		t.Fatalf("Expected a significant difference, got %+v", significance)
+	}
+	want := "NFS is 23.4% slower on throughput and adds 41ms to p95 latency for heavy_inserts (sync_mode) (statistically significant, p<0.001)"
+	if got := c.Verdict(significance); got != want {
+		t.Errorf("Verdict() = %q, want %q", got, want)
+	}
+
+	if c.LatencySignificance(0.05, 1000) != nil {
+		t.Error("Expected no significance result below minimum_samples")
+	}
+	nfs.Metrics.OperationsPerSecond, nfs.Metrics.P95Latency = 1005, 10*time.Millisecond
+	if got, want := Build("test", direct, nfs).Verdict(nil), "NFS matches direct throughput (within 1%) and leaves p95 latency unchanged for heavy_inserts (sync_mode)"; got != want {
+		t.Errorf("Verdict() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,228 @@
+package resultsdb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+// schema creates the results tables if they do not exist yet. A row in runs is one
+// invocation of the benchmark; results holds one row per scenario and storage label of a
+// run, keyed by the run's start time, database, scenario, storage type, mount option, and
+// insert mode;
+// result_stats holds the numeric DBStats of each result.
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id          INTEGER PRIMARY KEY,
+	started_at  TEXT NOT NULL UNIQUE,
+	output_dir  TEXT NOT NULL,
+	config_json TEXT
+);
+CREATE TABLE IF NOT EXISTS results (
+	id               INTEGER PRIMARY KEY,
+	run_id           INTEGER NOT NULL REFERENCES runs(id),
+	started_at       TEXT NOT NULL,
+	database         TEXT NOT NULL,
+	scenario         TEXT NOT NULL,
+	storage_type     TEXT NOT NULL,
+	mount_option     TEXT NOT NULL DEFAULT '',
+	insert_mode      TEXT NOT NULL DEFAULT '',
+	success          INTEGER NOT NULL,
+	error            TEXT,
+	duration_ns      INTEGER,
+	total_operations INTEGER,
+	ops_per_sec      REAL,
+	error_count      INTEGER,
+	error_rate       REAL,
+	avg_latency_ns   INTEGER,
+	p50_latency_ns   INTEGER,
+	p90_latency_ns   INTEGER,
+	p95_latency_ns   INTEGER,
+	p99_latency_ns   INTEGER,
+	p999_latency_ns  INTEGER,
+	max_latency_ns   INTEGER,
+	parameters_json  TEXT,
+	UNIQUE (started_at, database, scenario, storage_type, mount_option, insert_mode)
+);
+CREATE TABLE IF NOT EXISTS result_stats (
+	result_id INTEGER NOT NULL REFERENCES results(id),
+	name      TEXT NOT NULL,
+	value     REAL NOT NULL,
+	PRIMARY KEY (result_id, name)
+);
+CREATE INDEX IF NOT EXISTS results_scenario ON results (database, scenario, storage_type, started_at);
+`
+
+// timeFormat stores times as text that sorts chronologically
+const timeFormat = time.RFC3339Nano
+
+// DB is a SQLite file that results of every run are appended to, separate from the
+// databases being benchmarked, so trends can be queried with SQL
+type DB struct {
+	db *sql.DB
+}
+
+// Run describes one invocation of the benchmark
+type Run struct {
+	StartedAt time.Time
+	OutputDir string
+	Config    map[string]interface{} // effective config, stored as JSON
+}
+
+// Result is one scenario result of a run on one storage label
+type Result struct {
+	Database    string
+	Scenario    string
+	StorageType string
+	MountOption string
+	InsertMode  string // set when the scenario ran several insert modes
+	Success     bool
+	Error       error
+	Duration    time.Duration
+	Metrics     *metrics.Results // nil when the scenario failed before measuring
+	Parameters  map[string]interface{}
+	DBStats     map[string]interface{} // numeric entries are stored in result_stats
+}
+
+// Open opens or creates the results file and its tables
+func Open(path string) (*DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open results database: %w", err)
+	}
+	// SQLite allows one writer; a single connection serializes concurrent tasks
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create results tables in %s: %w", path, err)
+	}
+	return &DB{db: db}, nil
+}
+
+// Close closes the results file
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// AddRun records a run and returns its ID for AddResult
+func (d *DB) AddRun(run Run) (int64, error) {
+	config, err := json.Marshal(run.Config)
+	if err != nil {
+		return 0, err
+	}
+	res, err := d.db.Exec(`INSERT INTO runs (started_at, output_dir, config_json) VALUES (?, ?, ?)`,
+		run.StartedAt.UTC().Format(timeFormat), run.OutputDir, string(config))
+	if err != nil {
+		return 0, fmt.Errorf("failed to record run: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// FindRun returns the ID of the latest run recorded with the given output directory,
+// or false if there is none
+func (d *DB) FindRun(outputDir string) (int64, bool, error) {
+	var id int64
+	err := d.db.QueryRow(`SELECT id FROM runs WHERE output_dir = ? ORDER BY id DESC LIMIT 1`, outputDir).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to look up run: %w", err)
+	}
+	return id, true, nil
+}
+
+// AddResult records a scenario result of a run, replacing an earlier row for the same
+// scenario, storage label, and insert mode
+func (d *DB) AddResult(runID int64, result Result) error {
+	parameters, err := json.Marshal(result.Parameters)
+	if err != nil {
+		return err
+	}
+	var errText sql.NullString
+	if result.Error != nil {
+		errText = sql.NullString{String: result.Error.Error(), Valid: true}
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var startedAt string
+	if err := tx.QueryRow(`SELECT started_at FROM runs WHERE id = ?`, runID).Scan(&startedAt); err != nil {
+		return fmt.Errorf("unknown run %d: %w", runID, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM result_stats WHERE result_id IN (
+		SELECT id FROM results WHERE started_at = ? AND database = ? AND scenario = ? AND storage_type = ? AND mount_option = ? AND insert_mode = ?)`,
+		startedAt, result.Database, result.Scenario, result.StorageType, result.MountOption, result.InsertMode); err != nil {
+		return err
+	}
+
+	m := result.Metrics
+	if m == nil {
+		m = &metrics.Results{}
+	}
+	res, err := tx.Exec(`INSERT OR REPLACE INTO results (
+		run_id, started_at, database, scenario, storage_type, mount_option, insert_mode, success, error,
+		duration_ns, total_operations, ops_per_sec, error_count, error_rate,
+		avg_latency_ns, p50_latency_ns, p90_latency_ns, p95_latency_ns, p99_latency_ns, p999_latency_ns, max_latency_ns,
+		parameters_json
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		runID, startedAt, result.Database, result.Scenario, result.StorageType, result.MountOption, result.InsertMode, result.Success, errText,
+		int64(result.Duration), m.TotalOperations, m.OperationsPerSecond, m.ErrorCount, m.ErrorRate,
+		int64(m.AverageLatency), int64(m.P50Latency), int64(m.P90Latency), int64(m.P95Latency), int64(m.P99Latency), int64(m.P999Latency), int64(m.MaxLatency),
+		string(parameters))
+	if err != nil {
+		return fmt.Errorf("failed to record result: %w", err)
+	}
+	resultID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(result.DBStats))
+	for name := range result.DBStats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := numericStat(result.DBStats[name])
+		if !ok {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO result_stats (result_id, name, value) VALUES (?, ?, ?)`, resultID, name, value); err != nil {
+			return fmt.Errorf("failed to record stat %s: %w", name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// numericStat returns a DBStats value as a number, or false for text and nested values
+func numericStat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case time.Duration:
+		return float64(n), true
+	}
+	return 0, false
+}
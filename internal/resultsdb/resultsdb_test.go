@@ -0,0 +1,118 @@
+package resultsdb
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/l22io/nfsvsdirectbench/internal/metrics"
+)
+
+func TestAppendRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.db")
+
+	// Two runs appended to the same file, reopened in between like separate invocations
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, ops := range []float64{1000, 800} {
+		db, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		runID, err := db.AddRun(Run{StartedAt: start.Add(time.Duration(i) * time.Hour), OutputDir: "results"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := Result{
+			Database:    "postgresql",
+			Scenario:    "heavy_inserts",
+			StorageType: "nfs",
+			Success:     true,
+			Duration:    time.Minute,
+			Metrics:     &metrics.Results{OperationsPerSecond: ops, P99Latency: 15 * time.Millisecond},
+			Parameters:  map[string]interface{}{"batch_size": 1000},
+			DBStats:     map[string]interface{}{"table_size_bytes": int64(4096), "version": "16.1"},
+		}
+		if err := db.AddResult(runID, result); err != nil {
+			t.Fatal(err)
+		}
+		// Recording the same result again replaces it
+		if err := db.AddResult(runID, result); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.AddResult(runID, Result{Database: "postgresql", Scenario: "heavy_inserts", StorageType: "direct", Error: errors.New("connection refused")}); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.db.Query(`SELECT started_at, ops_per_sec, p99_latency_ns FROM results WHERE storage_type = 'nfs' ORDER BY started_at`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []float64
+	for rows.Next() {
+		var startedAt string
+		var ops float64
+		var p99 int64
+		if err := rows.Scan(&startedAt, &ops, &p99); err != nil {
+			t.Fatal(err)
+		}
+		if p99 != int64(15*time.Millisecond) {
+			t.Errorf("Expected p99 of 15ms in ns, got %d", p99)
+		}
+		got = append(got, ops)
+	}
+	if len(got) != 2 || got[0] != 1000 || got[1] != 800 {
+		t.Errorf("Expected nfs throughput 1000 then 800, got %v", got)
+	}
+
+	var stats int
+	if err := db.db.QueryRow(`SELECT COUNT(*) FROM result_stats WHERE name = 'table_size_bytes' AND value = 4096`).Scan(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats != 2 {
+		t.Errorf("Expected one numeric stat per nfs result, got %d", stats)
+	}
+
+	var failed string
+	if err := db.db.QueryRow(`SELECT error FROM results WHERE storage_type = 'direct' AND success = 0 LIMIT 1`).Scan(&failed); err != nil {
+		t.Fatal(err)
+	}
+	if failed != "connection refused" {
+		t.Errorf("Expected the failure to be recorded, got %q", failed)
+	}
+}
+
+func TestFindRun(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "results.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first, err := db.AddRun(Run{StartedAt: start, OutputDir: "results/run_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.AddRun(Run{StartedAt: start.Add(time.Hour), OutputDir: "results/run_2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if id, ok, err := db.FindRun("results/run_1"); err != nil || !ok || id != first {
+		t.Errorf("Expected run %d, got %d, %v, %v", first, id, ok, err)
+	}
+	if _, ok, err := db.FindRun("results/run_3"); err != nil || ok {
+		t.Errorf("Expected no run, got %v, %v", ok, err)
+	}
+}
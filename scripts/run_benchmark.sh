@@ -17,6 +17,7 @@ DATABASES="postgresql"
 SCENARIOS="heavy_inserts"
 OUTPUT_DIR=""
 VERBOSE=false
+QUIET=false
 CLEANUP_ONLY=false
 NO_CLEANUP=false
 
@@ -47,6 +48,7 @@ Options:
     -s, --scenarios SCENARIOS    Comma-separated list of scenarios (default: heavy_inserts)
     -o, --output OUTPUT_DIR      Output directory for results
     -v, --verbose               Enable verbose output
+    -q, --quiet                 Only show warnings, errors, and the final summary
     -c, --cleanup-only          Only cleanup running services and exit
     -n, --no-cleanup            Don't cleanup services after benchmark (for debugging)
     -h, --help                  Show this help message
@@ -241,6 +243,10 @@ run_benchmark() {
     if [ "$VERBOSE" = true ]; then
         cmd="$cmd --verbose"
     fi
+
+    if [ "$QUIET" = true ]; then
+        cmd="$cmd --quiet"
+    fi
     
     print_status "Executing: $cmd"
     
@@ -319,6 +325,10 @@ while [[ $# -gt 0 ]]; do
             VERBOSE=true
             shift
             ;;
+        -q|--quiet)
+            QUIET=true
+            shift
+            ;;
         -c|--cleanup-only)
             CLEANUP_ONLY=true
             shift